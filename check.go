@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/app"
+	"tappmanager/internal/services"
+)
+
+// Exit codes of a Nagios plugin
+const (
+	checkOK       = 0
+	checkWarning  = 1
+	checkCritical = 2
+	checkUnknown  = 3
+)
+
+// checkStates names the exit codes in the plugin output
+var checkStates = [...]string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkOptions are the thresholds of the check subcommand
+type checkOptions struct {
+	name       string
+	filter     string
+	minCount   int
+	maxCount   int
+	warnCPU    float64
+	maxCPU     float64
+	warnMemory float64
+	maxMemory  float64
+}
+
+// runCheck runs the check subcommand as a Nagios or Icinga plugin: it counts
+// the processes matching the filters, sums their CPU and memory usage,
+// prints one status line with perfdata and returns the plugin exit code
+func runCheck(application *app.App, args []string, out io.Writer) int {
+	var opts checkOptions
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.StringVar(&opts.name, "name", "", "only count processes with exactly this name")
+	flags.StringVar(&opts.filter, "filter", "", `filter expression, e.g. "user:www-data cpu:5"`)
+	flags.IntVar(&opts.minCount, "min-count", 0, "critical when fewer processes match")
+	flags.IntVar(&opts.maxCount, "max-count", -1, "critical when more processes match")
+	flags.Float64Var(&opts.warnCPU, "warn-cpu", 0, "warning when the matching processes use more CPU %")
+	flags.Float64Var(&opts.maxCPU, "max-cpu", 0, "critical when the matching processes use more CPU %")
+	flags.Float64Var(&opts.warnMemory, "warn-memory", 0, "warning when the matching processes use more memory %")
+	flags.Float64Var(&opts.maxMemory, "max-memory", 0, "critical when the matching processes use more memory %")
+	if err := flags.Parse(args); err != nil {
+		return checkUnknown
+	}
+
+	filter, err := services.ParseFilterExpr(opts.filter)
+	if err != nil {
+		fmt.Fprintf(out, "PROCS UNKNOWN - %v\n", err)
+		return checkUnknown
+	}
+	// Services usually run as root or a daemon user, so unlike in the UI
+	// system processes count unless the filter says otherwise
+	if !strings.Contains(opts.filter, "system:") {
+		filter.ShowSystem = true
+	}
+
+	processService := services.NewProcessService(application.GetStorage())
+	processes, err := processService.GetProcesses()
+	if err != nil {
+		fmt.Fprintf(out, "PROCS UNKNOWN - %v\n", err)
+		return checkUnknown
+	}
+	processes = processService.FilterProcesses(processes, filter)
+
+	var count int
+	var cpu, memory float64
+	for _, proc := range processes {
+		// The check itself would match filters on its own name
+		if proc.PID == int32(os.Getpid()) || (opts.name != "" && proc.Name != opts.name) {
+			continue
+		}
+		count++
+		cpu += proc.CPU
+		memory += proc.Memory
+	}
+
+	state, status := evaluateCheck(opts, count, cpu, memory)
+	fmt.Fprintf(out, "PROCS %s - %s | %s\n", checkStates[state], status, checkPerfdata(opts, count, cpu, memory))
+	return state
+}
+
+// evaluateCheck compares the totals with the thresholds and returns the
+// worst state along with the text describing it
+func evaluateCheck(opts checkOptions, count int, cpu, memory float64) (int, string) {
+	subject := "processes"
+	if count == 1 {
+		subject = "process"
+	}
+	if opts.name != "" {
+		subject += " named " + opts.name
+	}
+
+	state := checkOK
+	var problems []string
+	raise := func(level int, problem string) {
+		state = max(state, level)
+		problems = append(problems, problem)
+	}
+
+	if count < opts.minCount {
+		raise(checkCritical, fmt.Sprintf("expected at least %d", opts.minCount))
+	}
+	if opts.maxCount >= 0 && count > opts.maxCount {
+		raise(checkCritical, fmt.Sprintf("expected at most %d", opts.maxCount))
+	}
+	switch {
+	case opts.maxCPU > 0 && cpu > opts.maxCPU:
+		raise(checkCritical, fmt.Sprintf("CPU above %g%%", opts.maxCPU))
+	case opts.warnCPU > 0 && cpu > opts.warnCPU:
+		raise(checkWarning, fmt.Sprintf("CPU above %g%%", opts.warnCPU))
+	}
+	switch {
+	case opts.maxMemory > 0 && memory > opts.maxMemory:
+		raise(checkCritical, fmt.Sprintf("memory above %g%%", opts.maxMemory))
+	case opts.warnMemory > 0 && memory > opts.warnMemory:
+		raise(checkWarning, fmt.Sprintf("memory above %g%%", opts.warnMemory))
+	}
+
+	status := fmt.Sprintf("%d %s using %.1f%% CPU and %.1f%% memory", count, subject, cpu, memory)
+	if len(problems) > 0 {
+		status += " (" + strings.Join(problems, ", ") + ")"
+	}
+	return state, status
+}
+
+// checkPerfdata formats the totals as Nagios performance data, with the
+// thresholds as warning and critical ranges
+func checkPerfdata(opts checkOptions, count int, cpu, memory float64) string {
+	countRange := ""
+	if opts.minCount > 0 {
+		countRange = strconv.Itoa(opts.minCount) + ":"
+	}
+	if opts.maxCount >= 0 {
+		if countRange == "" {
+			countRange = "0:"
+		}
+		countRange += strconv.Itoa(opts.maxCount)
+	}
+
+	return fmt.Sprintf("procs=%d;;%s;0; cpu=%.1f%%;%s;%s;0; memory=%.1f%%;%s;%s;0;100",
+		count, countRange,
+		cpu, perfThreshold(opts.warnCPU), perfThreshold(opts.maxCPU),
+		memory, perfThreshold(opts.warnMemory), perfThreshold(opts.maxMemory))
+}
+
+// perfThreshold formats a threshold for perfdata, leaving unset ones empty
+func perfThreshold(value float64) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
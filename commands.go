@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"tappmanager/internal/app"
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+	"tappmanager/internal/storage"
+	"tappmanager/internal/version"
+)
+
+// runCommand runs a subcommand given on the command line instead of the UI
+func runCommand(application *app.App, args []string) error {
+	storage := application.GetStorage()
+
+	switch args[0] {
+	case "export-state":
+		path := ""
+		if len(args) > 1 {
+			path = args[1]
+		}
+		path, err := storage.ExportState(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("State exported to %s\n", path)
+
+	case "import-state":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: tappmanager import-state <archive>")
+		}
+		if err := storage.ImportState(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("State imported from %s\n", args[1])
+
+	case "config":
+		if len(args) < 2 || args[1] != "show" {
+			return fmt.Errorf("usage: tappmanager config show [json|yaml|toml]")
+		}
+		return showConfig(application, args[2:])
+
+	case "version":
+		check := len(args) > 1 && args[1] == "--check"
+		return showVersion(check || application.GetConfig().CheckUpdates)
+
+	case "status":
+		return showStatus()
+
+	case "daemon":
+		return runDaemon(application)
+
+	case "facts":
+		return showFacts(application, args[1:])
+
+	case "report":
+		return runReport(application, args[1:])
+
+	case "check":
+		// Monitoring systems read the state from the exit code
+		os.Exit(runCheck(application, args[1:], os.Stdout))
+
+	case "kill":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: tappmanager kill <pid>")
+		}
+		pid, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid pid: %s", args[1])
+		}
+		return killProcess(application, int32(pid))
+
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+
+	return nil
+}
+
+// showConfig prints the effective configuration, after environment overrides,
+// in the config file's format or the one given
+func showConfig(application *app.App, args []string) error {
+	path, format := application.GetStorage().ConfigFile()
+	if len(args) > 0 {
+		format = args[0]
+		if err := storage.ValidateConfigFormat(format); err != nil {
+			return err
+		}
+	}
+
+	data, err := storage.MarshalConfig(redactConfig(application.GetConfig()), format)
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout parseable by sending the source to stderr
+	fmt.Fprintf(os.Stderr, "# Effective configuration from %s with TAPPMANAGER_* overrides, credentials redacted\n", path)
+	fmt.Println(string(data))
+	return nil
+}
+
+// redacted replaces credentials in printed configs
+const redacted = "REDACTED"
+
+// redactConfig returns a copy of config with the MQTT password, the Grafana
+// token and the headers of HTTP exports replaced, so the output can be
+// shared
+func redactConfig(config *models.AppConfig) *models.AppConfig {
+	copied := *config
+	if copied.MQTT.Password != "" {
+		copied.MQTT.Password = redacted
+	}
+	if copied.Grafana.Token != "" {
+		copied.Grafana.Token = redacted
+	}
+
+	copied.ExportSchedules = slices.Clone(config.ExportSchedules)
+	for i, schedule := range copied.ExportSchedules {
+		if len(schedule.Destination.Headers) == 0 {
+			continue
+		}
+		headers := make(map[string]string, len(schedule.Destination.Headers))
+		for name := range schedule.Destination.Headers {
+			headers[name] = redacted
+		}
+		copied.ExportSchedules[i].Destination.Headers = headers
+	}
+	return &copied
+}
+
+// showFacts prints the facts document as JSON, watching the process names
+// given as arguments as well as those in the config
+func showFacts(application *app.App, names []string) error {
+	watch := append(slices.Clone(application.GetConfig().Watch), names...)
+	facts, err := services.CollectFacts(services.NewProcessService(application.GetStorage()), services.NewSystemService(), watch)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(facts)
+}
+
+// showVersion prints the build information and, with check set, whether a
+// newer release is available
+func showVersion(check bool) error {
+	info := version.Get()
+	fmt.Printf("tappmanager %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Printf("  commit:   %s\n", info.Commit)
+	}
+	if info.BuildDate != "" {
+		fmt.Printf("  built:    %s\n", info.BuildDate)
+	}
+	fmt.Printf("  go:       %s\n", info.GoVersion)
+	fmt.Printf("  platform: %s\n", info.Platform)
+
+	if !check {
+		return nil
+	}
+
+	release, err := services.NewUpdateService().CheckForUpdate(context.Background(), info.Version)
+	if err != nil {
+		return err
+	}
+	if release.UpdateAvailable {
+		fmt.Printf("\nUpdate available: %s\n%s\n", release.Version, release.URL)
+	} else {
+		fmt.Printf("\nLatest release: %s\n", release.Version)
+	}
+	return nil
+}
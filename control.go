@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"tappmanager/internal/app"
+	"tappmanager/internal/control"
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+	"tappmanager/internal/version"
+)
+
+// controlHandler answers control socket requests using this instance's services
+type controlHandler struct {
+	mode           string
+	startedAt      time.Time
+	processService *services.ProcessService
+	historyService *services.HistoryService
+	release        func() // called when a UI gives the socket up to a daemon
+}
+
+// Status describes this instance
+func (h *controlHandler) Status() (*control.Status, error) {
+	processes, err := h.processService.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return &control.Status{
+		PID:       os.Getpid(),
+		Mode:      h.mode,
+		Version:   version.Get().Version,
+		StartedAt: h.startedAt,
+		Processes: len(processes),
+		History:   h.historyService.GetHistoryStats(),
+	}, nil
+}
+
+// Kill kills pid on behalf of another invocation
+func (h *controlHandler) Kill(pid int32) error {
+	return h.processService.KillProcess(pid)
+}
+
+// Processes returns the most recently collected processes, collecting them
+// if nothing has been collected yet. Only a daemon supplies them, so that a
+// UI never attaches to another UI.
+func (h *controlHandler) Processes() ([]*models.ProcessInfo, error) {
+	if h.mode != control.ModeDaemon {
+		return nil, errNotDaemon
+	}
+	if processes := h.processService.LastProcesses(); processes != nil {
+		return processes, nil
+	}
+	return h.processService.GetProcesses()
+}
+
+// LoadHistory returns the load samples taken after since
+func (h *controlHandler) LoadHistory(since time.Time) []models.LoadSample {
+	samples := h.historyService.GetLoadHistory()
+	i := 0
+	for i < len(samples) && !samples[i].Timestamp.After(since) {
+		i++
+	}
+	return samples[i:]
+}
+
+// Release gives the socket up to a daemon; a daemon keeps it
+func (h *controlHandler) Release() error {
+	if h.mode == control.ModeDaemon {
+		return fmt.Errorf("a daemon is listening")
+	}
+	h.release()
+	return nil
+}
+
+// startControlServer listens on the control socket if enabled. A nil server
+// is returned when the socket is disabled, and an error when another
+// instance is listening on it.
+func startControlServer(application *app.App, mode string, processService *services.ProcessService, historyService *services.HistoryService, release func()) (*control.Server, error) {
+	if !application.GetConfig().ControlSocket {
+		return nil, nil
+	}
+	handler := &controlHandler{
+		mode:           mode,
+		startedAt:      time.Now(),
+		processService: processService,
+		historyService: historyService,
+		release:        release,
+	}
+	return control.Listen(app.ControlSocketPath(), handler)
+}
+
+// errNotDaemon is returned when processes are asked of an instance other
+// than a daemon
+var errNotDaemon = errors.New("not a daemon")
+
+// errServing is returned by daemonLink while the UI holds the control socket
+var errServing = errors.New("this instance holds the control socket")
+
+// daemonLink attaches a UI to a running daemon. While no daemon answers,
+// the UI serves the control socket itself, and gives it up as soon as a
+// daemon asks for it, attaching to that daemon from then on.
+type daemonLink struct {
+	application    *app.App
+	client         *control.Client
+	processService *services.ProcessService
+	historyService *services.HistoryService
+
+	mu      sync.Mutex
+	server  *control.Server
+	retryAt time.Time // when to try taking the socket again
+}
+
+// linkToDaemon makes the services use the processes and load history of a
+// daemon whenever one answers on the control socket, and otherwise serves
+// the socket. It returns the status of the daemon running at startup, or
+// nil when there is none.
+func linkToDaemon(application *app.App, processService *services.ProcessService, historyService *services.HistoryService) (*daemonLink, *control.Status) {
+	link := &daemonLink{
+		application:    application,
+		client:         control.NewClient(app.ControlSocketPath()),
+		processService: processService,
+		historyService: historyService,
+	}
+
+	status, err := link.client.Status()
+	if err == nil && status.Mode != control.ModeDaemon {
+		err = fmt.Errorf("instance %d is not a daemon", status.PID)
+	}
+	if err != nil {
+		status = nil
+		if err := link.listen(); err != nil {
+			log.Printf("Control socket disabled: %v", err)
+		}
+	}
+
+	processService.SetRemote(link, status != nil)
+	historyService.SetRemote(link)
+	return link, status
+}
+
+// Processes returns the daemon's latest processes. When no daemon answers,
+// the UI takes the socket so that other invocations reach it instead.
+func (l *daemonLink) Processes() ([]*models.ProcessInfo, error) {
+	if l.serving() {
+		return nil, errServing
+	}
+	processes, err := l.client.Processes()
+	if err != nil {
+		l.listen()
+		return nil, err
+	}
+
+	// Once the daemon answers, the socket is taken as soon as it stops
+	l.mu.Lock()
+	l.retryAt = time.Time{}
+	l.mu.Unlock()
+	return processes, nil
+}
+
+// LoadHistory returns the load samples the daemon took after since
+func (l *daemonLink) LoadHistory(since time.Time) ([]models.LoadSample, error) {
+	if l.serving() || !l.processService.Attached() {
+		return nil, errServing
+	}
+	return l.client.LoadHistory(since)
+}
+
+// Close stops serving the control socket
+func (l *daemonLink) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.server != nil {
+		l.server.Close()
+		l.server = nil
+	}
+}
+
+// serving reports whether the UI holds the control socket
+func (l *daemonLink) serving() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.server != nil
+}
+
+// listen takes the control socket for the UI. After a failed attempt, and
+// after a daemon asked for the socket, it waits controlRetryInterval before
+// trying again, so the UI and the daemon do not take it from each other.
+func (l *daemonLink) listen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.server != nil || time.Now().Before(l.retryAt) {
+		return nil
+	}
+	server, err := startControlServer(l.application, control.ModeTUI, l.processService, l.historyService, l.release)
+	if err != nil {
+		l.retryAt = time.Now().Add(controlRetryInterval)
+		return err
+	}
+	l.server = server
+	return nil
+}
+
+// release forgets the server a daemon asked to give the socket up
+func (l *daemonLink) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.server = nil
+	l.retryAt = time.Now().Add(controlRetryInterval)
+}
+
+// showStatus prints the status of the running instance
+func showStatus() error {
+	path := app.ControlSocketPath()
+	status, err := control.NewClient(path).Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("tappmanager %s running as PID %d (%s)\n", status.Version, status.PID, status.Mode)
+	fmt.Printf("  started:   %s (%s ago)\n", status.StartedAt.Format("2006-01-02 15:04:05"), time.Since(status.StartedAt).Truncate(time.Second))
+	fmt.Printf("  processes: %d\n", status.Processes)
+	if history := status.History; !history.OldestSample.IsZero() {
+		fmt.Printf("  history:   %d load samples since %s\n", history.RawSamples+history.MediumSamples+history.CoarseSamples, history.OldestSample.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("  socket:    %s\n", path)
+	return nil
+}
+
+// killProcess kills pid through the running instance, or directly when none is running
+func killProcess(application *app.App, pid int32) error {
+	err := control.NewClient(app.ControlSocketPath()).Kill(pid)
+	if err == control.ErrNotRunning {
+		err = services.NewProcessService(application.GetStorage()).KillProcess(pid)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Killed process %d\n", pid)
+	return nil
+}
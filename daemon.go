@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"tappmanager/internal/app"
+	"tappmanager/internal/control"
+	"tappmanager/internal/services"
+	"tappmanager/internal/storage"
+	"tappmanager/internal/version"
+)
+
+// defaultCollectInterval is used when the config leaves processes_refresh unset
+const defaultCollectInterval = 2 * time.Second
+
+// controlRetryInterval is how often the daemon tries to take the control
+// socket while another instance holds it
+const controlRetryInterval = 30 * time.Second
+
+// runDaemon runs the collector, history and budget checks without the UI
+// until interrupted or terminated, logging to stderr
+func runDaemon(application *app.App) error {
+	config := application.GetConfig()
+	store := application.GetStorage()
+
+	processService := services.NewProcessService(store)
+	if eventLog := openEventLog(application, processService); eventLog != nil {
+		defer eventLog.Close()
+	}
+	recordEventHistory(application, processService)
+	if publisher := startMQTTPublisher(application, processService); publisher != nil {
+		defer publisher.Stop()
+		log.Printf("Publishing to MQTT broker %s", config.MQTT.Broker)
+	}
+	if annotator := startGrafanaAnnotator(application, processService); annotator != nil {
+		defer annotator.Stop()
+		log.Printf("Pushing annotations to Grafana at %s", config.Grafana.URL)
+	}
+	historyService := services.NewHistoryService()
+	historyService.SetLimits(uint64(max(config.HistoryBudget, 0))*1024, time.Duration(config.HistoryRetention)*time.Second)
+	budgetService := services.NewBudgetService(processService, historyService, config.Budgets)
+	idleService := services.NewIdleService(processService, time.Duration(max(config.IdleThreshold, 0))*time.Second)
+	listenerService := services.NewListenerService(processService, time.Duration(max(config.ListenerInterval, 0))*time.Second)
+	exportScheduler := services.NewExportScheduler(processService, config.ExportSchedules)
+	for _, status := range exportScheduler.Statuses() {
+		if status.NextRun.IsZero() {
+			log.Printf("Export schedule %s is invalid: %s", status.Schedule.Name, status.Error)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	collectInterval := defaultCollectInterval
+	if config.ProcessesRefresh > 0 {
+		collectInterval = time.Duration(config.ProcessesRefresh) * time.Second
+	}
+
+	log.Printf("tappmanager %s daemon started (PID %d)", version.Get().Version, os.Getpid())
+
+	serving := make(chan struct{})
+	go func() {
+		serveControl(ctx, application, processService, historyService)
+		close(serving)
+	}()
+
+	runRecorder(ctx, store, processService, historyService, budgetService, idleService, listenerService, exportScheduler, collectInterval)
+	<-serving
+
+	log.Printf("tappmanager daemon stopped")
+	return nil
+}
+
+// serveControl listens on the control socket until ctx is done. An open UI
+// holding the socket is asked to give it up and attaches to the daemon
+// instead. While another daemon holds it, the daemon runs without it and
+// tries again every controlRetryInterval.
+func serveControl(ctx context.Context, application *app.App, processService *services.ProcessService, historyService *services.HistoryService) {
+	if !application.GetConfig().ControlSocket {
+		return
+	}
+
+	retry := time.NewTicker(controlRetryInterval)
+	defer retry.Stop()
+	for attempt := 0; ; attempt++ {
+		server, err := startControlServer(application, control.ModeDaemon, processService, historyService, nil)
+		if err == nil {
+			log.Printf("Listening on %s", app.ControlSocketPath())
+			<-ctx.Done()
+			server.Close()
+			return
+		}
+		if control.NewClient(app.ControlSocketPath()).Release() == nil {
+			continue
+		}
+		if attempt == 0 {
+			log.Printf("Control socket unavailable, retrying every %s: %v", controlRetryInterval, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-retry.C:
+		}
+	}
+}
+
+// runRecorder samples processes, load, budgets, idle processes and listening
+// sockets at their intervals, runs scheduled exports and prunes stored files
+// until ctx is done
+func runRecorder(ctx context.Context, store storage.Storage, processService *services.ProcessService, historyService *services.HistoryService, budgetService *services.BudgetService, idleService *services.IdleService, listenerService *services.ListenerService, exportScheduler *services.ExportScheduler, collectInterval time.Duration) {
+	collect := time.NewTicker(collectInterval)
+	defer collect.Stop()
+	load := time.NewTicker(services.LoadSampleInterval)
+	defer load.Stop()
+	budget := time.NewTicker(services.BudgetInterval)
+	defer budget.Stop()
+	idle := time.NewTicker(services.IdleSampleInterval)
+	defer idle.Stop()
+	prune := time.NewTicker(storage.PruneInterval)
+	defer prune.Stop()
+	exports := time.NewTicker(services.ExportCheckInterval)
+	defer exports.Stop()
+
+	// A nil channel never fires, leaving listener checks off
+	var listeners <-chan time.Time
+	if listenerService.Enabled() {
+		ticker := time.NewTicker(listenerService.Interval())
+		defer ticker.Stop()
+		listeners = ticker.C
+		listenerService.Sample(time.Now())
+	}
+
+	historyService.SampleLoad()
+	store.Prune(false)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-collect.C:
+			if _, err := processService.GetProcesses(); err != nil {
+				log.Printf("Failed to collect processes: %v", err)
+			}
+
+		case <-load.C:
+			historyService.SampleLoad()
+
+		case t := <-budget.C:
+			if !budgetService.HasBudgets() {
+				continue
+			}
+			exceeded, err := budgetService.Sample(t)
+			if err != nil {
+				log.Printf("Failed to sample budgets: %v", err)
+			}
+			for _, status := range exceeded {
+				log.Printf("Budget exceeded: %s used %.2f of %.2f CPU-hours today, killed %d processes",
+					status.Budget.Name, status.UsedCPUHours, status.Budget.CPUHours, len(status.Killed))
+			}
+
+		case t := <-idle.C:
+			if err := idleService.Sample(t); err != nil {
+				log.Printf("Failed to sample idle processes: %v", err)
+			}
+
+		case t := <-listeners:
+			changes, err := listenerService.Sample(t)
+			if err != nil {
+				log.Printf("Failed to sample listening sockets: %v", err)
+			}
+			for _, change := range changes {
+				verb := "Stopped"
+				if change.Started {
+					verb = "Started"
+				}
+				log.Printf("%s listening: %s (PID %d) on %s", verb, change.Listener.Process, change.Listener.PID, services.ListenerAddress(change.Listener))
+			}
+
+		case <-prune.C:
+			if _, err := store.Prune(false); err != nil {
+				log.Printf("Failed to prune stored files: %v", err)
+			}
+
+		case t := <-exports.C:
+			for _, status := range exportScheduler.RunDue(t) {
+				if status.Error != "" {
+					log.Printf("Scheduled export %s failed: %s", status.Schedule.Name, status.Error)
+				} else {
+					log.Printf("Scheduled export %s: %d processes to %s", status.Schedule.Name, status.LastCount, status.LastTarget)
+				}
+			}
+		}
+	}
+}
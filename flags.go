@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"tappmanager/internal/services"
+	"tappmanager/internal/ui/models"
+)
+
+// startupFlags holds the command-line flags parsed before the UI starts
+type startupFlags struct {
+	refresh time.Duration
+	filter  string
+	sort    string
+	view    string
+	theme   string
+	dataDir string
+}
+
+// parseFlags parses the command-line flags, leaving any subcommand in flag.Args
+func parseFlags() *startupFlags {
+	flags := &startupFlags{}
+	flag.DurationVar(&flags.refresh, "refresh", 0, "refresh interval for every view, e.g. 1s")
+	flag.StringVar(&flags.filter, "filter", "", `initial process filter, e.g. "user:me cpu:5 nginx"`)
+	flag.StringVar(&flags.sort, "sort", "", "sort field, optionally with order, e.g. memory or name:asc")
+	flag.StringVar(&flags.view, "view", "", "initial view: processes, details, stats, settings, help or diagnostics")
+	flag.StringVar(&flags.theme, "theme", "", "color theme: default, nord, dracula or gruvbox")
+	flag.StringVar(&flags.dataDir, "data-dir", "", "directory for snapshots, exports and backups")
+	flag.Parse()
+	return flags
+}
+
+// applyConfigOverrides passes config-level flags on as TAPPMANAGER_*
+// environment variables, so they take precedence over the config file
+// everywhere the config is loaded
+func (f *startupFlags) applyConfigOverrides() error {
+	overrides := make(map[string]string)
+
+	if f.refresh != 0 {
+		if f.refresh < 0 {
+			return fmt.Errorf("invalid --refresh: %s", f.refresh)
+		}
+		// Intervals are configured in whole seconds
+		seconds := strconv.Itoa(int(math.Max(1, math.Round(f.refresh.Seconds()))))
+		for _, key := range []string{"REFRESH_RATE", "PROCESSES_REFRESH", "DETAILS_REFRESH", "STATS_REFRESH"} {
+			overrides["TAPPMANAGER_"+key] = seconds
+		}
+	}
+
+	if f.sort != "" {
+		field, order, hasOrder := strings.Cut(f.sort, ":")
+		if !services.IsValidSortField(field) {
+			return fmt.Errorf("invalid --sort field: %s", field)
+		}
+		if hasOrder && order != "asc" && order != "desc" {
+			return fmt.Errorf("invalid --sort order: %s (use asc or desc)", order)
+		}
+		overrides["TAPPMANAGER_DEFAULT_SORT_FIELD"] = field
+		if hasOrder {
+			overrides["TAPPMANAGER_DEFAULT_SORT_ORDER"] = order
+		}
+	}
+
+	if f.theme != "" {
+		if !models.IsTheme(f.theme) {
+			return fmt.Errorf("invalid --theme: %s (use %s)", f.theme, strings.Join(models.Themes(), ", "))
+		}
+		overrides["TAPPMANAGER_THEME"] = f.theme
+	}
+	if f.dataDir != "" {
+		overrides["TAPPMANAGER_DATA_DIR"] = f.dataDir
+	}
+
+	for key, value := range overrides {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startupOptions returns the flags that set the initial state of the UI.
+// It is called before anything is started, so invalid flags stop nothing.
+func (f *startupFlags) startupOptions() (models.StartupOptions, error) {
+	options := models.StartupOptions{View: f.view}
+	if f.view != "" && !models.IsView(f.view) {
+		return options, fmt.Errorf("invalid --view: %s", f.view)
+	}
+	if f.filter != "" {
+		filter, err := services.ParseFilterExpr(f.filter)
+		if err != nil {
+			return options, fmt.Errorf("invalid --filter: %w", err)
+		}
+		options.Filter = filter
+	}
+	return options, nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"os"
 
+	"tappmanager/internal/models"
 	"tappmanager/internal/storage"
 
 	"github.com/rivo/tview"
@@ -10,27 +11,37 @@ import (
 
 // App represents the main application
 type App struct {
-	config  *Config
+	config  *models.AppConfig
 	storage storage.Storage
 	ui      *tview.Application
 }
 
 // NewApp creates a new application instance
 func NewApp() (*App, error) {
-	config, err := LoadConfig()
-	if err != nil {
-		return nil, err
-	}
-
 	// Ensure TERM is set (tview/tcell requirement)
 	if os.Getenv("TERM") == "" {
 		os.Setenv("TERM", "xterm-256color")
 	}
 
-	storage := storage.NewJSONStorage(config.DataDir)
-	
+	configDir, dataDir := ResolveDirs()
+	if os.Getenv("TAPPMANAGER_CONFIG_DIR") == "" && os.Getenv("TAPPMANAGER_DATA_DIR") == "" {
+		if err := MigrateLegacyDir(configDir, dataDir); err != nil {
+			return nil, err
+		}
+	}
+
+	storage := storage.NewJSONStorage(configDir, dataDir)
+
+	// TAPPMANAGER_CONFIG_FORMAT converts the config file to another format
+	if format := os.Getenv("TAPPMANAGER_CONFIG_FORMAT"); format != "" {
+		if err := storage.SetConfigFormat(format); err != nil {
+			return nil, err
+		}
+	}
+
 	// Load existing configuration
-	if _, err := storage.LoadConfig(); err != nil {
+	config, err := storage.LoadConfig()
+	if err != nil {
 		return nil, err
 	}
 
@@ -43,8 +54,8 @@ func NewApp() (*App, error) {
 	return app, nil
 }
 
-// GetConfig returns the application configuration
-func (a *App) GetConfig() *Config {
+// GetConfig returns the effective application configuration
+func (a *App) GetConfig() *models.AppConfig {
 	return a.config
 }
 
@@ -1,94 +1,126 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/spf13/viper"
+	"runtime"
+	"strings"
 )
 
-// Config holds the application configuration
-type Config struct {
-	DataDir     string `mapstructure:"data_dir"`
-	Theme       string `mapstructure:"theme"`
-	RefreshRate int    `mapstructure:"refresh_rate"`
-	AutoBackup  bool   `mapstructure:"auto_backup"`
-	BackupCount int    `mapstructure:"backup_count"`
-	ShowSystem  bool   `mapstructure:"show_system"`
-	AutoRefresh bool   `mapstructure:"auto_refresh"`
-}
+// appDirName is the directory created under the platform config and data locations
+const appDirName = "tappmanager"
 
-// DefaultConfig returns the default configuration
-func DefaultConfig() *Config {
+// LegacyDir returns the directory that held both config and data before
+// platform-specific locations were used
+func LegacyDir() string {
 	homeDir, _ := os.UserHomeDir()
-	dataDir := filepath.Join(homeDir, ".tappmanager")
-	
-	return &Config{
-		DataDir:     dataDir,
-		Theme:       "default",
-		RefreshRate: 2, // seconds
-		AutoBackup:  true,
-		BackupCount: 10,
-		ShowSystem:  false,
-		AutoRefresh: true,
+	return filepath.Join(homeDir, ".tappmanager")
+}
+
+// DefaultConfigDir returns the platform config location: $XDG_CONFIG_HOME or
+// ~/.config on Linux, ~/Library/Application Support on macOS and %APPDATA% on Windows
+func DefaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return LegacyDir()
 	}
+	return filepath.Join(dir, appDirName)
 }
 
-// LoadConfig loads configuration from file and environment variables
-func LoadConfig() (*Config, error) {
-	config := DefaultConfig()
-
-	// Set default values
-	viper.SetDefault("data_dir", config.DataDir)
-	viper.SetDefault("theme", config.Theme)
-	viper.SetDefault("refresh_rate", config.RefreshRate)
-	viper.SetDefault("auto_backup", config.AutoBackup)
-	viper.SetDefault("backup_count", config.BackupCount)
-
-	// Set config file
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("$HOME/.tappmanager")
-	viper.AddConfigPath("/etc/tappmanager")
-
-	// Read config file
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, err
-		}
-		// Config file not found, use defaults
+// DefaultDataDir returns the platform data location: $XDG_DATA_HOME or
+// ~/.local/share on Linux, and the same directory as the config elsewhere
+func DefaultDataDir() string {
+	switch runtime.GOOS {
+	case "darwin", "windows", "ios", "plan9":
+		return DefaultConfigDir()
+	}
+
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName)
 	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return LegacyDir()
+	}
+	return filepath.Join(homeDir, ".local", "share", appDirName)
+}
 
-	// Bind environment variables
-	viper.AutomaticEnv()
-	viper.BindEnv("data_dir", "TAPPMANAGER_DATA_DIR")
-	viper.BindEnv("theme", "TAPPMANAGER_THEME")
-	viper.BindEnv("refresh_rate", "TAPPMANAGER_REFRESH_RATE")
-	viper.BindEnv("auto_backup", "TAPPMANAGER_AUTO_BACKUP")
-	viper.BindEnv("backup_count", "TAPPMANAGER_BACKUP_COUNT")
-
-	// Unmarshal into struct
-	if err := viper.Unmarshal(config); err != nil {
-		return nil, err
+// ResolveDirs returns the config and data directories, letting
+// TAPPMANAGER_CONFIG_DIR and TAPPMANAGER_DATA_DIR override the defaults
+// since the config file itself lives there
+func ResolveDirs() (configDir, dataDir string) {
+	configDir = os.Getenv("TAPPMANAGER_CONFIG_DIR")
+	if configDir == "" {
+		configDir = DefaultConfigDir()
 	}
+	dataDir = os.Getenv("TAPPMANAGER_DATA_DIR")
+	if dataDir == "" {
+		dataDir = DefaultDataDir()
+	}
+	return configDir, dataDir
+}
 
-	return config, nil
+// ControlSocketPath returns the path of the control socket: in
+// $XDG_RUNTIME_DIR when set, otherwise in the data directory
+func ControlSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName+".sock")
+	}
+	_, dataDir := ResolveDirs()
+	return filepath.Join(dataDir, "control.sock")
 }
 
-// SaveConfig saves the current configuration to file
-func SaveConfig(config *Config) error {
-	viper.Set("data_dir", config.DataDir)
-	viper.Set("theme", config.Theme)
-	viper.Set("refresh_rate", config.RefreshRate)
-	viper.Set("auto_backup", config.AutoBackup)
-	viper.Set("backup_count", config.BackupCount)
-
-	configDir := filepath.Dir(config.DataDir)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return err
+// MigrateLegacyDir moves config files from the legacy ~/.tappmanager
+// directory into configDir and everything else into dataDir, then removes
+// it. Nothing is moved once configDir already holds a config file.
+func MigrateLegacyDir(configDir, dataDir string) error {
+	legacyDir := LegacyDir()
+	if legacyDir == configDir || legacyDir == dataDir {
+		return nil
+	}
+
+	entries, err := os.ReadDir(legacyDir)
+	if os.IsNotExist(err) {
+		return nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy directory: %w", err)
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join(configDir, "config.*")); len(matches) > 0 {
+		return nil
+	}
+
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	for _, entry := range entries {
+		target := dataDir
+		if isConfigFile(entry.Name()) {
+			target = configDir
+		}
+
+		from := filepath.Join(legacyDir, entry.Name())
+		to := filepath.Join(target, entry.Name())
+		if _, err := os.Stat(to); err == nil {
+			// Never overwrite something already in the new location
+			continue
+		}
+		if err := os.Rename(from, to); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", from, err)
+		}
+	}
+
+	// Only removed when empty, so anything that was skipped is kept
+	os.Remove(legacyDir)
+	return nil
+}
 
-	configFile := filepath.Join(configDir, "config.yaml")
-	return viper.WriteConfigAs(configFile)
+// isConfigFile reports whether name belongs in the config directory
+func isConfigFile(name string) bool {
+	return strings.HasPrefix(name, "config.") || name == "shortcuts.json" || name == "filters.json"
 }
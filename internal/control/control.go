@@ -0,0 +1,235 @@
+// Package control implements the local socket that lets a second invocation
+// of tappmanager send commands to a running instance
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// Commands understood by the control socket
+const (
+	CommandStatus    = "status"
+	CommandKill      = "kill"
+	CommandProcesses = "processes"
+	CommandHistory   = "history"
+	CommandRelease   = "release"
+)
+
+// Modes an instance runs in
+const (
+	ModeTUI    = "tui"
+	ModeDaemon = "daemon"
+)
+
+// requestTimeout bounds how long one request may take on either side
+const requestTimeout = 5 * time.Second
+
+// ErrNotRunning is returned by Send when no instance is listening
+var ErrNotRunning = errors.New("no running tappmanager instance")
+
+// Request is a command sent to the running instance
+type Request struct {
+	Command string    `json:"command"`
+	PID     int32     `json:"pid,omitempty"`
+	Since   time.Time `json:"since"` // history: only samples taken after this time
+}
+
+// Response is the running instance's answer to a Request
+type Response struct {
+	Error       string                `json:"error,omitempty"`
+	Status      *Status               `json:"status,omitempty"`
+	Processes   []*models.ProcessInfo `json:"processes,omitempty"`
+	LoadHistory []models.LoadSample   `json:"load_history,omitempty"`
+}
+
+// Status describes the running instance
+type Status struct {
+	PID       int                 `json:"pid"`
+	Mode      string              `json:"mode"` // tui or daemon
+	Version   string              `json:"version"`
+	StartedAt time.Time           `json:"started_at"`
+	Processes int                 `json:"processes"` // processes on the host
+	History   models.HistoryStats `json:"history"`
+}
+
+// Handler carries out the commands received on the control socket
+type Handler interface {
+	Status() (*Status, error)
+	Kill(pid int32) error
+	Processes() ([]*models.ProcessInfo, error)
+	LoadHistory(since time.Time) []models.LoadSample
+	// Release agrees to give the socket up to a daemon, after which the
+	// server stops listening
+	Release() error
+}
+
+// Server accepts requests on a Unix domain socket
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+}
+
+// Listen starts serving handler on the socket at path. A socket left behind
+// by an instance that exited is replaced; a live one is an error.
+func Listen(path string, handler Handler) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another instance is listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	// Only the owner may send commands
+	listener, err := listenUnix(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	s := &Server{path: path, listener: listener, handler: handler}
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting requests and removes the socket
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+// serve accepts connections until the listener is closed
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers the single request sent on conn
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	// Commands run as the owner, so nobody else may send them
+	if !trustedPeer(conn) {
+		json.NewEncoder(conn).Encode(Response{Error: "permission denied"})
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	var resp Response
+	var err error
+	switch req.Command {
+	case CommandStatus:
+		resp.Status, err = s.handler.Status()
+	case CommandKill:
+		err = s.handler.Kill(req.PID)
+	case CommandProcesses:
+		resp.Processes, err = s.handler.Processes()
+	case CommandHistory:
+		resp.LoadHistory = s.handler.LoadHistory(req.Since)
+	case CommandRelease:
+		// Stop listening before answering, so the socket is free once the
+		// daemon reads the answer
+		if err = s.handler.Release(); err == nil {
+			s.Close()
+		}
+	default:
+		err = fmt.Errorf("unknown command: %s", req.Command)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Send sends req to the instance listening at path and returns its
+// response. It returns ErrNotRunning when nothing is listening.
+func Send(path string, req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.Error != "" {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// Client sends requests to the instance listening on a control socket
+type Client struct {
+	path string
+}
+
+// NewClient creates a client for the control socket at path
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Status returns the status of the running instance
+func (c *Client) Status() (*Status, error) {
+	resp, err := Send(c.path, Request{Command: CommandStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// Kill kills pid through the running instance
+func (c *Client) Kill(pid int32) error {
+	_, err := Send(c.path, Request{Command: CommandKill, PID: pid})
+	return err
+}
+
+// Processes returns the latest processes collected by the running instance
+func (c *Client) Processes() ([]*models.ProcessInfo, error) {
+	resp, err := Send(c.path, Request{Command: CommandProcesses})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Processes, nil
+}
+
+// Release asks the running instance to give up the socket, so that a daemon
+// can listen on it
+func (c *Client) Release() error {
+	_, err := Send(c.path, Request{Command: CommandRelease})
+	return err
+}
+
+// LoadHistory returns the load samples the running instance took after since, oldest first
+func (c *Client) LoadHistory(since time.Time) ([]models.LoadSample, error) {
+	resp, err := Send(c.path, Request{Command: CommandHistory, Since: since})
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoadHistory, nil
+}
@@ -0,0 +1,88 @@
+package control
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// fakeHandler answers status requests and records kills
+type fakeHandler struct {
+	killed []int32
+}
+
+func (h *fakeHandler) Status() (*Status, error) {
+	return &Status{PID: 42, Mode: ModeDaemon}, nil
+}
+
+func (h *fakeHandler) Kill(pid int32) error {
+	if pid == 0 {
+		return errors.New("no such process")
+	}
+	h.killed = append(h.killed, pid)
+	return nil
+}
+
+func (h *fakeHandler) Processes() ([]*models.ProcessInfo, error) { return nil, nil }
+
+func (h *fakeHandler) LoadHistory(since time.Time) []models.LoadSample { return nil }
+
+func (h *fakeHandler) Release() error { return nil }
+
+func TestListen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("socket permissions are not Unix modes on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "control.sock")
+	handler := &fakeHandler{}
+	server, err := Listen(path, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permissions %o, want 600", perm)
+	}
+
+	client := NewClient(path)
+	status, err := client.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PID != 42 {
+		t.Errorf("status PID %d, want 42", status.PID)
+	}
+	if err := client.Kill(7); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Kill(0); err == nil || err.Error() != "no such process" {
+		t.Errorf("kill error %v, want the handler's", err)
+	}
+	if len(handler.killed) != 1 || handler.killed[0] != 7 {
+		t.Errorf("killed %v, want [7]", handler.killed)
+	}
+
+	// A second instance may not take over a live socket, unless it is
+	// given up
+	if _, err := Listen(path, handler); err == nil {
+		t.Error("listening on a live socket succeeded")
+	}
+	if err := client.Release(); err != nil {
+		t.Fatal(err)
+	}
+	next, err := Listen(path, handler)
+	if err != nil {
+		t.Fatalf("socket was not given up: %v", err)
+	}
+	next.Close()
+}
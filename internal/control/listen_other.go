@@ -0,0 +1,11 @@
+//go:build !unix
+
+package control
+
+import "net"
+
+// listenUnix creates the socket at path. Without a umask its access is
+// left to the permissions of the directory it is created in.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
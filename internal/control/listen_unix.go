@@ -0,0 +1,17 @@
+//go:build unix
+
+package control
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix creates the socket at path readable and writable by its owner
+// only. The umask is tightened while it is created, so the socket never
+// exists with looser permissions for another user to connect through.
+func listenUnix(path string) (net.Listener, error) {
+	old := syscall.Umask(0177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package control
+
+import "net"
+
+// trustedPeer accepts every peer where the kernel does not report who is
+// connected; access is then left to the socket's permissions
+func trustedPeer(conn net.Conn) bool {
+	return true
+}
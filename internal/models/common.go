@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -17,14 +18,213 @@ type ProcessInfo struct {
 	Username    string    `json:"username"`
 	Command     string    `json:"command"`
 	WorkingDir  string    `json:"working_dir"`
+	Executable  string    `json:"executable"`
 	NumThreads  int32     `json:"num_threads"`
 	Nice        int32     `json:"nice"`
 	IsRunning   bool      `json:"is_running"`
+
+	ContainerID         string   `json:"container_id,omitempty"`
+	ContainerRuntime    string   `json:"container_runtime,omitempty"`
+	ContainerPrivileged bool     `json:"container_privileged,omitempty"` // has every capability inside a container
+	HostNamespaces      []string `json:"host_namespaces,omitempty"`      // namespaces a containerized process shares with the host
+	SecurityContext     string   `json:"security_context,omitempty"`
+	Terminal            string   `json:"terminal,omitempty"`
+	SessionID           int32    `json:"session_id,omitempty"`
+	ProcessGroupID      int32    `json:"process_group_id,omitempty"`
+
+	// CgroupCPULimit and CgroupMemoryLimit are the tightest limits of the
+	// process's cgroup: CPUs' worth of time (1.5 for one and a half) and
+	// bytes, zero when unlimited
+	CgroupCPULimit    float64 `json:"cgroup_cpu_limit,omitempty"`
+	CgroupMemoryLimit uint64  `json:"cgroup_memory_limit,omitempty"`
+
+	// Virtualization names what the process runs under: WSL, Wine, or the
+	// hypervisor of the virtual machine it belongs to, empty for none
+	Virtualization string `json:"virtualization,omitempty"`
+
+	// IO holds cumulative disk I/O, nil when the counters cannot be read
+	IO *ProcessIO `json:"io,omitempty"`
+
+	// Args holds the individual argv entries that make up Command
+	Args []string `json:"args,omitempty"`
+
+	// UIDs and GIDs hold real, effective, saved and filesystem IDs where the platform reports them
+	UIDs []int32 `json:"uids,omitempty"`
+	GIDs []int32 `json:"gids,omitempty"`
+}
+
+// ProcessIO holds the disk I/O a process has done since it started
+type ProcessIO struct {
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+	ReadCount  uint64 `json:"read_count"`  // read operations
+	WriteCount uint64 `json:"write_count"` // write operations
+}
+
+// RealUID returns the real user ID and whether it is known
+func (p *ProcessInfo) RealUID() (int32, bool) {
+	if len(p.UIDs) < 1 {
+		return 0, false
+	}
+	return p.UIDs[0], true
+}
+
+// EffectiveUID returns the effective user ID and whether it is known
+func (p *ProcessInfo) EffectiveUID() (int32, bool) {
+	if len(p.UIDs) < 2 {
+		return 0, false
+	}
+	return p.UIDs[1], true
+}
+
+// IsSetuid reports whether the real and effective user IDs differ
+func (p *ProcessInfo) IsSetuid() bool {
+	ruid, ok := p.RealUID()
+	if !ok {
+		return false
+	}
+	euid, ok := p.EffectiveUID()
+	return ok && ruid != euid
+}
+
+// UserStats represents aggregated resource usage for a single user
+type UserStats struct {
+	Username     string  `json:"username"`
+	ProcessCount int     `json:"process_count"`
+	ThreadCount  int     `json:"thread_count"`
+	CPU          float64 `json:"cpu"`
+	Memory       float64 `json:"memory"`
+	MemoryBytes  uint64  `json:"memory_bytes"`
+}
+
+// ContainerStats represents aggregated resource usage for a single container
+type ContainerStats struct {
+	ID           string  `json:"id"`
+	Runtime      string  `json:"runtime"`
+	ProcessCount int     `json:"process_count"`
+	CPU          float64 `json:"cpu"`
+	Memory       float64 `json:"memory"`
+	MemoryBytes  uint64  `json:"memory_bytes"`
+
+	// Privileged is set when a process in the container has every
+	// capability, as in a container run with --privileged
+	Privileged bool `json:"privileged"`
+	// HostNamespaces lists the namespaces its processes share with the host,
+	// and HostNamespaceProcesses how many processes share any
+	HostNamespaces         []string `json:"host_namespaces,omitempty"`
+	HostNamespaceProcesses int      `json:"host_namespace_processes,omitempty"`
+}
+
+// Flagged reports whether the container weakens isolation from the host
+func (cs *ContainerStats) Flagged() bool {
+	return cs.Privileged || len(cs.HostNamespaces) > 0
+}
+
+// ShortID returns the abbreviated container ID used by container CLIs
+func (cs *ContainerStats) ShortID() string {
+	if len(cs.ID) > 12 {
+		return cs.ID[:12]
+	}
+	return cs.ID
+}
+
+// BlockedProcess represents a process stuck in uninterruptible sleep (D state)
+type BlockedProcess struct {
+	Process      *ProcessInfo `json:"process"`
+	BlockedSince time.Time    `json:"blocked_since"`
+	WaitChannel  string       `json:"wait_channel,omitempty"`
+}
+
+// DuplicateGroup is a set of processes running the same command line
+type DuplicateGroup struct {
+	Command   string         `json:"command"`
+	Processes []*ProcessInfo `json:"processes"` // oldest first
+}
+
+// Connection is a socket held open by a process
+type Connection struct {
+	Protocol   string `json:"protocol"` // tcp, tcp6, udp, udp6 or unix
+	LocalAddr  string `json:"local_addr"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	Status     string `json:"status,omitempty"` // TCP state, e.g. LISTEN or ESTABLISHED
 }
 
-// ProcessFilter represents filtering options for processes
+// ListeningPort is a socket accepting connections or datagrams on the host
+type ListeningPort struct {
+	Protocol string `json:"protocol"` // tcp, tcp6, udp or udp6
+	Address  string `json:"address"`  // bound IP, 0.0.0.0 or :: for all
+	Port     uint32 `json:"port"`
+	PID      int32  `json:"pid,omitempty"` // zero when the owner is not visible
+	Process  string `json:"process,omitempty"`
+}
+
+// Dependency is a local process connected over TCP to a port another local
+// process listens on
+type Dependency struct {
+	ClientPID   int32  `json:"client_pid"` // zero when the owner is not visible
+	ClientName  string `json:"client_name"`
+	ServerPID   int32  `json:"server_pid"`
+	ServerName  string `json:"server_name"`
+	Port        uint32 `json:"port"`        // the port the server listens on
+	Connections int    `json:"connections"` // open connections between the two
+}
+
+// OpenFile is a file held open by a process
+type OpenFile struct {
+	FD   uint64 `json:"fd"`
+	Path string `json:"path"`
+}
+
+// EnvVar is a variable in the environment of a process
+type EnvVar struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Sensitive bool   `json:"sensitive"` // the name suggests a credential
+}
+
+// Thread is a thread of a process and the CPU time it has used
+type Thread struct {
+	TID    int32   `json:"tid"`
+	Name   string  `json:"name,omitempty"` // only known on Linux
+	User   float64 `json:"user"`           // seconds of CPU time in user mode
+	System float64 `json:"system"`         // seconds of CPU time in kernel mode
+}
+
+// Language runtimes recognised by the process service
+const (
+	RuntimeGo     = "go"
+	RuntimeJVM    = "jvm"
+	RuntimePython = "python"
+	RuntimeNode   = "node"
+)
+
+// RuntimeInfo describes the language runtime a process runs on
+type RuntimeInfo struct {
+	Name    string `json:"name"`              // one of the Runtime constants
+	Version string `json:"version,omitempty"` // when it can be told
+	Main    string `json:"main,omitempty"`    // main module, jar or script
+}
+
+// JVMStats are the heap and garbage collection figures of a JVM, as jstat
+// reports them
+type JVMStats struct {
+	HeapUsed          uint64  `json:"heap_used"` // bytes, young and old generations
+	HeapCapacity      uint64  `json:"heap_capacity"`
+	OldUsed           uint64  `json:"old_used"`
+	OldCapacity       uint64  `json:"old_capacity"`
+	MetaspaceUsed     uint64  `json:"metaspace_used"`
+	MetaspaceCapacity uint64  `json:"metaspace_capacity"`
+	YoungGCs          int64   `json:"young_gcs"`
+	YoungGCTime       float64 `json:"young_gc_time"` // seconds
+	FullGCs           int64   `json:"full_gcs"`
+	FullGCTime        float64 `json:"full_gc_time"`
+	GCTime            float64 `json:"gc_time"` // all collections
+}
+
+// ProcessFilter represents filtering options for processes. A zero MaxCPU
+// or MaxMemory sets no upper bound, so the zero filter keeps every process.
 type ProcessFilter struct {
-	SearchTerm string `json:"search_term"`
+	SearchTerm string  `json:"search_term"`
 	MinCPU     float64 `json:"min_cpu"`
 	MaxCPU     float64 `json:"max_cpu"`
 	MinMemory  float64 `json:"min_memory"`
@@ -32,31 +232,246 @@ type ProcessFilter struct {
 	Status     string  `json:"status"`
 	Username   string  `json:"username"`
 	ShowSystem bool    `json:"show_system"`
+	SessionID  int32   `json:"session_id,omitempty"`
+	PathPrefix string  `json:"path_prefix,omitempty"` // match processes running from or within this directory
+
+	// Virtualized shows only virtualized processes or hides them (see
+	// VirtualizedOnly and VirtualizedHide); empty shows both
+	Virtualized string `json:"virtualized,omitempty"`
+
+	// Processes matching any exclusion are hidden even if they match the rest
+	ExcludeUsers      []string `json:"exclude_users,omitempty"`
+	ExcludeNames      []string `json:"exclude_names,omitempty"`       // exact names, ignoring case
+	ExcludeSearchTerm string   `json:"exclude_search_term,omitempty"` // hides processes whose name, command or user contains it
+}
+
+// Values of ProcessFilter.Virtualized
+const (
+	VirtualizedOnly = "only"
+	VirtualizedHide = "hide"
+)
+
+// SavedFilter is a process filter kept under a name to apply again later
+type SavedFilter struct {
+	Name   string        `json:"name"`
+	Filter ProcessFilter `json:"filter"`
 }
 
 // ProcessSort represents sorting options for processes
 type ProcessSort struct {
-	Field string `json:"field"` // cpu, memory, pid, name, status
+	Field string `json:"field"` // cpu, memory, pid, name, status, io_read, io_write
 	Order string `json:"order"` // asc, desc
 }
 
+// RetentionPolicy limits how long and how much of a kind of stored file is kept
+type RetentionPolicy struct {
+	MaxAge  int `json:"max_age"`  // days; zero keeps files of any age
+	MaxSize int `json:"max_size"` // MiB across all files of the kind; zero is unlimited
+}
+
+// Budget actions taken once a budget is exceeded
+const (
+	BudgetAlert = "alert"
+	BudgetKill  = "kill"
+)
+
+// MQTTConfig configures publishing summaries and events to an MQTT broker
+type MQTTConfig struct {
+	Broker       string `json:"broker"`    // host:port, optionally tcp:// or ssl:// prefixed; empty disables publishing
+	ClientID     string `json:"client_id"` // {host} in this and the topics is replaced by the hostname
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	SummaryTopic string `json:"summary_topic"` // retained summary metrics
+	EventTopic   string `json:"event_topic"`   // kills and budget alerts
+	Interval     int    `json:"interval"`      // seconds between summaries
+}
+
+// GrafanaConfig configures pushing events to Grafana as annotations
+type GrafanaConfig struct {
+	URL          string   `json:"url"`           // Grafana base URL; empty disables annotations
+	Token        string   `json:"token"`         // service account token allowed to write annotations
+	DashboardUID string   `json:"dashboard_uid"` // show annotations on this dashboard only, empty for all
+	Tags         []string `json:"tags"`          // added to the event kind, level and host tags
+}
+
+// ResourceBudget caps the CPU time processes with a given name may use per day
+type ResourceBudget struct {
+	Name     string  `json:"name"`      // process name
+	CPUHours float64 `json:"cpu_hours"` // CPU-hours per calendar day, summed over all processes with the name
+	Action   string  `json:"action"`    // alert or kill
+}
+
+// BudgetStatus is the usage of a budget on the current day
+type BudgetStatus struct {
+	Budget       ResourceBudget `json:"budget"`
+	UsedCPUHours float64        `json:"used_cpu_hours"`
+	Exceeded     bool           `json:"exceeded"`
+	Killed       []int32        `json:"killed,omitempty"` // PIDs killed when the budget was exceeded
+	Error        string         `json:"error,omitempty"`
+}
+
+// Export destination types
+const (
+	ExportToDir  = "dir"
+	ExportToS3   = "s3"
+	ExportToHTTP = "http"
+)
+
+// ExportSchedule exports the filtered process list to a destination at a fixed interval
+type ExportSchedule struct {
+	Name        string            `json:"name"`
+	Every       string            `json:"every"`  // interval such as 15m or 6h, at least a minute
+	Filter      string            `json:"filter"` // filter expression as for --filter; empty hides system processes
+	Format      string            `json:"format"` // csv or json
+	Destination ExportDestination `json:"destination"`
+}
+
+// ExportDestination is where a scheduled export is sent. Only the fields of
+// its type are used.
+type ExportDestination struct {
+	Type     string            `json:"type"`     // dir, s3 or http
+	Path     string            `json:"path"`     // dir: directory the files are written to
+	Bucket   string            `json:"bucket"`   // s3: bucket name
+	Prefix   string            `json:"prefix"`   // s3: prefix of the object keys
+	Region   string            `json:"region"`   // s3: bucket region, AWS_REGION when empty
+	Endpoint string            `json:"endpoint"` // s3: URL of an S3-compatible store, empty for AWS
+	URL      string            `json:"url"`      // http: URL the export is POSTed to
+	Headers  map[string]string `json:"headers"`  // http: extra request headers such as Authorization
+}
+
+// ExportScheduleStatus is the outcome of a schedule's exports so far
+type ExportScheduleStatus struct {
+	Schedule   ExportSchedule `json:"schedule"`
+	NextRun    time.Time      `json:"next_run"`    // zero when the schedule is invalid
+	LastRun    time.Time      `json:"last_run"`    // zero before the first run
+	LastTarget string         `json:"last_target"` // file, object or URL the last export went to
+	LastCount  int            `json:"last_count"`  // processes in the last export
+	Runs       int            `json:"runs"`
+	Failures   int            `json:"failures"`
+	Error      string         `json:"error,omitempty"` // why the last run failed or the schedule is invalid
+}
+
+// LogHighlight colors log lines matching a regular expression
+type LogHighlight struct {
+	Pattern string `json:"pattern"`
+	Color   string `json:"color"` // terminal color number or hex code
+}
+
+// ConfigSchemaVersion is the current config file schema. Bump it together
+// with a new migration whenever keys are renamed or their meaning changes.
+const ConfigSchemaVersion = 2
+
 // AppConfig represents the application configuration
 type AppConfig struct {
-	RefreshRate    int           `json:"refresh_rate"`
-	ShowSystem     bool          `json:"show_system"`
-	DefaultSort    ProcessSort   `json:"default_sort"`
-	DefaultFilter  ProcessFilter `json:"default_filter"`
-	AutoRefresh    bool          `json:"auto_refresh"`
-	Theme          string        `json:"theme"`
-	DataDir        string        `json:"data_dir"`
-	Version        string        `json:"version"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	SchemaVersion   int           `json:"schema_version"`
+	RefreshRate     int           `json:"refresh_rate"`
+	ShowSystem      bool          `json:"show_system"`
+	DefaultSort     ProcessSort   `json:"default_sort"`
+	DefaultFilter   ProcessFilter `json:"default_filter"`
+	AutoRefresh     bool          `json:"auto_refresh"`
+	AutoBackup      bool          `json:"auto_backup"`
+	BackupCount     int           `json:"backup_count"`
+	Theme           string        `json:"theme"`
+	DataDir         string        `json:"data_dir"`
+	Version         string        `json:"version"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	DStateThreshold int           `json:"dstate_threshold"` // seconds in D state before a process is reported as stuck
+
+	// Command lines running more than DuplicateThreshold times are reported,
+	// except for process names matching DuplicateExclude (globs allowed)
+	DuplicateThreshold int      `json:"duplicate_threshold"`
+	DuplicateExclude   []string `json:"duplicate_exclude"`
+
+	// Per-view refresh intervals in seconds; zero falls back to the view's default
+	ProcessesRefresh int `json:"processes_refresh"`
+	DetailsRefresh   int `json:"details_refresh"`
+	StatsRefresh     int `json:"stats_refresh"`
+
+	ProcessCap int `json:"process_cap"` // most processes listed after sorting; zero lists all
+
+	HistoryBudget    int `json:"history_budget"`    // KiB of memory the load history may use
+	HistoryRetention int `json:"history_retention"` // seconds of load history to keep
+
+	SnapshotCompression string `json:"snapshot_compression"` // none or gzip, for snapshots and the rotated event history
+
+	ExportRetention   RetentionPolicy `json:"export_retention"`
+	SnapshotRetention RetentionPolicy `json:"snapshot_retention"`
+	BackupRetention   RetentionPolicy `json:"backup_retention"`
+	EventRetention    RetentionPolicy `json:"event_retention"` // the rotated event history, not the file in use
+
+	CheckUpdates bool `json:"check_updates"` // look for newer GitHub releases at startup
+
+	LogFiles      map[string][]string `json:"log_files"`      // log file paths or globs by process name
+	LogHighlights []LogHighlight      `json:"log_highlights"` // the first matching rule colors a log line
+
+	Budgets []ResourceBudget `json:"budgets"`
+
+	ExportSchedules []ExportSchedule `json:"export_schedules"`
+
+	CPULimitPercent int `json:"cpu_limit_percent"` // share of time a CPU-limited process may run
+
+	KillTimeout int `json:"kill_timeout"` // seconds a process gets to exit after SIGTERM before SIGKILL
+
+	IdleThreshold int `json:"idle_threshold"` // seconds without CPU or I/O before a process counts as idle, 0 to disable
+
+	ListenerInterval int `json:"listener_interval"` // seconds between checks for services starting or stopping listening, 0 to disable
+
+	HashAllowlist string `json:"hash_allowlist"` // file of allowed executable SHA-256 hashes as written by sha256sum, empty to disable hash checks
+
+	ControlSocket bool `json:"control_socket"` // accept commands from other invocations on a local socket
+
+	EventLog string `json:"event_log"` // syslog or journald to record kills and budget alerts there, empty to disable
+
+	MQTT MQTTConfig `json:"mqtt"`
+
+	Grafana GrafanaConfig `json:"grafana"`
+
+	Watch []string `json:"watch"` // process names whose status the facts subcommand reports
+
+	Columns []string `json:"columns"` // optional processes table columns shown, from ProcessColumns
+
+	ChangeIndicators string `json:"change_indicators"` // how CPU and memory changes since the last refresh are marked: arrows, absolute or off
+}
+
+// Ways the processes table marks CPU and memory changes since the last refresh
+const (
+	ChangesArrows   = "arrows"   // ▲ or ▼ after the figure
+	ChangesAbsolute = "absolute" // the arrow followed by the change itself
+	ChangesOff      = "off"
+)
+
+// Optional columns of the processes table
+const (
+	ColumnPID       = "pid"
+	ColumnPPID      = "ppid"
+	ColumnCPUBar    = "cpu_bar"
+	ColumnMemoryBar = "memory_bar"
+	ColumnThreads   = "threads"
+	ColumnNice      = "nice"
+	ColumnIO        = "io"
+	ColumnStart     = "start"
+	ColumnCommand   = "command"
+)
+
+// ProcessColumns lists the optional processes table columns in the order
+// the column chooser offers them
+var ProcessColumns = []string{ColumnPID, ColumnPPID, ColumnCPUBar, ColumnMemoryBar, ColumnThreads, ColumnNice, ColumnIO, ColumnStart, ColumnCommand}
+
+// StateArchive bundles the config and other state files so a setup can be
+// replicated on another machine
+type StateArchive struct {
+	Version    int                        `json:"version"`
+	ExportedAt time.Time                  `json:"exported_at"`
+	Config     *AppConfig                 `json:"config"`
+	Files      map[string]json.RawMessage `json:"files"` // state files by name within the config directory
 }
 
 // NewAppConfig creates a new AppConfig instance with default values
 func NewAppConfig() *AppConfig {
 	return &AppConfig{
+		SchemaVersion: ConfigSchemaVersion,
+
 		RefreshRate: 2,
 		ShowSystem:  false,
 		DefaultSort: ProcessSort{
@@ -74,10 +489,67 @@ func NewAppConfig() *AppConfig {
 			ShowSystem: false,
 		},
 		AutoRefresh: true,
+		AutoBackup:  true,
+		BackupCount: 10,
 		Theme:       "default",
 		DataDir:     "~/.tappmanager",
 		Version:     "1.0.0",
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+
+		DStateThreshold: 10,
+
+		DuplicateThreshold: 1,
+		DuplicateExclude:   []string{"chrome", "chromium", "firefox", "code", "electron", "postgres", "nginx", "httpd", "apache2", "php-fpm*"},
+
+		ProcessesRefresh: 2,
+		DetailsRefresh:   3,
+		StatsRefresh:     5,
+
+		ProcessCap: 2000,
+
+		HistoryBudget:    1024,
+		HistoryRetention: 86400,
+
+		SnapshotCompression: "none",
+
+		CheckUpdates: false,
+
+		LogFiles: map[string][]string{},
+		LogHighlights: []LogHighlight{
+			{Pattern: `(?i)\b(error|fatal|panic)\b`, Color: "196"},
+			{Pattern: `(?i)\bwarn(ing)?\b`, Color: "220"},
+		},
+
+		Budgets: []ResourceBudget{},
+
+		ExportSchedules: []ExportSchedule{},
+
+		CPULimitPercent: 50,
+
+		KillTimeout: 5,
+
+		IdleThreshold: 3600,
+
+		ListenerInterval: 30,
+
+		ControlSocket: true,
+
+		MQTT: MQTTConfig{
+			ClientID:     "tappmanager-{host}",
+			SummaryTopic: "tappmanager/{host}/summary",
+			EventTopic:   "tappmanager/{host}/events",
+			Interval:     60,
+		},
+
+		Grafana: GrafanaConfig{
+			Tags: []string{"tappmanager"},
+		},
+
+		Watch: []string{},
+
+		Columns: []string{ColumnPID, ColumnThreads, ColumnNice},
+
+		ChangeIndicators: ChangesArrows,
 	}
 }
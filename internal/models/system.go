@@ -0,0 +1,368 @@
+package models
+
+import (
+	"time"
+)
+
+// LoadSample represents a single load average and run-queue measurement
+type LoadSample struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Load1        float64   `json:"load1"`
+	Load5        float64   `json:"load5"`
+	Load15       float64   `json:"load15"`
+	ProcsRunning int       `json:"procs_running"`
+	ProcsBlocked int       `json:"procs_blocked"`
+}
+
+// ReleaseInfo describes the latest published release
+type ReleaseInfo struct {
+	Version         string    `json:"version"`
+	URL             string    `json:"url"`
+	PublishedAt     time.Time `json:"published_at"`
+	UpdateAvailable bool      `json:"update_available"` // newer than the running version
+}
+
+// Scheduled action kinds
+const (
+	ActionKill = "kill"
+)
+
+// Scheduled action states
+const (
+	ActionPending   = "pending"
+	ActionDone      = "done"
+	ActionFailed    = "failed"
+	ActionSkipped   = "skipped"
+	ActionCancelled = "cancelled"
+)
+
+// ScheduledAction is an action to run on a process at a set time
+type ScheduledAction struct {
+	ID          int       `json:"id"`
+	Action      string    `json:"action"`
+	PID         int32     `json:"pid"`
+	Name        string    `json:"name"`
+	CreateTime  time.Time `json:"create_time"` // start time of the process, to detect PID reuse
+	At          time.Time `json:"at"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	RanAt       time.Time `json:"ran_at,omitempty"`
+	State       string    `json:"state"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// CPULimit is a cap on the share of time a process may run
+type CPULimit struct {
+	PID         int32     `json:"pid"`
+	Name        string    `json:"name"`
+	Percent     int       `json:"percent"`      // share of each cycle the process may run
+	MeasuredCPU float64   `json:"measured_cpu"` // CPU usage under the limit, percent of one core
+	StartedAt   time.Time `json:"started_at"`
+	Error       string    `json:"error,omitempty"` // why the limit ended, if it did
+}
+
+// Event kinds
+const (
+	EventKill            = "kill"
+	EventSignal          = "signal" // any signal but SIGKILL
+	EventRenice          = "renice"
+	EventBudgetExceeded  = "budget_exceeded"
+	EventListenerStarted = "listener_started"
+	EventListenerStopped = "listener_stopped"
+)
+
+// Event levels
+const (
+	EventNotice  = "notice"
+	EventWarning = "warning"
+)
+
+// Event is something tappmanager did or noticed that is worth recording in
+// the host's logs
+type Event struct {
+	Time    time.Time         `json:"time"`
+	Kind    string            `json:"kind"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"` // e.g. pid and name
+}
+
+// MetricsSummary is a snapshot of the host's process and load metrics
+type MetricsSummary struct {
+	Time      time.Time `json:"time"`
+	Hostname  string    `json:"hostname"`
+	Processes int       `json:"processes"`
+	Running   int       `json:"running"`
+	CPU       float64   `json:"cpu"`    // summed CPU usage of all processes, percent of one core
+	Memory    float64   `json:"memory"` // summed memory usage of all processes, percent
+	Load1     float64   `json:"load1"`
+	Load5     float64   `json:"load5"`
+	Load15    float64   `json:"load15"`
+}
+
+// FactsSchemaVersion is raised whenever Facts changes in a way that breaks
+// existing consumers; added fields do not change it
+const FactsSchemaVersion = 1
+
+// Facts describes the host for Ansible facts or CMDB ingestion
+type Facts struct {
+	SchemaVersion  int              `json:"schema_version"`
+	CollectedAt    time.Time        `json:"collected_at"`
+	Host           *SystemInfo      `json:"host"`
+	Metrics        *MetricsSummary  `json:"metrics"`
+	Processes      []WatchedProcess `json:"processes"` // in the order they are watched
+	ListeningPorts []ListeningPort  `json:"listening_ports"`
+}
+
+// WatchedProcess is the status of the processes with a watched name
+type WatchedProcess struct {
+	Name    string  `json:"name"`
+	Running bool    `json:"running"`
+	Count   int     `json:"count"`
+	PIDs    []int32 `json:"pids"`
+	CPU     float64 `json:"cpu"`    // summed over the processes, percent of one core
+	Memory  float64 `json:"memory"` // summed over the processes, percent
+}
+
+// Report sections
+const (
+	ReportSummary = "summary"
+	ReportTop     = "top"
+	ReportUsers   = "users"
+	ReportLoad    = "load"
+	ReportAlerts  = "alerts"
+	ReportWatch   = "watch"
+	ReportDiff    = "diff"
+)
+
+// ReportSections are the report sections in the order they appear
+var ReportSections = []string{ReportSummary, ReportTop, ReportUsers, ReportLoad, ReportAlerts, ReportWatch, ReportDiff}
+
+// Report combines the chosen sections into one document. Only the fields of
+// the chosen sections are set.
+type Report struct {
+	GeneratedAt  time.Time        `json:"generated_at"`
+	Host         *SystemInfo      `json:"host"`
+	Sections     []string         `json:"sections"`
+	Summary      *MetricsSummary  `json:"summary,omitempty"`
+	StatusCounts map[string]int   `json:"status_counts,omitempty"`
+	TopN         int              `json:"top_n,omitempty"`
+	TopCPU       []*ProcessInfo   `json:"top_cpu,omitempty"`
+	TopMemory    []*ProcessInfo   `json:"top_memory,omitempty"`
+	Users        []*UserStats     `json:"users,omitempty"`
+	Window       time.Duration    `json:"window,omitempty"` // how far back load history and alerts go
+	LoadHistory  []LoadSample     `json:"load_history,omitempty"`
+	Alerts       []Event          `json:"alerts,omitempty"`
+	Watched      []WatchedProcess `json:"watched,omitempty"`
+	Diff         *ProcessDiff     `json:"diff,omitempty"`
+}
+
+// ProcessDiff lists the processes started and exited since a saved snapshot
+type ProcessDiff struct {
+	HasSnapshot bool           `json:"has_snapshot"`
+	Started     []*ProcessInfo `json:"started"`
+	Exited      []*ProcessInfo `json:"exited"`
+}
+
+// MQTTStatus describes publishing to an MQTT broker
+type MQTTStatus struct {
+	Broker        string    `json:"broker"`
+	Connected     bool      `json:"connected"`
+	Published     int       `json:"published"`
+	Failed        int       `json:"failed"`
+	Dropped       int       `json:"dropped"` // events dropped while the queue was full
+	LastPublishAt time.Time `json:"last_publish_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorAt   time.Time `json:"last_error_at"`
+}
+
+// GrafanaStatus describes pushing annotations to Grafana
+type GrafanaStatus struct {
+	URL         string    `json:"url"`
+	Pushed      int       `json:"pushed"`
+	Failed      int       `json:"failed"`
+	Dropped     int       `json:"dropped"` // events dropped while the queue was full
+	LastPushAt  time.Time `json:"last_push_at"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at"`
+}
+
+// IdleProcess is a process that has used almost no CPU and done no I/O for a while
+type IdleProcess struct {
+	PID        int32     `json:"pid"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username"`
+	Command    string    `json:"command"`
+	CreateTime time.Time `json:"create_time"`
+	IdleSince  time.Time `json:"idle_since"`  // last activity, or when first seen
+	CPUSeconds float64   `json:"cpu_seconds"` // total CPU time used
+}
+
+// UnknownBinary is a running process whose executable's hash is not in the
+// allowlist
+type UnknownBinary struct {
+	PID        int32     `json:"pid"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username"`
+	Executable string    `json:"executable"`
+	SHA256     string    `json:"sha256"`
+	CreateTime time.Time `json:"create_time"`
+	FirstSeen  time.Time `json:"first_seen"`
+}
+
+// PrivilegeAudit is a process whose privileges stand out, with the findings
+// that make it risky
+type PrivilegeAudit struct {
+	PID             int32    `json:"pid"`
+	Name            string   `json:"name"`
+	Username        string   `json:"username"`
+	Executable      string   `json:"executable"`
+	Root            bool     `json:"root"`                   // effective UID is 0
+	AllCapabilities bool     `json:"all_capabilities"`       // every known capability is effective
+	Capabilities    []string `json:"capabilities,omitempty"` // effective capabilities unless all are
+	WritableBy      string   `json:"writable_by,omitempty"`  // who else can replace the executable
+	Risk            int      `json:"risk"`                   // higher is riskier
+	Reasons         []string `json:"reasons"`
+}
+
+// ListenerChange is a service that started or stopped listening on a port
+type ListenerChange struct {
+	Time     time.Time     `json:"time"`
+	Started  bool          `json:"started"` // false when it stopped listening
+	Listener ListeningPort `json:"listener"`
+}
+
+// SystemInfo represents static information about the host
+type SystemInfo struct {
+	Hostname        string    `json:"hostname"`
+	OS              string    `json:"os"`
+	Platform        string    `json:"platform"`
+	PlatformVersion string    `json:"platform_version"`
+	KernelVersion   string    `json:"kernel_version"`
+	KernelArch      string    `json:"kernel_arch"`
+	CPUModel        string    `json:"cpu_model"`
+	CPUCores        int       `json:"cpu_cores"`
+	BootTime        time.Time `json:"boot_time"`
+}
+
+// Uptime returns how long the host has been running
+func (si *SystemInfo) Uptime() time.Duration {
+	if si.BootTime.IsZero() {
+		return 0
+	}
+	return time.Since(si.BootTime)
+}
+
+// Capability describes whether a platform feature is usable and why not
+type Capability struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Capabilities describes which optional process features the current platform supports
+type Capabilities struct {
+	Affinity    Capability `json:"affinity"`
+	IONice      Capability `json:"ionice"`
+	Connections Capability `json:"connections"`
+	IOCounters  Capability `json:"io_counters"`
+	WaitChannel Capability `json:"wait_channel"`
+	Containers  Capability `json:"containers"`
+	Security    Capability `json:"security"`
+
+	// Scope tells whether tappmanager runs inside a container and so what
+	// its figures cover
+	Scope Scope `json:"scope"`
+}
+
+// Scope describes what tappmanager sees from where it runs. Inside a
+// container only the container's processes are listed, unless it shares
+// the host's PID namespace or reads the host's /proc through HOST_PROC,
+// while the kernel's CPU and memory totals remain the host's.
+type Scope struct {
+	Container     bool    `json:"container"`
+	Runtime       string  `json:"runtime,omitempty"` // docker, podman, kubernetes, ...
+	HostProcesses bool    `json:"host_processes"`    // the host's processes are listed
+	CPULimit      float64 `json:"cpu_limit,omitempty"`
+	MemoryLimit   uint64  `json:"memory_limit,omitempty"`
+}
+
+// List returns all capabilities in display order
+func (c *Capabilities) List() []Capability {
+	return []Capability{
+		c.Affinity,
+		c.IONice,
+		c.Connections,
+		c.IOCounters,
+		c.WaitChannel,
+		c.Containers,
+		c.Security,
+	}
+}
+
+// CollectorStats describes the timing and error counts of process scans
+type CollectorStats struct {
+	Scans            int            `json:"scans"`
+	LastScanAt       time.Time      `json:"last_scan_at"`
+	LastScanDuration time.Duration  `json:"last_scan_duration"`
+	MaxScanDuration  time.Duration  `json:"max_scan_duration"`
+	ProcessCount     int            `json:"process_count"`
+	SkippedCount     int            `json:"skipped_count"`
+	FieldErrors      map[string]int `json:"field_errors"` // per field, last scan only
+}
+
+// StorageStats describes the latency and outcome of storage writes
+type StorageStats struct {
+	Writes           int           `json:"writes"`
+	Errors           int           `json:"errors"`
+	LastWriteAt      time.Time     `json:"last_write_at"`
+	LastWriteLatency time.Duration `json:"last_write_latency"`
+	MaxWriteLatency  time.Duration `json:"max_write_latency"`
+}
+
+// PrunedFile is a stored file selected for deletion by a retention policy
+type PrunedFile struct {
+	Path    string    `json:"path"`
+	Kind    string    `json:"kind"` // export, snapshot or backup
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Reason  string    `json:"reason"`
+}
+
+// PruneReport lists the files removed by a prune, or that a dry run would remove
+type PruneReport struct {
+	DryRun     bool         `json:"dry_run"`
+	Files      []PrunedFile `json:"files"`
+	FreedBytes int64        `json:"freed_bytes"`
+	RunAt      time.Time    `json:"run_at"`
+}
+
+// RuntimeStats describes the resource usage of the application itself
+type RuntimeStats struct {
+	HeapAlloc  uint64 `json:"heap_alloc"`
+	HeapSys    uint64 `json:"heap_sys"`
+	Sys        uint64 `json:"sys"`
+	NumGC      uint32 `json:"num_gc"`
+	Goroutines int    `json:"goroutines"`
+}
+
+// HistoryStats describes the size of the in-memory history against its limits
+type HistoryStats struct {
+	RawSamples    int           `json:"raw_samples"`
+	MediumSamples int           `json:"medium_samples"` // 10 second averages
+	CoarseSamples int           `json:"coarse_samples"` // 1 minute averages
+	MemoryBytes   uint64        `json:"memory_bytes"`
+	BudgetBytes   uint64        `json:"budget_bytes"`
+	Retention     time.Duration `json:"retention"`
+	OldestSample  time.Time     `json:"oldest_sample"`
+}
+
+// Diagnostics groups collector, storage, history and runtime health information
+type Diagnostics struct {
+	Collector   CollectorStats `json:"collector"`
+	Storage     StorageStats   `json:"storage"`
+	History     HistoryStats   `json:"history"`
+	Runtime     RuntimeStats   `json:"runtime"`
+	MQTT        *MQTTStatus    `json:"mqtt,omitempty"`    // nil when publishing is off
+	Grafana     *GrafanaStatus `json:"grafana,omitempty"` // nil when annotations are off
+	CollectedAt time.Time      `json:"collected_at"`
+}
@@ -0,0 +1,205 @@
+// Package mqtt implements the part of MQTT 3.1.1 needed to publish messages
+// at QoS 0: connecting, publishing and disconnecting
+package mqtt
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Control packet types, shifted into the first byte of the fixed header
+const (
+	packetConnect    = 0x10
+	packetConnack    = 0x20
+	packetPublish    = 0x30
+	packetDisconnect = 0xe0
+)
+
+// Flags of the CONNECT packet
+const (
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+)
+
+// timeout bounds connecting and each write
+const timeout = 10 * time.Second
+
+// maxRemainingLength is the largest packet body MQTT can encode
+const maxRemainingLength = 268435455
+
+// connackErrors describe the CONNACK return codes that refuse a connection
+var connackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// Options configure a connection to a broker
+type Options struct {
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration // the broker drops the client after 1.5 times this without packets; zero disables
+}
+
+// Client is a connection to an MQTT broker
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Dial connects to the broker at addr and completes the MQTT handshake. The
+// address is host:port, optionally prefixed with tcp:// or mqtt://, or with
+// ssl://, tls:// or mqtts:// to connect over TLS.
+func Dial(addr string, opts Options) (*Client, error) {
+	conn, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn}
+	if err := c.connect(opts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// dial opens the network connection named by addr
+func dial(addr string) (net.Conn, error) {
+	scheme, host, found := strings.Cut(addr, "://")
+	if !found {
+		scheme, host = "tcp", addr
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	switch scheme {
+	case "tcp", "mqtt":
+		if !strings.Contains(host, ":") {
+			host += ":1883"
+		}
+		return dialer.Dial("tcp", host)
+	case "ssl", "tls", "mqtts":
+		if !strings.Contains(host, ":") {
+			host += ":8883"
+		}
+		return tls.DialWithDialer(dialer, "tcp", host, nil)
+	default:
+		return nil, fmt.Errorf("unsupported broker scheme %q", scheme)
+	}
+}
+
+// connect sends CONNECT and waits for the broker to accept it
+func (c *Client) connect(opts Options) error {
+	var body bytes.Buffer
+	writeString(&body, "MQTT")
+	body.WriteByte(4) // protocol level of MQTT 3.1.1
+
+	flags := byte(flagCleanSession)
+	if opts.Username != "" {
+		flags |= flagUsername
+		if opts.Password != "" {
+			flags |= flagPassword
+		}
+	}
+	body.WriteByte(flags)
+	binary.Write(&body, binary.BigEndian, uint16(min(opts.KeepAlive/time.Second, 65535)))
+
+	writeString(&body, opts.ClientID)
+	if flags&flagUsername != 0 {
+		writeString(&body, opts.Username)
+	}
+	if flags&flagPassword != 0 {
+		writeString(&body, opts.Password)
+	}
+
+	if err := c.write(packetConnect, body.Bytes()); err != nil {
+		return fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	defer c.conn.SetReadDeadline(time.Time{})
+
+	var connack [4]byte
+	if _, err := io.ReadFull(c.conn, connack[:]); err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if connack[0] != packetConnack || connack[1] != 2 {
+		return fmt.Errorf("unexpected reply to CONNECT: %#x", connack[0])
+	}
+	if code := connack[3]; code != 0 {
+		if reason, ok := connackErrors[code]; ok {
+			return fmt.Errorf("connection refused: %s", reason)
+		}
+		return fmt.Errorf("connection refused: code %d", code)
+	}
+	return nil
+}
+
+// Publish sends payload to topic at QoS 0. Retained messages are kept by the
+// broker and delivered to clients that subscribe later.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	var body bytes.Buffer
+	writeString(&body, topic)
+	body.Write(payload)
+
+	header := byte(packetPublish)
+	if retain {
+		header |= 0x01
+	}
+	if err := c.write(header, body.Bytes()); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Close sends DISCONNECT and closes the connection
+func (c *Client) Close() error {
+	c.write(packetDisconnect, nil)
+	return c.conn.Close()
+}
+
+// write sends one packet with the given first header byte and body
+func (c *Client) write(header byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return fmt.Errorf("packet of %d bytes is too large", len(body))
+	}
+
+	packet := []byte{header}
+	// The remaining length is encoded 7 bits at a time, low bits first
+	for n := len(body); ; {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if n == 0 {
+			break
+		}
+	}
+	packet = append(packet, body...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+// writeString writes s as a length-prefixed UTF-8 string
+func writeString(b *bytes.Buffer, s string) {
+	binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
@@ -0,0 +1,155 @@
+package mqtt
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipeClient returns a client connected to the returned broker end of a pipe
+func pipeClient(t *testing.T) (*Client, net.Conn) {
+	t.Helper()
+	client, broker := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		broker.Close()
+	})
+	return &Client{conn: client}, broker
+}
+
+// readPacket reads len(want) bytes from broker, failing if they differ
+func readPacket(t *testing.T, broker net.Conn, want []byte) {
+	t.Helper()
+	broker.SetReadDeadline(time.Now().Add(time.Second))
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(broker, got); err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("packet\n% x\nwant\n% x", got, want)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	// Variable header shared by every CONNECT: protocol name and level
+	protocol := []byte{0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		body    []byte // after the protocol name and level
+		connack []byte
+		wantErr string
+	}{
+		{
+			name:    "client id only",
+			opts:    Options{ClientID: "tm", KeepAlive: time.Minute},
+			body:    []byte{0x02, 0x00, 0x3c, 0x00, 0x02, 't', 'm'},
+			connack: []byte{0x20, 0x02, 0x00, 0x00},
+		},
+		{
+			name:    "user name and password",
+			opts:    Options{ClientID: "tm", Username: "u", Password: "pw"},
+			body:    []byte{0xc2, 0x00, 0x00, 0x00, 0x02, 't', 'm', 0x00, 0x01, 'u', 0x00, 0x02, 'p', 'w'},
+			connack: []byte{0x20, 0x02, 0x00, 0x00},
+		},
+		{
+			name:    "password without user name is not sent",
+			opts:    Options{ClientID: "tm", Password: "pw"},
+			body:    []byte{0x02, 0x00, 0x00, 0x00, 0x02, 't', 'm'},
+			connack: []byte{0x20, 0x02, 0x00, 0x00},
+		},
+		{
+			name:    "keep alive is capped",
+			opts:    Options{ClientID: "", Username: "u", KeepAlive: 100 * time.Hour},
+			body:    []byte{0x82, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01, 'u'},
+			connack: []byte{0x20, 0x02, 0x00, 0x00},
+		},
+		{
+			name:    "refused",
+			opts:    Options{ClientID: "tm"},
+			body:    []byte{0x02, 0x00, 0x00, 0x00, 0x02, 't', 'm'},
+			connack: []byte{0x20, 0x02, 0x00, 0x05},
+			wantErr: "not authorized",
+		},
+		{
+			name:    "not a CONNACK",
+			opts:    Options{ClientID: "tm"},
+			body:    []byte{0x02, 0x00, 0x00, 0x00, 0x02, 't', 'm'},
+			connack: []byte{0x30, 0x02, 0x00, 0x00},
+			wantErr: "unexpected reply",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, broker := pipeClient(t)
+			errc := make(chan error, 1)
+			go func() { errc <- c.connect(tt.opts) }()
+
+			body := append(append([]byte{}, protocol...), tt.body...)
+			readPacket(t, broker, append([]byte{packetConnect, byte(len(body))}, body...))
+			if _, err := broker.Write(tt.connack); err != nil {
+				t.Fatal(err)
+			}
+
+			err := <-errc
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		topic   string
+		payload []byte
+		retain  bool
+		header  []byte // fixed header: packet type and remaining length
+	}{
+		{"empty payload", "t", nil, false, []byte{0x30, 0x03}},
+		{"retained", "a/b", []byte("hi"), true, []byte{0x31, 0x07}},
+		// Remaining lengths at the edges of each encoded byte count
+		{"127 bytes", "t", make([]byte, 124), false, []byte{0x30, 0x7f}},
+		{"128 bytes", "t", make([]byte, 125), false, []byte{0x30, 0x80, 0x01}},
+		{"16383 bytes", "t", make([]byte, 16380), false, []byte{0x30, 0xff, 0x7f}},
+		{"16384 bytes", "t", make([]byte, 16381), false, []byte{0x30, 0x80, 0x80, 0x01}},
+		{"2097152 bytes", "t", make([]byte, 2097149), false, []byte{0x30, 0x80, 0x80, 0x80, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, broker := pipeClient(t)
+			errc := make(chan error, 1)
+			go func() { errc <- c.Publish(tt.topic, tt.payload, tt.retain) }()
+
+			want := append([]byte{}, tt.header...)
+			want = append(want, 0x00, byte(len(tt.topic)))
+			want = append(want, tt.topic...)
+			want = append(want, tt.payload...)
+			readPacket(t, broker, want)
+			if err := <-errc; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	c, broker := pipeClient(t)
+	errc := make(chan error, 1)
+	go func() { errc <- c.Close() }()
+
+	readPacket(t, broker, []byte{packetDisconnect, 0x00})
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
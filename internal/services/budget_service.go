@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// BudgetInterval is how often CPU usage is sampled for resource budgets
+const BudgetInterval = 10 * time.Second
+
+// cpuSample is the cumulative CPU time of a process at the last budget sample
+type cpuSample struct {
+	createTime int64 // ms since epoch, to detect PID reuse
+	seconds    float64
+}
+
+// BudgetService tracks CPU time used per process name against daily budgets
+type BudgetService struct {
+	mu             sync.Mutex
+	processService *ProcessService
+	historyService *HistoryService
+	budgets        map[string]models.ResourceBudget // by process name
+
+	lastSample   map[int32]cpuSample
+	lastSampleAt time.Time
+	exceededOn   map[string]string // day each budget was last reported exceeded
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(processService *ProcessService, historyService *HistoryService, budgets []models.ResourceBudget) *BudgetService {
+	byName := make(map[string]models.ResourceBudget, len(budgets))
+	for _, budget := range budgets {
+		if budget.Action != models.BudgetKill {
+			budget.Action = models.BudgetAlert
+		}
+		byName[budget.Name] = budget
+	}
+
+	return &BudgetService{
+		processService: processService,
+		historyService: historyService,
+		budgets:        byName,
+		lastSample:     make(map[int32]cpuSample),
+		exceededOn:     make(map[string]string),
+	}
+}
+
+// HasBudgets reports whether any budget is configured
+func (bs *BudgetService) HasBudgets() bool {
+	return len(bs.budgets) > 0
+}
+
+// Sample records the CPU time used by budgeted processes since the last
+// sample, acts on exceeded budgets and returns those newly exceeded today or
+// whose processes were killed
+func (bs *BudgetService) Sample(now time.Time) ([]models.BudgetStatus, error) {
+	if !bs.HasBudgets() {
+		return nil, nil
+	}
+
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processes: %w", err)
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	pidsByName := make(map[string][]int32)
+	seen := make(map[int32]cpuSample)
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+		if _, ok := bs.budgets[name]; !ok {
+			continue
+		}
+		times, err := p.Times()
+		if err != nil {
+			continue
+		}
+		createTime, _ := p.CreateTime()
+
+		sample := cpuSample{createTime: createTime, seconds: times.User + times.System}
+		seen[p.Pid] = sample
+
+		// Exited processes waiting to be reaped cannot be killed again
+		if status, err := p.Status(); err != nil || len(status) == 0 || status[0] != process.Zombie {
+			pidsByName[name] = append(pidsByName[name], p.Pid)
+		}
+
+		// Count the time used since the last sample. A process seen for the
+		// first time only counts in full if it started after that sample.
+		var used float64
+		if prev, ok := bs.lastSample[p.Pid]; ok && prev.createTime == createTime {
+			used = sample.seconds - prev.seconds
+		} else if !bs.lastSampleAt.IsZero() && createTime > bs.lastSampleAt.UnixMilli() {
+			used = sample.seconds
+		}
+		if used > 0 {
+			bs.historyService.AddCPUUsage(name, used, now)
+		}
+	}
+	bs.lastSample = seen
+	bs.lastSampleAt = now
+
+	var exceeded []models.BudgetStatus
+	day := now.Format(usageDayLayout)
+	for _, budget := range bs.budgets {
+		status := bs.status(budget, now)
+		if !status.Exceeded {
+			continue
+		}
+		reported := bs.exceededOn[budget.Name] == day
+		bs.exceededOn[budget.Name] = day
+
+		// Kill budgets stay enforced for the rest of the day
+		if budget.Action == models.BudgetKill {
+			for _, pid := range pidsByName[budget.Name] {
+				if err := bs.processService.KillProcess(pid); err != nil {
+					status.Error = err.Error()
+					continue
+				}
+				status.Killed = append(status.Killed, pid)
+			}
+		}
+		if !reported || len(status.Killed) > 0 {
+			exceeded = append(exceeded, status)
+		}
+		if !reported {
+			bs.recordExceeded(status, now)
+		}
+	}
+
+	return exceeded, nil
+}
+
+// recordExceeded records a budget first exceeded today in the event log
+func (bs *BudgetService) recordExceeded(status models.BudgetStatus, now time.Time) {
+	bs.processService.RecordEvent(models.Event{
+		Time:  now,
+		Kind:  models.EventBudgetExceeded,
+		Level: models.EventWarning,
+		Message: fmt.Sprintf("Budget exceeded: %s used %.2f of %.2f CPU-hours today",
+			status.Budget.Name, status.UsedCPUHours, status.Budget.CPUHours),
+		Fields: map[string]string{
+			"name":      status.Budget.Name,
+			"cpu_hours": strconv.FormatFloat(status.UsedCPUHours, 'f', 2, 64),
+			"budget":    strconv.FormatFloat(status.Budget.CPUHours, 'f', 2, 64),
+			"action":    status.Budget.Action,
+		},
+	})
+}
+
+// Statuses returns the usage of every budget on the day of now
+func (bs *BudgetService) Statuses(now time.Time) []models.BudgetStatus {
+	statuses := make([]models.BudgetStatus, 0, len(bs.budgets))
+	for _, budget := range bs.budgets {
+		statuses = append(statuses, bs.status(budget, now))
+	}
+	slices.SortFunc(statuses, func(a, b models.BudgetStatus) int {
+		return strings.Compare(a.Budget.Name, b.Budget.Name)
+	})
+	return statuses
+}
+
+// status returns the usage of budget on the day of now
+func (bs *BudgetService) status(budget models.ResourceBudget, now time.Time) models.BudgetStatus {
+	used := bs.historyService.GetCPUUsage(budget.Name, now) / 3600
+	return models.BudgetStatus{
+		Budget:       budget,
+		UsedCPUHours: used,
+		Exceeded:     budget.CPUHours > 0 && used > budget.CPUHours,
+	}
+}
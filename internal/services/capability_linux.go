@@ -0,0 +1,23 @@
+package services
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// ioprioWhoProcess selects a single process for ioprio_get
+const ioprioWhoProcess = 1
+
+// probeAffinity reads the CPU affinity of the current process
+func probeAffinity() error {
+	var set unix.CPUSet
+	return unix.SchedGetaffinity(0, &set)
+}
+
+// probeIONice reads the I/O priority of the current process. x/sys has no
+// wrapper for ioprio_get, so it is called directly.
+func probeIONice() error {
+	if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
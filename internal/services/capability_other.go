@@ -0,0 +1,19 @@
+//go:build !linux
+
+package services
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// probeAffinity fails, as only Linux exposes CPU affinity through
+// sched_getaffinity
+func probeAffinity() error {
+	return fmt.Errorf("not available on %s", runtime.GOOS)
+}
+
+// probeIONice fails, as only Linux has I/O priorities
+func probeIONice() error {
+	return fmt.Errorf("not available on %s", runtime.GOOS)
+}
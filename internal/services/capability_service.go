@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// CapabilityService probes which optional process features the current OS supports
+type CapabilityService struct {
+	once         sync.Once
+	capabilities *models.Capabilities
+}
+
+// NewCapabilityService creates a new capability service
+func NewCapabilityService() *CapabilityService {
+	return &CapabilityService{}
+}
+
+// GetCapabilities returns the platform capabilities, probing them on first use
+func (cs *CapabilityService) GetCapabilities() *models.Capabilities {
+	cs.once.Do(func() {
+		cs.capabilities = cs.probe()
+	})
+	return cs.capabilities
+}
+
+// probe checks each capability against the running process
+func (cs *CapabilityService) probe() *models.Capabilities {
+	linuxOnly := func(name string) models.Capability {
+		if runtime.GOOS == "linux" {
+			return models.Capability{Name: name, Supported: true}
+		}
+		return models.Capability{Name: name, Reason: fmt.Sprintf("not available on %s", runtime.GOOS)}
+	}
+	probed := func(name string, err error) models.Capability {
+		if err != nil {
+			return models.Capability{Name: name, Reason: err.Error()}
+		}
+		return models.Capability{Name: name, Supported: true}
+	}
+
+	caps := &models.Capabilities{
+		Affinity:    probed("CPU affinity", probeAffinity()),
+		IONice:      probed("I/O priority", probeIONice()),
+		WaitChannel: linuxOnly("Wait channel"),
+		Containers:  linuxOnly("Container detection"),
+		Security:    linuxOnly("Security context"),
+		Connections: models.Capability{Name: "Network connections"},
+		IOCounters:  models.Capability{Name: "I/O counters"},
+		Scope:       detectScope(),
+	}
+
+	// macOS sandbox status is only exposed through the private sandbox_check API
+	if runtime.GOOS == "darwin" {
+		caps.Security.Reason = "sandbox status requires the sandbox_check API"
+	}
+
+	// Probe per-process APIs against ourselves; gopsutil reports
+	// unsupported platforms through errors rather than build tags
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		caps.Connections.Reason = err.Error()
+		caps.IOCounters.Reason = err.Error()
+		return caps
+	}
+
+	if _, err := self.Connections(); err != nil {
+		caps.Connections.Reason = err.Error()
+	} else {
+		caps.Connections.Supported = true
+	}
+
+	if _, err := self.IOCounters(); err != nil {
+		caps.IOCounters.Reason = err.Error()
+	} else {
+		caps.IOCounters.Supported = true
+	}
+
+	return caps
+}
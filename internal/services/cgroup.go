@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup hierarchies are mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupUnlimited is the least cgroup v1 memory limit taken as no limit; the
+// kernel reports an unset limit as the largest page-aligned int64
+const cgroupUnlimited = 1 << 62
+
+// cgroupLimits are the limits a process runs under, zero when unlimited
+type cgroupLimits struct {
+	cpu    float64 // CPUs' worth of time per period, e.g. 1.5
+	memory uint64  // bytes
+}
+
+// readCgroup returns the contents of /proc/<pid>/cgroup, empty where cgroups
+// are not available
+func readCgroup(pid int32) string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// cgroupLimitsOf returns the tightest limits set on the cgroups listed in
+// cgroup, the contents of /proc/<pid>/cgroup, or on their ancestors, which
+// bound their descendants too. Both cgroup v2 (memory.max, cpu.max) and the
+// v1 memory and cpu controllers are read. Inside a container the cgroup path
+// may not exist under the container's own mount, whose root is then its
+// cgroup, so missing directories are skipped on the way up. Limits are
+// looked up once per cgroup in cache.
+func cgroupLimitsOf(cgroup string, cache map[string]cgroupLimits) cgroupLimits {
+	if limits, ok := cache[cgroup]; ok {
+		return limits
+	}
+
+	var limits cgroupLimits
+	_, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	unified := err == nil
+	for _, line := range strings.Split(cgroup, "\n") {
+		// Lines look like "hierarchy-ID:controller-list:cgroup-path"
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		controllers, path := strings.Split(parts[1], ","), parts[2]
+
+		switch {
+		case parts[1] == "" && unified:
+			walkCgroup(cgroupRoot, path, func(dir string) {
+				limits.memory = tighterMemory(limits.memory, readCgroupValue(dir, "memory.max"))
+				limits.cpu = tighterCPU(limits.cpu, readCPUMax(dir))
+			})
+		case slices.Contains(controllers, "memory"):
+			walkCgroup(filepath.Join(cgroupRoot, parts[1]), path, func(dir string) {
+				if memory := readCgroupValue(dir, "memory.limit_in_bytes"); memory < cgroupUnlimited {
+					limits.memory = tighterMemory(limits.memory, memory)
+				}
+			})
+		case slices.Contains(controllers, "cpu"):
+			walkCgroup(filepath.Join(cgroupRoot, parts[1]), path, func(dir string) {
+				quota, err := strconv.ParseInt(readCgroupFile(dir, "cpu.cfs_quota_us"), 10, 64)
+				period := readCgroupValue(dir, "cpu.cfs_period_us")
+				if err == nil && quota > 0 && period > 0 {
+					limits.cpu = tighterCPU(limits.cpu, float64(quota)/float64(period))
+				}
+			})
+		}
+	}
+
+	cache[cgroup] = limits
+	return limits
+}
+
+// walkCgroup calls visit for the directory of path under mount and each of
+// its ancestors up to mount, skipping those that do not exist
+func walkCgroup(mount, path string, visit func(dir string)) {
+	for dir := filepath.Join(mount, path); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			visit(dir)
+		}
+		if dir == mount || !strings.HasPrefix(dir, mount) {
+			return
+		}
+	}
+}
+
+// readCPUMax reads a cgroup v2 cpu.max, "QUOTA PERIOD" or "max PERIOD", as
+// CPUs' worth of time, zero when unlimited
+func readCPUMax(dir string) float64 {
+	fields := strings.Fields(readCgroupFile(dir, "cpu.max"))
+	if len(fields) != 2 {
+		return 0
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readCgroupValue reads a number from a cgroup file, zero when it is "max"
+// or cannot be read
+func readCgroupValue(dir, name string) uint64 {
+	value, err := strconv.ParseUint(readCgroupFile(dir, name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// readCgroupFile reads a cgroup file without its trailing newline
+func readCgroupFile(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// tighterMemory returns the lower of two memory limits, zero meaning none
+func tighterMemory(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// tighterCPU returns the lower of two CPU limits, zero meaning none
+func tighterCPU(a, b float64) float64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// containerIDPattern matches the 64-character hex IDs used by container runtimes
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerRuntimes maps cgroup path markers to the runtime that creates them
+var containerRuntimes = []struct {
+	marker  string
+	runtime string
+}{
+	{"libpod-", "podman"},
+	{"cri-containerd-", "containerd"},
+	{"crio-", "cri-o"},
+	{"docker", "docker"},
+	{"kubepods", "kubernetes"},
+	{"containerd", "containerd"},
+	{"lxc", "lxc"},
+}
+
+// hostNamespaces are the namespaces a containerized process is checked for
+// sharing with the host
+var hostNamespaces = []string{"pid", "net", "ipc", "uts", "mnt"}
+
+// parseContainerCgroup extracts a container ID and runtime from /proc/<pid>/cgroup contents.
+// Detection relies on cgroup paths and is only available on Linux.
+func parseContainerCgroup(cgroup string) (string, string) {
+	for _, line := range strings.Split(cgroup, "\n") {
+		// Lines look like "hierarchy-ID:controller-list:cgroup-path"
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		path := parts[2]
+		id := containerIDPattern.FindString(path)
+		if id == "" {
+			continue
+		}
+
+		for _, cr := range containerRuntimes {
+			if strings.Contains(path, cr.marker) {
+				return id, cr.runtime
+			}
+		}
+		return id, "unknown"
+	}
+
+	return "", ""
+}
+
+// detectScope works out whether tappmanager itself runs in a container,
+// from the marker files Docker and Podman leave, the container variable
+// systemd-nspawn, LXC and Podman set, Kubernetes' service variables and its
+// own cgroup, and whether the host's processes are listed: only the host's
+// PID namespace has kernel threads, whose parent kthreadd is PID 2. gopsutil
+// reads processes from HOST_PROC when set, so that is checked rather than
+// /proc. The container's own limits come from its cgroup.
+func detectScope() models.Scope {
+	if runtime.GOOS != "linux" {
+		return models.Scope{HostProcesses: true}
+	}
+
+	cgroup := readCgroup(int32(os.Getpid()))
+	_, containerRuntime := parseContainerCgroup(cgroup)
+	scope := models.Scope{Runtime: containerRuntime}
+	switch {
+	case fileExists("/.dockerenv"):
+		scope.Runtime = "docker"
+	case fileExists("/run/.containerenv"):
+		scope.Runtime = "podman"
+	case os.Getenv("container") != "":
+		scope.Runtime = os.Getenv("container")
+	case scope.Runtime == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != "":
+		scope.Runtime = "kubernetes"
+	}
+	scope.Container = scope.Runtime != ""
+
+	procRoot := cmp.Or(os.Getenv("HOST_PROC"), "/proc")
+	comm, err := os.ReadFile(filepath.Join(procRoot, "2", "comm"))
+	scope.HostProcesses = !scope.Container || (err == nil && strings.TrimSpace(string(comm)) == "kthreadd")
+
+	if scope.Container {
+		limits := cgroupLimitsOf(cgroup, make(map[string]cgroupLimits))
+		scope.CPULimit, scope.MemoryLimit = limits.cpu, limits.memory
+	}
+	return scope
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// inspectContainerProcess reports whether a containerized process has every
+// capability, which is what a privileged container grants, and which
+// namespaces it shares with the host. tappmanager's own namespaces stand for
+// the host's. Reading another user's namespaces usually needs root; those
+// that cannot be read are not reported.
+func inspectContainerProcess(pid int32) (bool, []string) {
+	capabilities, _ := readEffectiveCapabilities(pid)
+	privileged := len(capabilities) == len(capabilityNames)
+
+	var shared []string
+	for _, ns := range hostNamespaces {
+		host, err := os.Readlink("/proc/self/ns/" + ns)
+		if err != nil {
+			continue
+		}
+		if target, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, ns)); err == nil && target == host {
+			shared = append(shared, ns)
+		}
+	}
+	return privileged, shared
+}
+
+// GetContainerStats aggregates CPU and memory usage per container, busiest first
+func (ps *ProcessService) GetContainerStats(processes []*models.ProcessInfo) []*models.ContainerStats {
+	containers := make(map[string]*models.ContainerStats)
+
+	for _, proc := range processes {
+		if proc.ContainerID == "" {
+			continue
+		}
+
+		stats, ok := containers[proc.ContainerID]
+		if !ok {
+			stats = &models.ContainerStats{
+				ID:      proc.ContainerID,
+				Runtime: proc.ContainerRuntime,
+			}
+			containers[proc.ContainerID] = stats
+		}
+
+		stats.ProcessCount++
+		stats.CPU += proc.CPU
+		stats.Memory += proc.Memory
+		stats.MemoryBytes += proc.MemoryBytes
+
+		stats.Privileged = stats.Privileged || proc.ContainerPrivileged
+		if len(proc.HostNamespaces) > 0 {
+			stats.HostNamespaceProcesses++
+			for _, ns := range proc.HostNamespaces {
+				if !slices.Contains(stats.HostNamespaces, ns) {
+					stats.HostNamespaces = append(stats.HostNamespaces, ns)
+				}
+			}
+		}
+	}
+
+	result := make([]*models.ContainerStats, 0, len(containers))
+	for _, stats := range containers {
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CPU > result[j].CPU
+	})
+
+	return result
+}
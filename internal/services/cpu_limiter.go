@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// limiterPeriod is the length of one run/stop cycle. Short cycles keep the
+// throttled process responsive; each cycle sends two signals.
+const limiterPeriod = 100 * time.Millisecond
+
+// limiterMeasureInterval is how often the effective CPU usage is measured
+const limiterMeasureInterval = time.Second
+
+// Bounds of the share of each cycle a limited process may run
+const (
+	MinCPULimit = 5
+	MaxCPULimit = 95
+)
+
+// limitedProcess is a process being throttled by the limiter
+type limitedProcess struct {
+	limit models.CPULimit
+	proc  *process.Process
+	stop  chan struct{}
+}
+
+// CPULimiter caps the CPU usage of processes by alternately stopping and
+// continuing them (SIGSTOP/SIGCONT on Unix)
+type CPULimiter struct {
+	mu      sync.Mutex
+	limited map[int32]*limitedProcess
+	ended   map[int32]models.CPULimit // limits dropped because signalling failed
+	wg      sync.WaitGroup
+}
+
+// NewCPULimiter creates a new CPU limiter
+func NewCPULimiter() *CPULimiter {
+	return &CPULimiter{
+		limited: make(map[int32]*limitedProcess),
+		ended:   make(map[int32]models.CPULimit),
+	}
+}
+
+// SetLimit starts limiting pid to run for percent of each cycle, or changes
+// the limit if it is already limited. The percentage is clamped to
+// MinCPULimit..MaxCPULimit.
+func (l *CPULimiter) SetLimit(pid int32, name string, percent int) error {
+	if int(pid) == os.Getpid() {
+		return fmt.Errorf("cannot limit the task manager itself")
+	}
+	percent = min(max(percent, MinCPULimit), MaxCPULimit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.ended, pid)
+	if lp, ok := l.limited[pid]; ok {
+		lp.limit.Percent = percent
+		return nil
+	}
+
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+	// Stop once up front so a missing permission is reported right away
+	if err := proc.Suspend(); err != nil {
+		return fmt.Errorf("failed to stop process %d: %w", pid, err)
+	}
+
+	lp := &limitedProcess{
+		limit: models.CPULimit{
+			PID:       pid,
+			Name:      name,
+			Percent:   percent,
+			StartedAt: time.Now(),
+		},
+		proc: proc,
+		stop: make(chan struct{}),
+	}
+	l.limited[pid] = lp
+
+	l.wg.Add(1)
+	go l.run(lp)
+	return nil
+}
+
+// RemoveLimit stops limiting pid and lets it run freely again
+func (l *CPULimiter) RemoveLimit(pid int32) {
+	l.mu.Lock()
+	lp, ok := l.limited[pid]
+	delete(l.limited, pid)
+	delete(l.ended, pid)
+	l.mu.Unlock()
+
+	if ok {
+		close(lp.stop)
+	}
+}
+
+// StopAll removes every limit and waits until all processes are continued.
+// It must be called before exiting so no process is left stopped.
+func (l *CPULimiter) StopAll() {
+	l.mu.Lock()
+	for pid, lp := range l.limited {
+		close(lp.stop)
+		delete(l.limited, pid)
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+}
+
+// Limit returns the limit on pid, if any. A limit that ended because the
+// process could not be signalled is returned with its Error set.
+func (l *CPULimiter) Limit(pid int32) (models.CPULimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lp, ok := l.limited[pid]; ok {
+		return lp.limit, true
+	}
+	limit, ok := l.ended[pid]
+	return limit, ok
+}
+
+// run throttles lp until its limit is removed or the process goes away,
+// always leaving the process running
+func (l *CPULimiter) run(lp *limitedProcess) {
+	defer l.wg.Done()
+	defer lp.proc.Resume()
+
+	lastCPU, _ := cpuSeconds(lp.proc)
+	lastMeasure := time.Now()
+
+	for {
+		l.mu.Lock()
+		percent := lp.limit.Percent
+		l.mu.Unlock()
+
+		running := limiterPeriod * time.Duration(percent) / 100
+		err := lp.proc.Resume()
+		if err == nil && !sleepUnlessStopped(lp.stop, running) {
+			return
+		}
+		if err == nil {
+			err = lp.proc.Suspend()
+		}
+		if err != nil {
+			// The process exited or can no longer be signalled
+			l.fail(lp, err)
+			return
+		}
+		if !sleepUnlessStopped(lp.stop, limiterPeriod-running) {
+			return
+		}
+
+		if elapsed := time.Since(lastMeasure); elapsed >= limiterMeasureInterval {
+			// IsRunning compares start times, so a reused PID is never signalled
+			if running, err := lp.proc.IsRunning(); err == nil && !running {
+				l.fail(lp, fmt.Errorf("process %d exited", lp.limit.PID))
+				return
+			}
+			if cpu, err := cpuSeconds(lp.proc); err == nil {
+				l.mu.Lock()
+				lp.limit.MeasuredCPU = (cpu - lastCPU) / elapsed.Seconds() * 100
+				l.mu.Unlock()
+				lastCPU = cpu
+			}
+			lastMeasure = time.Now()
+		}
+	}
+}
+
+// fail drops lp from the limiter after its process could not be signalled,
+// keeping the error for Limit to report
+func (l *CPULimiter) fail(lp *limitedProcess, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limited[lp.limit.PID] == lp {
+		delete(l.limited, lp.limit.PID)
+		lp.limit.Error = err.Error()
+		l.ended[lp.limit.PID] = lp.limit
+	}
+}
+
+// sleepUnlessStopped waits for d and reports false if stop was closed first
+func sleepUnlessStopped(stop <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// cpuSeconds returns the cumulative user and system CPU time of proc
+func cpuSeconds(proc *process.Process) (float64, error) {
+	times, err := proc.Times()
+	if err != nil {
+		return 0, err
+	}
+	return times.User + times.System, nil
+}
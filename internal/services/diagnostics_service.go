@@ -0,0 +1,71 @@
+package services
+
+import (
+	"runtime"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+)
+
+// DiagnosticsService reports on the health of the collector, storage, history and the app itself
+type DiagnosticsService struct {
+	processService *ProcessService
+	historyService *HistoryService
+	storage        storage.Storage
+	mqtt           *MQTTPublisher
+	grafana        *GrafanaAnnotator
+}
+
+// NewDiagnosticsService creates a new diagnostics service
+func NewDiagnosticsService(processService *ProcessService, historyService *HistoryService, storage storage.Storage) *DiagnosticsService {
+	return &DiagnosticsService{
+		processService: processService,
+		historyService: historyService,
+		storage:        storage,
+	}
+}
+
+// SetMQTTPublisher includes the state of publisher in the diagnostics
+func (ds *DiagnosticsService) SetMQTTPublisher(publisher *MQTTPublisher) {
+	ds.mqtt = publisher
+}
+
+// SetGrafanaAnnotator includes the state of annotator in the diagnostics
+func (ds *DiagnosticsService) SetGrafanaAnnotator(annotator *GrafanaAnnotator) {
+	ds.grafana = annotator
+}
+
+// PreviewPrune reports which stored files the retention policies would delete
+func (ds *DiagnosticsService) PreviewPrune() (*models.PruneReport, error) {
+	return ds.storage.Prune(true)
+}
+
+// GetDiagnostics collects the current collector, storage, history and runtime statistics
+func (ds *DiagnosticsService) GetDiagnostics() *models.Diagnostics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	diagnostics := &models.Diagnostics{
+		Collector: ds.processService.GetCollectorStats(),
+		Storage:   ds.storage.GetWriteStats(),
+		History:   ds.historyService.GetHistoryStats(),
+		Runtime: models.RuntimeStats{
+			HeapAlloc:  mem.HeapAlloc,
+			HeapSys:    mem.HeapSys,
+			Sys:        mem.Sys,
+			NumGC:      mem.NumGC,
+			Goroutines: runtime.NumGoroutine(),
+		},
+		CollectedAt: time.Now(),
+	}
+	if ds.mqtt != nil {
+		status := ds.mqtt.Status()
+		diagnostics.MQTT = &status
+	}
+	if ds.grafana != nil {
+		status := ds.grafana.Status()
+		diagnostics.Grafana = &status
+	}
+	return diagnostics
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+)
+
+// EventHistoryFile is the file in the data directory events are kept in
+const EventHistoryFile = "events.jsonl"
+
+// eventHistoryMaxSize is the size at which the history is rotated. One
+// older file is kept, so at most twice this much is stored.
+const eventHistoryMaxSize = 4 << 20
+
+// EventHistory keeps events in a file in the data directory, one JSON
+// object per line, so reports can list what happened in a time window
+type EventHistory struct {
+	mu          sync.Mutex
+	path        string
+	compression string // of the rotated file, one of the storage.Compression formats
+}
+
+// NewEventHistory creates a history kept in EventHistoryFile in dataDir.
+// The file is compressed with compression when it is rotated.
+func NewEventHistory(dataDir, compression string) *EventHistory {
+	return &EventHistory{path: filepath.Join(dataDir, EventHistoryFile), compression: compression}
+}
+
+// Record appends event to the history, rotating the file once it is full
+func (eh *EventHistory) Record(event models.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+
+	if info, err := os.Stat(eh.path); err == nil && info.Size() >= eventHistoryMaxSize {
+		if err := eh.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate event history: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(eh.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	file, err := os.OpenFile(eh.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open event history: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write event history: %w", err)
+	}
+	return file.Close()
+}
+
+// rotate moves the full history to its rotated file, compressed as
+// configured, replacing the file rotated before whatever its compression
+func (eh *EventHistory) rotate() error {
+	ext, err := storage.CompressionExt(eh.compression)
+	if err != nil {
+		return err
+	}
+	rotated := eh.path + ".1" + ext
+
+	if ext == "" {
+		if err := os.Rename(eh.path, rotated); err != nil {
+			return err
+		}
+	} else {
+		data, err := os.ReadFile(eh.path)
+		if err != nil {
+			return err
+		}
+		if data, err = storage.Compress(data, eh.compression); err != nil {
+			return err
+		}
+		if err := os.WriteFile(rotated, data, 0600); err != nil {
+			return err
+		}
+		if err := os.Remove(eh.path); err != nil {
+			return err
+		}
+	}
+
+	// Drop the file rotated under a previous compression setting
+	for _, file := range rotatedEventFiles(eh.path) {
+		if file != rotated {
+			os.Remove(file)
+		}
+	}
+	return nil
+}
+
+// rotatedEventFiles returns every path the history at path may have been
+// rotated to, one per compression format
+func rotatedEventFiles(path string) []string {
+	return []string{path + ".1", path + ".1.gz"}
+}
+
+// ReadEventHistory returns the events recorded in dataDir since the given
+// time, oldest first. Lines that cannot be parsed are skipped.
+func ReadEventHistory(dataDir string, since time.Time) ([]models.Event, error) {
+	path := filepath.Join(dataDir, EventHistoryFile)
+
+	var events []models.Event
+	for _, file := range append(rotatedEventFiles(path), path) {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event history: %w", err)
+		}
+		if data, err = storage.Decompress(data); err != nil {
+			return nil, fmt.Errorf("failed to read event history: %w", err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var event models.Event
+			if json.Unmarshal(scanner.Bytes(), &event) != nil || event.Time.Before(since) {
+				continue
+			}
+			events = append(events, event)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read event history: %w", err)
+		}
+	}
+	return events, nil
+}
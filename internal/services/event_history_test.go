@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+func TestEventHistoryRotation(t *testing.T) {
+	for _, compression := range []string{"none", "gzip"} {
+		t.Run(compression, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, EventHistoryFile)
+			since := time.Now().Add(-time.Hour)
+
+			// A full history, rotated the next time an event is recorded, and
+			// files rotated before under either setting
+			filler, err := json.Marshal(models.Event{Time: time.Now(), Message: "filler"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := eventHistoryMaxSize/len(filler) + 1
+			full := strings.Repeat(string(filler)+"\n", lines)
+			if err := os.WriteFile(path, []byte(full), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path+".1.gz", []byte("stale"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path+".1", []byte("stale"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			history := NewEventHistory(dir, compression)
+			if err := history.Record(models.Event{Time: time.Now(), Message: "new"}); err != nil {
+				t.Fatal(err)
+			}
+
+			rotated := path + ".1"
+			if compression == "gzip" {
+				rotated += ".gz"
+			}
+			for _, file := range rotatedEventFiles(path) {
+				_, err := os.Stat(file)
+				if file == rotated && err != nil {
+					t.Errorf("%s missing: %v", file, err)
+				}
+				if file != rotated && !os.IsNotExist(err) {
+					t.Errorf("%s left behind", file)
+				}
+			}
+			if info, err := os.Stat(rotated); err == nil && compression == "gzip" && info.Size() >= int64(len(full)) {
+				t.Errorf("rotated file is %d bytes, not compressed", info.Size())
+			}
+
+			// The rotated events are read back first, then the new one
+			events, err := ReadEventHistory(dir, since)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != lines+1 || events[0].Message != "filler" || events[lines].Message != "new" {
+				t.Fatalf("read %d events, want %d rotated and the new one", len(events), lines)
+			}
+		})
+	}
+}
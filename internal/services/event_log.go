@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// Destinations of the event log
+const (
+	EventLogSyslog   = "syslog"
+	EventLogJournald = "journald"
+)
+
+// syslogPaths are the local syslog sockets, tried in order
+var syslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// journaldSocket accepts messages in journald's native protocol
+const journaldSocket = "/run/systemd/journal/socket"
+
+// eventLogIdentifier tags every entry written to the system log
+const eventLogIdentifier = "tappmanager"
+
+// syslogFacilityUser is the syslog facility of the entries
+const syslogFacilityUser = 1
+
+// EventSink records events outside tappmanager
+type EventSink interface {
+	Record(event models.Event) error
+}
+
+// EventLog mirrors events to the host's syslog or journald
+type EventLog struct {
+	mu          sync.Mutex
+	destination string
+	conn        net.Conn
+}
+
+// NewEventLog connects to the system log named by destination, syslog or
+// journald. An empty destination returns nil, leaving events unrecorded.
+func NewEventLog(destination string) (*EventLog, error) {
+	if destination == "" {
+		return nil, nil
+	}
+
+	el := &EventLog{destination: destination}
+	conn, err := el.dial()
+	if err != nil {
+		return nil, err
+	}
+	el.conn = conn
+	return el, nil
+}
+
+// dial connects to the system log
+func (el *EventLog) dial() (net.Conn, error) {
+	switch el.destination {
+	case EventLogSyslog:
+		if runtime.GOOS == "windows" {
+			return nil, fmt.Errorf("syslog is not available on %s", runtime.GOOS)
+		}
+		for _, path := range syslogPaths {
+			for _, network := range []string{"unixgram", "unix"} {
+				if conn, err := net.Dial(network, path); err == nil {
+					return conn, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("no syslog socket found")
+
+	case EventLogJournald:
+		if runtime.GOOS != "linux" {
+			return nil, fmt.Errorf("journald is not available on %s", runtime.GOOS)
+		}
+		conn, err := net.Dial("unixgram", journaldSocket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to journald: %w", err)
+		}
+		return conn, nil
+
+	default:
+		return nil, fmt.Errorf("unknown event log %q, expected syslog or journald", el.destination)
+	}
+}
+
+// Record writes event to the system log, reconnecting once if the log was
+// restarted since the last event
+func (el *EventLog) Record(event models.Event) error {
+	var entry []byte
+	if el.destination == EventLogJournald {
+		entry = journaldEntry(event)
+	} else {
+		entry = syslogEntry(event)
+	}
+
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	if _, err := el.conn.Write(entry); err == nil {
+		return nil
+	}
+	conn, err := el.dial()
+	if err != nil {
+		return err
+	}
+	el.conn.Close()
+	el.conn = conn
+	_, err = el.conn.Write(entry)
+	return err
+}
+
+// Close disconnects from the system log
+func (el *EventLog) Close() error {
+	return el.conn.Close()
+}
+
+// syslogEntry formats event as a BSD syslog line, with its fields appended
+// to the message as key=value pairs
+func syslogEntry(event models.Event) []byte {
+	message := event.Message + " event=" + event.Kind
+	for _, key := range sortedKeys(event.Fields) {
+		value := event.Fields[key]
+		if value == "" || strings.ContainsAny(value, " \"=") {
+			value = strconv.Quote(value)
+		}
+		message += " " + key + "=" + value
+	}
+
+	priority := syslogFacilityUser*8 + eventSeverity(event)
+	return fmt.Appendf(nil, "<%d>%s %s[%d]: %s\n",
+		priority, event.Time.Format(time.Stamp), eventLogIdentifier, os.Getpid(), message)
+}
+
+// journaldEntry formats event in journald's native protocol, with each field
+// prefixed TAPPMANAGER_
+func journaldEntry(event models.Event) []byte {
+	var b bytes.Buffer
+	writeJournaldField(&b, "MESSAGE", event.Message)
+	writeJournaldField(&b, "PRIORITY", strconv.Itoa(eventSeverity(event)))
+	writeJournaldField(&b, "SYSLOG_IDENTIFIER", eventLogIdentifier)
+	writeJournaldField(&b, "TAPPMANAGER_EVENT", event.Kind)
+	for _, key := range sortedKeys(event.Fields) {
+		writeJournaldField(&b, "TAPPMANAGER_"+journaldFieldName(key), event.Fields[key])
+	}
+	return b.Bytes()
+}
+
+// writeJournaldField writes one field, using the length-prefixed form for
+// values spanning several lines
+func writeJournaldField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name + "=" + value + "\n")
+		return
+	}
+	b.WriteString(name + "\n")
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// journaldFieldName upper-cases key and replaces characters journald does
+// not allow in field names
+func journaldFieldName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// eventSeverity returns the syslog severity of event: warning or notice
+func eventSeverity(event models.Event) int {
+	if event.Level == models.EventWarning {
+		return 4
+	}
+	return 5
+}
+
+// sortedKeys returns the keys of fields in order, so entries read the same every time
+func sortedKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+)
+
+// ExportCheckInterval is how often export schedules are checked for due runs
+const ExportCheckInterval = 15 * time.Second
+
+// minExportInterval is the shortest interval a schedule may export at
+const minExportInterval = time.Minute
+
+// exportTimeout bounds each upload of a scheduled export
+const exportTimeout = 30 * time.Second
+
+// scheduledExport is a configured schedule and how it has run so far
+type scheduledExport struct {
+	every  time.Duration
+	filter *models.ProcessFilter
+	status models.ExportScheduleStatus
+}
+
+// ExportScheduler exports the filtered process list to a directory, an S3
+// bucket or an HTTP endpoint at each schedule's interval
+type ExportScheduler struct {
+	mu             sync.Mutex
+	processService *ProcessService
+	client         *http.Client
+	schedules      []*scheduledExport
+}
+
+// NewExportScheduler creates a scheduler for schedules, the first run of
+// each due one interval from now. Invalid schedules are kept with their
+// error so it can be shown, but never run.
+func NewExportScheduler(processService *ProcessService, schedules []models.ExportSchedule) *ExportScheduler {
+	now := time.Now()
+	es := &ExportScheduler{
+		processService: processService,
+		client:         &http.Client{Timeout: exportTimeout},
+	}
+	for i, schedule := range schedules {
+		if schedule.Name == "" {
+			schedule.Name = fmt.Sprintf("export %d", i+1)
+		}
+		if schedule.Format == "" {
+			schedule.Format = "csv"
+		}
+
+		scheduled := &scheduledExport{status: models.ExportScheduleStatus{Schedule: schedule}}
+		var err error
+		if scheduled.every, scheduled.filter, err = parseExportSchedule(schedule); err != nil {
+			scheduled.status.Error = err.Error()
+		} else {
+			scheduled.status.NextRun = now.Add(scheduled.every)
+		}
+		es.schedules = append(es.schedules, scheduled)
+	}
+	return es
+}
+
+// parseExportSchedule checks schedule and returns its interval and filter
+func parseExportSchedule(schedule models.ExportSchedule) (time.Duration, *models.ProcessFilter, error) {
+	every, err := time.ParseDuration(schedule.Every)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid interval %q: use a duration like 15m or 6h", schedule.Every)
+	}
+	if every < minExportInterval {
+		return 0, nil, fmt.Errorf("interval %s is shorter than %s", every, minExportInterval)
+	}
+
+	filter, err := ParseFilterExpr(schedule.Filter)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if schedule.Format != "csv" && schedule.Format != "json" {
+		return 0, nil, fmt.Errorf("unsupported export format: %s", schedule.Format)
+	}
+
+	dest := schedule.Destination
+	switch dest.Type {
+	case models.ExportToDir:
+		if dest.Path == "" {
+			return 0, nil, fmt.Errorf("dir destination needs a path")
+		}
+	case models.ExportToS3:
+		if dest.Bucket == "" {
+			return 0, nil, fmt.Errorf("s3 destination needs a bucket")
+		}
+	case models.ExportToHTTP:
+		if u, err := url.Parse(dest.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return 0, nil, fmt.Errorf("http destination needs an http or https URL")
+		}
+	default:
+		return 0, nil, fmt.Errorf("unknown destination type %q: use dir, s3 or http", dest.Type)
+	}
+	return every, filter, nil
+}
+
+// HasSchedules reports whether any export schedule is configured
+func (es *ExportScheduler) HasSchedules() bool {
+	return len(es.schedules) > 0
+}
+
+// RunDue runs every schedule due at now and returns their statuses
+func (es *ExportScheduler) RunDue(now time.Time) []models.ExportScheduleStatus {
+	// Move due schedules on before running them, so a slow upload is not
+	// started again by the next check
+	es.mu.Lock()
+	var due []*scheduledExport
+	for _, scheduled := range es.schedules {
+		if !scheduled.status.NextRun.IsZero() && !scheduled.status.NextRun.After(now) {
+			due = append(due, scheduled)
+			scheduled.status.NextRun = now.Add(scheduled.every)
+		}
+	}
+	es.mu.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	processes := es.processService.LastProcesses()
+	if processes == nil {
+		processes, _ = es.processService.GetProcesses()
+	}
+
+	statuses := make([]models.ExportScheduleStatus, 0, len(due))
+	for _, scheduled := range due {
+		target, count, err := es.export(scheduled, processes, now)
+
+		es.mu.Lock()
+		scheduled.status.LastRun = now
+		scheduled.status.Runs++
+		scheduled.status.Error = ""
+		if err != nil {
+			scheduled.status.Failures++
+			scheduled.status.Error = err.Error()
+		} else {
+			scheduled.status.LastTarget = target
+			scheduled.status.LastCount = count
+		}
+		statuses = append(statuses, scheduled.status)
+		es.mu.Unlock()
+	}
+	return statuses
+}
+
+// Statuses returns the status of every schedule in configuration order
+func (es *ExportScheduler) Statuses() []models.ExportScheduleStatus {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	statuses := make([]models.ExportScheduleStatus, len(es.schedules))
+	for i, scheduled := range es.schedules {
+		statuses[i] = scheduled.status
+	}
+	return statuses
+}
+
+// export filters and encodes processes for scheduled and sends them to its
+// destination, returning where they went and how many were exported
+func (es *ExportScheduler) export(scheduled *scheduledExport, processes []*models.ProcessInfo, now time.Time) (string, int, error) {
+	schedule := scheduled.status.Schedule
+	filter := *scheduled.filter
+	processes = es.processService.FilterProcesses(processes, &filter)
+	es.processService.SortProcesses(processes, &models.ProcessSort{Field: "cpu", Order: "desc"})
+
+	data, err := storage.EncodeProcesses(processes, schedule.Format)
+	if err != nil {
+		return "", 0, err
+	}
+	name := fmt.Sprintf("processes_%s_%s.%s", exportSlug(schedule.Name), now.Format("20060102_150405"), schedule.Format)
+	contentType := "text/csv"
+	if schedule.Format == "json" {
+		contentType = "application/json"
+	}
+
+	dest := schedule.Destination
+	var target string
+	switch dest.Type {
+	case models.ExportToDir:
+		target, err = writeExportFile(dest, name, data)
+	case models.ExportToS3:
+		target, err = es.putS3Object(dest, strings.TrimPrefix(dest.Prefix, "/")+name, data, contentType, now)
+	case models.ExportToHTTP:
+		target, err = es.postExport(dest, name, data, contentType)
+	}
+	return target, len(processes), err
+}
+
+// exportSlug turns a schedule name into something safe in file names and
+// object keys
+func exportSlug(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// writeExportFile writes an export to the destination directory, creating it if needed
+func writeExportFile(dest models.ExportDestination, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(dest.Path, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	path := filepath.Join(dest.Path, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write export file: %w", err)
+	}
+	return path, nil
+}
+
+// postExport POSTs an export to the destination URL with its configured
+// headers. The file name is sent in Content-Disposition.
+func (es *ExportScheduler) postExport(dest models.ExportDestination, name string, data []byte, contentType string) (string, error) {
+	req, err := http.NewRequest(http.MethodPost, dest.URL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create export request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	for key, value := range dest.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if err := es.send(req); err != nil {
+		return "", err
+	}
+	return dest.URL, nil
+}
+
+// putS3Object uploads an export to an S3 bucket, signing the request with
+// the credentials in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and optionally
+// AWS_SESSION_TOKEN. Buckets on AWS are addressed by host name, those on a
+// custom endpoint by path.
+func (es *ExportScheduler) putS3Object(dest models.ExportDestination, key string, data []byte, contentType string, now time.Time) (string, error) {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return "", fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for s3 exports")
+	}
+	region := cmp.Or(dest.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1")
+
+	objectURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", dest.Bucket, region, awsURIEncode(key, false))
+	if dest.Endpoint != "" {
+		objectURL = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(dest.Endpoint, "/"), awsURIEncode(dest.Bucket, true), awsURIEncode(key, false))
+	}
+
+	req, err := http.NewRequest(http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create export request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signAWSRequest(req, accessKey, secretKey, region, "s3", now)
+
+	if err := es.send(req); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", dest.Bucket, key), nil
+}
+
+// send sends req, treating any status other than 2xx as an error
+func (es *ExportScheduler) send(req *http.Request) error {
+	resp, err := es.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send export: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("export rejected: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// signAWSRequest signs req with AWS Signature Version 4. The host and
+// every header already set are signed, and the payload hash must be in
+// X-Amz-Content-Sha256.
+func signAWSRequest(req *http.Request, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	canonicalRequest, signedHeaders := awsCanonicalRequest(req)
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// awsCanonicalRequest returns the SigV4 canonical request of req, signing
+// the host and every header set, and the names of the signed headers
+func awsCanonicalRequest(req *http.Request) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		headers["x-amz-content-sha256"],
+	}, "\n")
+	return canonicalRequest, signedHeaders
+}
+
+// canonicalQuery encodes query parameters sorted by name as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, awsURIEncode(name, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// awsURIEncode percent-encodes everything but unreserved characters, and
+// slashes too when encodeSlash is set
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
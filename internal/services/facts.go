@@ -0,0 +1,69 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// CollectFacts gathers the host information, headline metrics, status of the
+// watched process names and listening ports in the stable Facts schema.
+// Parts that cannot be read are left empty rather than failing the whole
+// document.
+func CollectFacts(processService *ProcessService, systemService *SystemService, watch []string) (*models.Facts, error) {
+	processes, err := processService.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	facts := &models.Facts{
+		SchemaVersion:  models.FactsSchemaVersion,
+		CollectedAt:    time.Now(),
+		Processes:      watchedProcesses(processes, watch),
+		ListeningPorts: []models.ListeningPort{},
+	}
+
+	hostname := ""
+	if info, err := systemService.GetSystemInfo(); err == nil {
+		facts.Host = info
+		hostname = info.Hostname
+	}
+	facts.Metrics = summarize(processes, hostname)
+
+	if ports, err := processService.GetListeningPorts(processes); err == nil {
+		facts.ListeningPorts = ports
+	}
+	return facts, nil
+}
+
+// watchedProcesses reports, for each name in watch, the processes with
+// exactly that name
+func watchedProcesses(processes []*models.ProcessInfo, watch []string) []models.WatchedProcess {
+	watched := make([]models.WatchedProcess, 0, len(watch))
+	index := make(map[string]int, len(watch))
+	for _, name := range watch {
+		if _, ok := index[name]; !ok {
+			index[name] = len(watched)
+			watched = append(watched, models.WatchedProcess{Name: name, PIDs: []int32{}})
+		}
+	}
+
+	for _, proc := range processes {
+		i, ok := index[proc.Name]
+		if !ok {
+			continue
+		}
+		w := &watched[i]
+		w.Running = true
+		w.Count++
+		w.PIDs = append(w.PIDs, proc.PID)
+		w.CPU += proc.CPU
+		w.Memory += proc.Memory
+	}
+
+	for i := range watched {
+		slices.Sort(watched[i].PIDs)
+	}
+	return watched
+}
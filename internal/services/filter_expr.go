@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// ParseFilterExpr parses a filter expression such as "user:me cpu:5 nginx".
+// Supported terms are user:NAME (me for the current user), status:STATUS,
+// cpu:MIN, mem:MIN, dir:PATH, session:ID, system:BOOL and virtualized:BOOL
+// (true for only virtualized processes, false to hide them); any other words
+// become the search term. A leading "-" excludes instead: -user:NAME and
+// -name:NAME hide a user's processes or processes with that name, and other
+// words starting with "-" become the exclude search term.
+func ParseFilterExpr(expr string) (*models.ProcessFilter, error) {
+	filter := &models.ProcessFilter{}
+	var search, exclude []string
+
+	for _, term := range strings.Fields(expr) {
+		if negated, found := strings.CutPrefix(term, "-"); found && negated != "" {
+			key, value, ok := strings.Cut(negated, ":")
+			switch {
+			case !ok:
+				exclude = append(exclude, negated)
+			case key == "user":
+				filter.ExcludeUsers = append(filter.ExcludeUsers, currentUsername(value))
+			case key == "name":
+				filter.ExcludeNames = append(filter.ExcludeNames, value)
+			default:
+				return nil, fmt.Errorf("invalid filter term %q: only user: and name: can be excluded", term)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(term, ":")
+		if !ok {
+			search = append(search, term)
+			continue
+		}
+
+		var err error
+		switch key {
+		case "user":
+			filter.Username = currentUsername(value)
+		case "status":
+			filter.Status = value
+		case "cpu":
+			filter.MinCPU, err = strconv.ParseFloat(value, 64)
+		case "mem":
+			filter.MinMemory, err = strconv.ParseFloat(value, 64)
+		case "dir":
+			filter.PathPrefix = value
+		case "session":
+			var id int64
+			id, err = strconv.ParseInt(value, 10, 32)
+			filter.SessionID = int32(id)
+		case "system":
+			filter.ShowSystem, err = strconv.ParseBool(value)
+		case "virtualized":
+			var only bool
+			only, err = strconv.ParseBool(value)
+			filter.Virtualized = models.VirtualizedHide
+			if only {
+				filter.Virtualized = models.VirtualizedOnly
+			}
+		default:
+			search = append(search, term)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter term %q: %w", term, err)
+		}
+	}
+
+	filter.SearchTerm = strings.Join(search, " ")
+	filter.ExcludeSearchTerm = strings.Join(exclude, " ")
+	return filter, nil
+}
+
+// currentUsername resolves "me" to the current user's name
+func currentUsername(name string) string {
+	if name == "me" {
+		if current, err := user.Current(); err == nil {
+			return current.Username
+		}
+	}
+	return name
+}
+
+// IsValidSortField reports whether processes can be sorted by field
+func IsValidSortField(field string) bool {
+	return processComparator(field, "desc") != nil
+}
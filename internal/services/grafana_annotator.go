@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// grafanaTimeout bounds each request to Grafana
+const grafanaTimeout = 10 * time.Second
+
+// grafanaEventQueue is how many events may wait to be pushed before new ones
+// are dropped
+const grafanaEventQueue = 64
+
+// GrafanaAnnotator pushes events to Grafana's annotations API, so kills and
+// budget alerts show up as markers on dashboards. Each annotation carries a
+// snapshot of the host's headline metrics when the event happened.
+type GrafanaAnnotator struct {
+	config         models.GrafanaConfig
+	url            string
+	processService *ProcessService
+	hostname       string
+	client         *http.Client
+
+	events chan models.Event
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	status models.GrafanaStatus
+}
+
+// NewGrafanaAnnotator creates an annotator for config. It returns nil when no
+// Grafana URL is configured.
+func NewGrafanaAnnotator(processService *ProcessService, config models.GrafanaConfig) *GrafanaAnnotator {
+	if config.URL == "" {
+		return nil
+	}
+
+	hostname, _ := os.Hostname()
+	return &GrafanaAnnotator{
+		config:         config,
+		url:            strings.TrimSuffix(config.URL, "/") + "/api/annotations",
+		processService: processService,
+		hostname:       hostname,
+		client:         &http.Client{Timeout: grafanaTimeout},
+		events:         make(chan models.Event, grafanaEventQueue),
+		stop:           make(chan struct{}),
+		status:         models.GrafanaStatus{URL: config.URL},
+	}
+}
+
+// Start pushes events as they are recorded
+func (ga *GrafanaAnnotator) Start() {
+	ga.wg.Add(1)
+	go ga.run()
+}
+
+// Stop pushes the events still queued and returns
+func (ga *GrafanaAnnotator) Stop() {
+	close(ga.stop)
+	ga.wg.Wait()
+}
+
+// Record queues event to be pushed, dropping it when the queue is full so a
+// slow Grafana never holds up the action that raised it
+func (ga *GrafanaAnnotator) Record(event models.Event) error {
+	select {
+	case ga.events <- event:
+		return nil
+	default:
+		ga.mu.Lock()
+		ga.status.Dropped++
+		ga.mu.Unlock()
+		return fmt.Errorf("Grafana event queue is full")
+	}
+}
+
+// Status returns the push counts and the last error
+func (ga *GrafanaAnnotator) Status() models.GrafanaStatus {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	return ga.status
+}
+
+// run pushes events until Stop is called
+func (ga *GrafanaAnnotator) run() {
+	defer ga.wg.Done()
+
+	for {
+		select {
+		case <-ga.stop:
+			for {
+				select {
+				case event := <-ga.events:
+					ga.recordResult(ga.push(event))
+				default:
+					return
+				}
+			}
+		case event := <-ga.events:
+			ga.recordResult(ga.push(event))
+		}
+	}
+}
+
+// push creates the annotation for event
+func (ga *GrafanaAnnotator) push(event models.Event) error {
+	annotation := struct {
+		Time         int64    `json:"time"` // milliseconds since the epoch
+		Tags         []string `json:"tags"`
+		Text         string   `json:"text"`
+		DashboardUID string   `json:"dashboardUID,omitempty"`
+	}{
+		Time:         event.Time.UnixMilli(),
+		Tags:         append(append([]string{}, ga.config.Tags...), event.Kind, event.Level, "host:"+ga.hostname),
+		Text:         ga.annotationText(event),
+		DashboardUID: ga.config.DashboardUID,
+	}
+	body, err := json.Marshal(annotation)
+	if err != nil {
+		return fmt.Errorf("failed to encode annotation: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ga.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create annotation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ga.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+ga.config.Token)
+	}
+
+	resp, err := ga.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push annotation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to push annotation: %s", resp.Status)
+	}
+	return nil
+}
+
+// annotationText describes event, its fields and the state of the host
+func (ga *GrafanaAnnotator) annotationText(event models.Event) string {
+	lines := []string{event.Message}
+	for _, key := range sortedKeys(event.Fields) {
+		lines = append(lines, key+": "+event.Fields[key])
+	}
+
+	// The snapshot is best effort; the event matters more
+	if processes, err := ga.processService.GetProcesses(); err == nil {
+		summary := summarize(processes, ga.hostname)
+		lines = append(lines, fmt.Sprintf("%s: %d processes, %.1f%% CPU, %.1f%% memory, load %.2f %.2f %.2f",
+			ga.hostname, summary.Processes, summary.CPU, summary.Memory, summary.Load1, summary.Load5, summary.Load15))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recordResult updates the status after a push
+func (ga *GrafanaAnnotator) recordResult(err error) {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+
+	if err != nil {
+		ga.status.Failed++
+		ga.status.LastError = err.Error()
+		ga.status.LastErrorAt = time.Now()
+		return
+	}
+	ga.status.Pushed++
+	ga.status.LastPushAt = time.Now()
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// HashCheckInterval is how often the executables of new processes are
+// checked against the allowlist
+const HashCheckInterval = 30 * time.Second
+
+// binaryKey identifies a process, the creation time guarding against PID reuse
+type binaryKey struct {
+	pid        int32
+	createTime int64
+}
+
+// fileHash is the hash of an executable as of its size and modification time
+type fileHash struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+// HashChecker flags processes whose executable's SHA-256 hash is not in a
+// local allowlist file. Each executable is hashed once, when the first
+// process running it is seen, and again only if it changes on disk.
+type HashChecker struct {
+	mu         sync.Mutex
+	path       string
+	allowed    map[string]bool      // nil until the allowlist is loaded
+	files      map[string]fileHash  // by executable path
+	checked    map[binaryKey]string // hash of each process's executable, "" if unreadable
+	unknown    map[binaryKey]models.UnknownBinary
+	unreadable int
+}
+
+// NewHashChecker creates a checker for the allowlist at path. An empty path
+// disables hash checks.
+func NewHashChecker(path string) *HashChecker {
+	return &HashChecker{
+		path:    path,
+		files:   make(map[string]fileHash),
+		checked: make(map[binaryKey]string),
+		unknown: make(map[binaryKey]models.UnknownBinary),
+	}
+}
+
+// Enabled reports whether hash checks are turned on
+func (hc *HashChecker) Enabled() bool {
+	return hc.path != ""
+}
+
+// Path returns the allowlist file
+func (hc *HashChecker) Path() string {
+	return hc.path
+}
+
+// Load reads the allowlist. It has one hash per line, optionally followed
+// by the file it belongs to, so the output of sha256sum can be used as is.
+// Empty lines and lines starting with # are ignored. A missing file is an
+// empty allowlist.
+func (hc *HashChecker) Load() error {
+	if !hc.Enabled() {
+		return nil
+	}
+
+	allowed := make(map[string]bool)
+	file, err := os.Open(hc.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		hc.setAllowed(allowed)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open hash allowlist: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		sum := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+			return fmt.Errorf("%s:%d: %q is not a SHA-256 hash", hc.path, line, fields[0])
+		}
+		allowed[sum] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read hash allowlist: %w", err)
+	}
+
+	hc.setAllowed(allowed)
+	return nil
+}
+
+// setAllowed replaces the allowlist, dropping flags on binaries now allowed
+func (hc *HashChecker) setAllowed(allowed map[string]bool) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	hc.allowed = allowed
+	for key, binary := range hc.unknown {
+		if allowed[binary.SHA256] {
+			delete(hc.unknown, key)
+		}
+	}
+}
+
+// Check hashes the executables of processes not seen before and flags those
+// not in the allowlist. It returns the binaries newly flagged. Nothing is
+// flagged until the allowlist has been loaded. Executables are hashed
+// without holding the lock, so reading large binaries does not hold up the
+// Security view.
+func (hc *HashChecker) Check(processes []*models.ProcessInfo, now time.Time) []models.UnknownBinary {
+	if !hc.Enabled() {
+		return nil
+	}
+
+	// Find the processes not checked yet, with what is known of their files
+	hc.mu.Lock()
+	if hc.allowed == nil {
+		hc.mu.Unlock()
+		return nil
+	}
+	seen := make(map[binaryKey]bool, len(processes))
+	var pending []*models.ProcessInfo
+	files := make(map[string]fileHash)
+	for _, proc := range processes {
+		if proc.Executable == "" {
+			continue
+		}
+		key := binaryKey{proc.PID, proc.CreateTime.UnixMilli()}
+		seen[key] = true
+		if _, ok := hc.checked[key]; ok {
+			continue
+		}
+		pending = append(pending, proc)
+		if cached, ok := hc.files[proc.Executable]; ok {
+			files[proc.Executable] = cached
+		}
+	}
+	hc.mu.Unlock()
+
+	sums := make([]string, len(pending))
+	for i, proc := range pending {
+		sums[i] = hashExecutable(proc, files)
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	maps.Copy(hc.files, files)
+	var flagged []models.UnknownBinary
+	for i, proc := range pending {
+		key := binaryKey{proc.PID, proc.CreateTime.UnixMilli()}
+		if _, ok := hc.checked[key]; ok {
+			// Checked meanwhile by another call
+			continue
+		}
+		sum := sums[i]
+		hc.checked[key] = sum
+		if sum != "" && !hc.allowed[sum] {
+			binary := models.UnknownBinary{
+				PID:        proc.PID,
+				Name:       proc.Name,
+				Username:   proc.Username,
+				Executable: proc.Executable,
+				SHA256:     sum,
+				CreateTime: proc.CreateTime,
+				FirstSeen:  now,
+			}
+			hc.unknown[key] = binary
+			flagged = append(flagged, binary)
+		}
+	}
+
+	// Forget processes that exited
+	hc.unreadable = 0
+	for key, sum := range hc.checked {
+		switch {
+		case !seen[key]:
+			delete(hc.checked, key)
+			delete(hc.unknown, key)
+		case sum == "":
+			hc.unreadable++
+		}
+	}
+	return flagged
+}
+
+// hashExecutable returns the SHA-256 hash of proc's executable, or "" if it
+// cannot be read. On Linux the file is read through /proc, so the binary
+// the process actually runs is hashed even if it was replaced or deleted.
+// Files are looked up in and added to files by executable path, so each is
+// read again only if its size or modification time changed.
+func hashExecutable(proc *models.ProcessInfo, files map[string]fileHash) string {
+	path := proc.Executable
+	if runtime.GOOS == "linux" {
+		path = fmt.Sprintf("/proc/%d/exe", proc.PID)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ""
+	}
+	if cached, ok := files[proc.Executable]; ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sum
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	files[proc.Executable] = fileHash{size: info.Size(), modTime: info.ModTime(), sum: sum}
+	return sum
+}
+
+// Unknown returns the running processes whose executable is not in the
+// allowlist, most recently flagged first
+func (hc *HashChecker) Unknown() []models.UnknownBinary {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	unknown := make([]models.UnknownBinary, 0, len(hc.unknown))
+	for _, binary := range hc.unknown {
+		unknown = append(unknown, binary)
+	}
+	slices.SortFunc(unknown, func(a, b models.UnknownBinary) int {
+		if c := b.FirstSeen.Compare(a.FirstSeen); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Executable, b.Executable); c != 0 {
+			return c
+		}
+		return int(a.PID - b.PID)
+	})
+	return unknown
+}
+
+// Unreadable returns how many running processes' executables could not be
+// read, usually because they belong to other users
+func (hc *HashChecker) Unreadable() int {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.unreadable
+}
+
+// Allow adds the hash of binary to the allowlist file, creating it if
+// needed, and clears the flag on every process running it
+func (hc *HashChecker) Allow(binary models.UnknownBinary) error {
+	if !hc.Enabled() {
+		return fmt.Errorf("no hash allowlist is configured")
+	}
+
+	file, err := os.OpenFile(hc.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open hash allowlist: %w", err)
+	}
+	// Same layout as sha256sum, so the file can be checked with sha256sum -c
+	if _, err := fmt.Fprintf(file, "%s  %s\n", binary.SHA256, binary.Executable); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write hash allowlist: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write hash allowlist: %w", err)
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	if hc.allowed == nil {
+		hc.allowed = make(map[string]bool)
+	}
+	hc.allowed[binary.SHA256] = true
+	for key, flagged := range hc.unknown {
+		if flagged.SHA256 == binary.SHA256 {
+			delete(hc.unknown, key)
+		}
+	}
+	return nil
+}
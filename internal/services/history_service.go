@@ -0,0 +1,315 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// Default limits of the in-memory history
+const (
+	DefaultHistoryBudget    = 1 << 20 // bytes
+	DefaultHistoryRetention = 24 * time.Hour
+)
+
+// LoadSampleInterval is how often load averages are recorded in the history
+const LoadSampleInterval = 5 * time.Second
+
+// Samples are kept at full resolution for rawHistoryWindow, then averaged
+// into mediumResolution buckets until mediumHistoryWindow and into
+// coarseResolution buckets after that
+const (
+	rawHistoryWindow    = 10 * time.Minute
+	mediumHistoryWindow = time.Hour
+	mediumResolution    = 10 * time.Second
+	coarseResolution    = time.Minute
+)
+
+// loadSampleSize is the in-memory size of a single load sample
+const loadSampleSize = uint64(unsafe.Sizeof(models.LoadSample{}))
+
+// LoadHistorySource supplies load samples recorded elsewhere, such as by a daemon
+type LoadHistorySource interface {
+	LoadHistory(since time.Time) ([]models.LoadSample, error)
+}
+
+// HistoryService keeps a rolling in-memory history of system measurements
+type HistoryService struct {
+	mu sync.RWMutex
+
+	// Load samples by resolution, each oldest first; coarse samples are the oldest overall
+	loadSamples   []models.LoadSample
+	mediumSamples []models.LoadSample
+	coarseSamples []models.LoadSample
+
+	// CPU seconds used per process name, by local calendar day
+	cpuUsage map[string]map[string]float64
+
+	budget    uint64
+	retention time.Duration
+
+	// remote, when set, is asked for new load samples before sampling locally
+	remote LoadHistorySource
+}
+
+// usageDayLayout keys CPU usage by local calendar day
+const usageDayLayout = "2006-01-02"
+
+// NewHistoryService creates a new history service
+func NewHistoryService() *HistoryService {
+	return &HistoryService{
+		loadSamples: []models.LoadSample{},
+		cpuUsage:    make(map[string]map[string]float64),
+		budget:      DefaultHistoryBudget,
+		retention:   DefaultHistoryRetention,
+	}
+}
+
+// SetLimits changes the memory budget in bytes and the retention window.
+// Zero values keep the defaults.
+func (hs *HistoryService) SetLimits(budget uint64, retention time.Duration) {
+	if budget == 0 {
+		budget = DefaultHistoryBudget
+	}
+	if retention <= 0 {
+		retention = DefaultHistoryRetention
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.budget = budget
+	hs.retention = retention
+	if n := len(hs.loadSamples); n > 0 {
+		hs.compact(hs.loadSamples[n-1].Timestamp)
+	}
+}
+
+// SetRemote makes SampleLoad copy new samples from source, falling back to
+// sampling locally whenever source fails. It must be called before the
+// service is used.
+func (hs *HistoryService) SetRemote(source LoadHistorySource) {
+	hs.remote = source
+}
+
+// SampleLoad records the current load averages and run-queue length
+func (hs *HistoryService) SampleLoad() (models.LoadSample, error) {
+	if hs.remote != nil {
+		if sample, err := hs.copyRemote(); err == nil {
+			return sample, nil
+		}
+	}
+
+	avg, err := load.Avg()
+	if err != nil {
+		return models.LoadSample{}, fmt.Errorf("failed to get load average: %w", err)
+	}
+
+	sample := models.LoadSample{
+		Timestamp: time.Now(),
+		Load1:     avg.Load1,
+		Load5:     avg.Load5,
+		Load15:    avg.Load15,
+	}
+
+	// Run-queue information is not available on every platform
+	if misc, err := load.Misc(); err == nil {
+		sample.ProcsRunning = misc.ProcsRunning
+		sample.ProcsBlocked = misc.ProcsBlocked
+	}
+
+	hs.AddLoadSample(sample)
+	return sample, nil
+}
+
+// copyRemote appends the samples the remote source took after the latest
+// local one and returns the latest sample
+func (hs *HistoryService) copyRemote() (models.LoadSample, error) {
+	hs.mu.RLock()
+	latest := hs.latestSample()
+	hs.mu.RUnlock()
+
+	samples, err := hs.remote.LoadHistory(latest.Timestamp)
+	if err != nil {
+		return models.LoadSample{}, err
+	}
+	if len(samples) == 0 {
+		return latest, nil
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.loadSamples = append(hs.loadSamples, samples...)
+	latest = samples[len(samples)-1]
+	hs.compact(latest.Timestamp)
+	return latest, nil
+}
+
+// latestSample returns the newest sample at any resolution.
+// Callers must hold the lock.
+func (hs *HistoryService) latestSample() models.LoadSample {
+	for _, tier := range [][]models.LoadSample{hs.loadSamples, hs.mediumSamples, hs.coarseSamples} {
+		if n := len(tier); n > 0 {
+			return tier[n-1]
+		}
+	}
+	return models.LoadSample{}
+}
+
+// AddLoadSample appends a load sample, downsampling and dropping old samples
+// to stay within the retention window and memory budget
+func (hs *HistoryService) AddLoadSample(sample models.LoadSample) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.loadSamples = append(hs.loadSamples, sample)
+	hs.compact(sample.Timestamp)
+}
+
+// compact moves aged samples to coarser resolutions and enforces the limits.
+// Callers must hold the write lock.
+func (hs *HistoryService) compact(now time.Time) {
+	var aged []models.LoadSample
+	hs.loadSamples, aged = splitAged(hs.loadSamples, now.Add(-rawHistoryWindow), mediumResolution)
+	hs.mediumSamples = append(hs.mediumSamples, downsample(aged, mediumResolution)...)
+
+	hs.mediumSamples, aged = splitAged(hs.mediumSamples, now.Add(-mediumHistoryWindow), coarseResolution)
+	hs.coarseSamples = append(hs.coarseSamples, downsample(aged, coarseResolution)...)
+
+	// Drop everything past the retention window
+	cutoff := now.Add(-hs.retention)
+	hs.coarseSamples = dropBefore(hs.coarseSamples, cutoff)
+	hs.mediumSamples = dropBefore(hs.mediumSamples, cutoff)
+	hs.loadSamples = dropBefore(hs.loadSamples, cutoff)
+
+	// Then drop the oldest samples until the history fits the budget
+	if excess := hs.sampleCount() - int(hs.budget/loadSampleSize); excess > 0 {
+		for _, tier := range []*[]models.LoadSample{&hs.coarseSamples, &hs.mediumSamples, &hs.loadSamples} {
+			n := min(excess, len(*tier))
+			*tier = (*tier)[n:]
+			excess -= n
+		}
+	}
+}
+
+// sampleCount returns the number of samples across all resolutions
+func (hs *HistoryService) sampleCount() int {
+	return len(hs.loadSamples) + len(hs.mediumSamples) + len(hs.coarseSamples)
+}
+
+// splitAged splits samples into those still recent and those whose whole
+// resolution bucket lies before cutoff, so a bucket is never split
+func splitAged(samples []models.LoadSample, cutoff time.Time, resolution time.Duration) (recent, aged []models.LoadSample) {
+	i := 0
+	for i < len(samples) && !samples[i].Timestamp.Truncate(resolution).Add(resolution).After(cutoff) {
+		i++
+	}
+	return samples[i:], samples[:i]
+}
+
+// downsample averages samples, which must be oldest first, into buckets of resolution
+func downsample(samples []models.LoadSample, resolution time.Duration) []models.LoadSample {
+	var result []models.LoadSample
+	for start := 0; start < len(samples); {
+		bucket := samples[start].Timestamp.Truncate(resolution)
+		end := start
+		avg := models.LoadSample{Timestamp: bucket}
+		for end < len(samples) && samples[end].Timestamp.Truncate(resolution).Equal(bucket) {
+			avg.Load1 += samples[end].Load1
+			avg.Load5 += samples[end].Load5
+			avg.Load15 += samples[end].Load15
+			avg.ProcsRunning += samples[end].ProcsRunning
+			avg.ProcsBlocked += samples[end].ProcsBlocked
+			end++
+		}
+
+		n := end - start
+		avg.Load1 /= float64(n)
+		avg.Load5 /= float64(n)
+		avg.Load15 /= float64(n)
+		avg.ProcsRunning /= n
+		avg.ProcsBlocked /= n
+		result = append(result, avg)
+		start = end
+	}
+	return result
+}
+
+// dropBefore removes samples, which must be oldest first, taken before cutoff
+func dropBefore(samples []models.LoadSample, cutoff time.Time) []models.LoadSample {
+	i := 0
+	for i < len(samples) && samples[i].Timestamp.Before(cutoff) {
+		i++
+	}
+	return samples[i:]
+}
+
+// AddCPUUsage adds CPU seconds used by processes named name on the day of at,
+// forgetting days past the retention window
+func (hs *HistoryService) AddCPUUsage(name string, seconds float64, at time.Time) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	day := at.Format(usageDayLayout)
+	if hs.cpuUsage[day] == nil {
+		hs.cpuUsage[day] = make(map[string]float64)
+	}
+	hs.cpuUsage[day][name] += seconds
+
+	// Days are kept whole, so today survives any retention window
+	cutoff := at.Add(-hs.retention).Format(usageDayLayout)
+	for old := range hs.cpuUsage {
+		if old < cutoff {
+			delete(hs.cpuUsage, old)
+		}
+	}
+}
+
+// GetCPUUsage returns the CPU seconds used by processes named name on the day of at
+func (hs *HistoryService) GetCPUUsage(name string, at time.Time) float64 {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return hs.cpuUsage[at.Format(usageDayLayout)][name]
+}
+
+// GetLoadHistory returns a copy of the recorded load samples, oldest first.
+// Older samples have a coarser resolution.
+func (hs *HistoryService) GetLoadHistory() []models.LoadSample {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	samples := make([]models.LoadSample, 0, hs.sampleCount())
+	samples = append(samples, hs.coarseSamples...)
+	samples = append(samples, hs.mediumSamples...)
+	samples = append(samples, hs.loadSamples...)
+	return samples
+}
+
+// GetHistoryStats returns the current size of the history against its limits
+func (hs *HistoryService) GetHistoryStats() models.HistoryStats {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	stats := models.HistoryStats{
+		RawSamples:    len(hs.loadSamples),
+		MediumSamples: len(hs.mediumSamples),
+		CoarseSamples: len(hs.coarseSamples),
+		MemoryBytes:   uint64(hs.sampleCount()) * loadSampleSize,
+		BudgetBytes:   hs.budget,
+		Retention:     hs.retention,
+	}
+	for _, tier := range [][]models.LoadSample{hs.coarseSamples, hs.mediumSamples, hs.loadSamples} {
+		if len(tier) > 0 {
+			stats.OldestSample = tier[0].Timestamp
+			break
+		}
+	}
+	return stats
+}
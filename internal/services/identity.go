@@ -0,0 +1,67 @@
+package services
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+// identityCache caches UID/GID to name lookups, which can hit NSS or LDAP
+type identityCache struct {
+	mu     sync.Mutex
+	users  map[int32]string
+	groups map[int32]string
+}
+
+// newIdentityCache creates an empty identity cache
+func newIdentityCache() *identityCache {
+	return &identityCache{
+		users:  make(map[int32]string),
+		groups: make(map[int32]string),
+	}
+}
+
+// userName resolves a UID to a user name, or "" if it cannot be resolved
+func (c *identityCache) userName(uid int32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+
+	// Failed lookups are cached too so unknown IDs aren't retried every refresh
+	name := ""
+	if u, err := user.LookupId(strconv.Itoa(int(uid))); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+	return name
+}
+
+// groupName resolves a GID to a group name, or "" if it cannot be resolved
+func (c *identityCache) groupName(gid int32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name, ok := c.groups[gid]; ok {
+		return name
+	}
+
+	name := ""
+	if g, err := user.LookupGroupId(strconv.Itoa(int(gid))); err == nil {
+		name = g.Name
+	}
+	c.groups[gid] = name
+	return name
+}
+
+// ResolveUser returns the user name for a UID, using a cache
+func (ps *ProcessService) ResolveUser(uid int32) string {
+	return ps.identities.userName(uid)
+}
+
+// ResolveGroup returns the group name for a GID, using a cache
+func (ps *ProcessService) ResolveGroup(gid int32) string {
+	return ps.identities.groupName(gid)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// IdleSampleInterval is how often processes are checked for activity
+const IdleSampleInterval = 30 * time.Second
+
+// idleCPUTolerance is how much CPU time a process may use, in seconds, and
+// still count as idle
+const idleCPUTolerance = 0.1
+
+// idleState is the activity of a process as of its last sample
+type idleState struct {
+	createTime int64 // ms since epoch, to detect PID reuse
+	name       string
+	username   string
+	command    string
+	cpuSeconds float64 // CPU time at activeAt
+	ioBytes    uint64  // bytes read and written at activeAt
+	cpuNow     float64
+	activeAt   time.Time
+}
+
+// IdleService detects processes that have used almost no CPU and done no
+// I/O for longer than a threshold
+type IdleService struct {
+	mu             sync.Mutex
+	processService *ProcessService
+	threshold      time.Duration
+	states         map[int32]*idleState
+}
+
+// NewIdleService creates a new idle service. A zero threshold disables detection.
+func NewIdleService(processService *ProcessService, threshold time.Duration) *IdleService {
+	return &IdleService{
+		processService: processService,
+		threshold:      threshold,
+		states:         make(map[int32]*idleState),
+	}
+}
+
+// Enabled reports whether idle detection is turned on
+func (is *IdleService) Enabled() bool {
+	return is.threshold > 0
+}
+
+// Threshold returns how long a process must be inactive to count as idle
+func (is *IdleService) Threshold() time.Duration {
+	return is.threshold
+}
+
+// Sample records the CPU time and I/O of every process, marking those that
+// used more than idleCPUTolerance or did any I/O since they were last active
+func (is *IdleService) Sample(now time.Time) error {
+	if !is.Enabled() {
+		return nil
+	}
+
+	procs, err := process.Processes()
+	if err != nil {
+		return fmt.Errorf("failed to get processes: %w", err)
+	}
+
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	self := int32(os.Getpid())
+	seen := make(map[int32]*idleState, len(procs))
+	for _, p := range procs {
+		if p.Pid == self || p.Pid == 1 {
+			continue
+		}
+		times, err := p.Times()
+		if err != nil {
+			continue
+		}
+		cpu := times.User + times.System
+		createTime, _ := p.CreateTime()
+
+		// I/O counters need extra privileges for other users' processes;
+		// without them only CPU time is considered
+		var ioBytes uint64
+		if io, err := p.IOCounters(); err == nil {
+			ioBytes = io.ReadBytes + io.WriteBytes
+		}
+
+		state, ok := is.states[p.Pid]
+		if !ok || state.createTime != createTime {
+			state, ok = newIdleState(p, createTime, now)
+			if !ok {
+				continue
+			}
+			state.cpuSeconds = cpu
+			state.ioBytes = ioBytes
+		}
+		if cpu-state.cpuSeconds > idleCPUTolerance || ioBytes != state.ioBytes {
+			state.cpuSeconds = cpu
+			state.ioBytes = ioBytes
+			state.activeAt = now
+		}
+		state.cpuNow = cpu
+		seen[p.Pid] = state
+	}
+	is.states = seen
+
+	return nil
+}
+
+// newIdleState starts tracking p, skipping kernel threads which have no
+// command line and cannot be killed
+func newIdleState(p *process.Process, createTime int64, now time.Time) (*idleState, bool) {
+	cmdline, err := p.Cmdline()
+	if err != nil || cmdline == "" {
+		return nil, false
+	}
+	name, _ := p.Name()
+	username, _ := p.Username()
+
+	return &idleState{
+		createTime: createTime,
+		name:       name,
+		username:   username,
+		command:    cmdline,
+		activeAt:   now,
+	}, true
+}
+
+// IdleProcesses returns the processes inactive for longer than the
+// threshold at now, longest idle first
+func (is *IdleService) IdleProcesses(now time.Time) []models.IdleProcess {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	var idle []models.IdleProcess
+	for pid, state := range is.states {
+		if now.Sub(state.activeAt) < is.threshold {
+			continue
+		}
+		idle = append(idle, models.IdleProcess{
+			PID:        pid,
+			Name:       state.name,
+			Username:   state.username,
+			Command:    state.command,
+			CreateTime: time.UnixMilli(state.createTime),
+			IdleSince:  state.activeAt,
+			CPUSeconds: state.cpuNow,
+		})
+	}
+	slices.SortFunc(idle, func(a, b models.IdleProcess) int {
+		if c := a.IdleSince.Compare(b.IdleSince); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return idle
+}
+
+// KillIdle kills the given processes, skipping any that became active or
+// exited since they were listed. It returns the PIDs killed.
+func (is *IdleService) KillIdle(procs []models.IdleProcess) ([]int32, error) {
+	var killed []int32
+	var errs []error
+	for _, proc := range procs {
+		if !is.stillIdle(proc) || !is.processService.IsSameProcess(proc.PID, proc.CreateTime) {
+			continue
+		}
+		if err := is.processService.KillProcess(proc.PID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		killed = append(killed, proc.PID)
+	}
+	return killed, errors.Join(errs...)
+}
+
+// stillIdle reports whether proc has not been active since it was listed
+func (is *IdleService) stillIdle(proc models.IdleProcess) bool {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	state, ok := is.states[proc.PID]
+	return ok && state.createTime == proc.CreateTime.UnixMilli() && state.activeAt.Equal(proc.IdleSince)
+}
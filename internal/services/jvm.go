@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// JVMStats reads the heap and garbage collection figures of the JVM running
+// as pid with jstat, which has to be installed with a JDK
+func (ps *ProcessService) JVMStats(pid int32) (*models.JVMStats, error) {
+	output, err := runTool(pid, "jstat", "-gc", strconv.Itoa(int(pid)))
+	if err != nil {
+		return nil, err
+	}
+	return parseJStatGC(output)
+}
+
+// parseJStatGC parses the output of jstat -gc: a header row naming the
+// columns and a row of values. Capacities and usage are in KB, times in
+// seconds. Columns differ between JDK versions, so they are found by name,
+// and a column missing or shown as "-" counts as zero.
+func parseJStatGC(output string) (*models.JVMStats, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("unexpected jstat output: %q", strings.TrimSpace(output))
+	}
+	names := strings.Fields(lines[0])
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(names) != len(fields) {
+		return nil, fmt.Errorf("unexpected jstat output: %d columns but %d values", len(names), len(fields))
+	}
+
+	values := make(map[string]float64, len(names))
+	for i, name := range names {
+		if value, err := strconv.ParseFloat(fields[i], 64); err == nil {
+			values[name] = value
+		}
+	}
+	kb := func(names ...string) uint64 {
+		var total float64
+		for _, name := range names {
+			total += values[name]
+		}
+		return uint64(total * 1024)
+	}
+
+	return &models.JVMStats{
+		HeapUsed:          kb("S0U", "S1U", "EU", "OU"),
+		HeapCapacity:      kb("S0C", "S1C", "EC", "OC"),
+		OldUsed:           kb("OU"),
+		OldCapacity:       kb("OC"),
+		MetaspaceUsed:     kb("MU"),
+		MetaspaceCapacity: kb("MC"),
+		YoungGCs:          int64(values["YGC"]),
+		YoungGCTime:       values["YGCT"],
+		FullGCs:           int64(values["FGC"]),
+		FullGCTime:        values["FGCT"],
+		GCTime:            values["GCT"],
+	}, nil
+}
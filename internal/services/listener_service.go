@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ephemeralPortStart is the lowest port handed out for outgoing connections.
+// Unconnected UDP sockets above it are usually clients such as resolvers
+// rather than services, so they are not watched.
+const ephemeralPortStart = 32768
+
+// listenerKey identifies a listening service. The program rather than the
+// PID is part of it, so a service restarting does not count as a change.
+type listenerKey struct {
+	protocol string
+	address  string
+	port     uint32
+	process  string
+}
+
+// ListenerService detects services starting or stopping listening on a
+// port by comparing the listening sockets between samples
+type ListenerService struct {
+	mu             sync.Mutex
+	processService *ProcessService
+	interval       time.Duration
+	listeners      map[listenerKey]models.ListeningPort // nil before the first sample
+}
+
+// NewListenerService creates a new listener service. A zero interval disables detection.
+func NewListenerService(processService *ProcessService, interval time.Duration) *ListenerService {
+	return &ListenerService{
+		processService: processService,
+		interval:       interval,
+	}
+}
+
+// Enabled reports whether listener detection is turned on
+func (ls *ListenerService) Enabled() bool {
+	return ls.interval > 0
+}
+
+// Interval returns how often the listening sockets should be sampled
+func (ls *ListenerService) Interval() time.Duration {
+	return ls.interval
+}
+
+// Sample compares the listening sockets with the previous sample and records
+// an event for every service that started or stopped listening since. The
+// first sample only sets the baseline. It returns the changes found.
+func (ls *ListenerService) Sample(now time.Time) ([]models.ListenerChange, error) {
+	if !ls.Enabled() {
+		return nil, nil
+	}
+
+	ports, err := ls.processService.GetListeningPorts(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[listenerKey]models.ListeningPort, len(ports))
+	names := make(map[int32]string)
+	for _, port := range ports {
+		udp := port.Protocol == "udp" || port.Protocol == "udp6"
+		if udp && port.Port >= ephemeralPortStart {
+			continue
+		}
+		port.Process = processName(port.PID, names)
+		current[listenerKey{port.Protocol, port.Address, port.Port, port.Process}] = port
+	}
+
+	ls.mu.Lock()
+	previous := ls.listeners
+	ls.listeners = current
+	ls.mu.Unlock()
+
+	if previous == nil {
+		return nil, nil
+	}
+
+	var changes []models.ListenerChange
+	for key, port := range current {
+		if _, ok := previous[key]; !ok {
+			changes = append(changes, models.ListenerChange{Time: now, Started: true, Listener: port})
+		}
+	}
+	for key, port := range previous {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, models.ListenerChange{Time: now, Listener: port})
+		}
+	}
+	slices.SortFunc(changes, func(a, b models.ListenerChange) int {
+		return int(a.Listener.Port) - int(b.Listener.Port)
+	})
+	for _, change := range changes {
+		ls.recordChange(change)
+	}
+	return changes, nil
+}
+
+// processName returns the name of pid, looking it up at most once per sample
+func processName(pid int32, names map[int32]string) string {
+	if pid == 0 {
+		return ""
+	}
+	name, ok := names[pid]
+	if !ok {
+		if p, err := process.NewProcess(pid); err == nil {
+			name, _ = p.Name()
+		}
+		names[pid] = name
+	}
+	return name
+}
+
+// recordChange records a service starting or stopping listening in the event log
+func (ls *ListenerService) recordChange(change models.ListenerChange) {
+	port := change.Listener
+	owner := "an unknown process"
+	if port.PID != 0 {
+		owner = fmt.Sprintf("%s (PID %d)", port.Process, port.PID)
+	}
+
+	event := models.Event{
+		Time:    change.Time,
+		Kind:    models.EventListenerStopped,
+		Level:   models.EventNotice,
+		Message: fmt.Sprintf("Stopped listening: %s on %s", owner, ListenerAddress(port)),
+		Fields: map[string]string{
+			"protocol": port.Protocol,
+			"address":  port.Address,
+			"port":     strconv.FormatUint(uint64(port.Port), 10),
+			"pid":      strconv.Itoa(int(port.PID)),
+			"name":     port.Process,
+		},
+	}
+	// A new listener is what an intruder's backdoor looks like
+	if change.Started {
+		event.Kind = models.EventListenerStarted
+		event.Level = models.EventWarning
+		event.Message = fmt.Sprintf("Started listening: %s on %s", owner, ListenerAddress(port))
+	}
+	ls.processService.RecordEvent(event)
+}
+
+// ListenerAddress formats the protocol, address and port of a listening socket
+func ListenerAddress(port models.ListeningPort) string {
+	address := port.Address
+	if port.Protocol == "tcp6" || port.Protocol == "udp6" {
+		address = "[" + address + "]"
+	}
+	return fmt.Sprintf("%s %s:%d", port.Protocol, address, port.Port)
+}
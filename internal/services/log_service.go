@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxTailBytes bounds how much of a log file is read to find its last lines
+const maxTailBytes = 512 * 1024
+
+// LogService finds and tails the log files associated with process names
+type LogService struct {
+	files map[string][]string // paths or globs by process name
+}
+
+// NewLogService creates a new log service from log file patterns keyed by process name
+func NewLogService(files map[string][]string) *LogService {
+	return &LogService{files: files}
+}
+
+// HasLogs reports whether log files are associated with the process name
+func (ls *LogService) HasLogs(name string) bool {
+	return len(ls.files[name]) > 0
+}
+
+// LogFiles expands the paths and globs associated with the process name into
+// existing files, most recently modified first
+func (ls *LogService) LogFiles(name string) ([]string, error) {
+	seen := make(map[string]bool)
+	modTimes := make(map[string]int64)
+	var files []string
+
+	for _, pattern := range ls.files[name] {
+		matches, err := filepath.Glob(expandHome(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log file pattern %q: %w", pattern, err)
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || seen[path] {
+				continue
+			}
+			seen[path] = true
+			modTimes[path] = info.ModTime().UnixNano()
+			files = append(files, path)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return modTimes[files[i]] > modTimes[files[j]]
+	})
+	return files, nil
+}
+
+// TailFile returns up to n of the last lines of the file at path
+func (ls *LogService) TailFile(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	// Only the end of large files is read
+	start := max(info.Size()-maxTailBytes, 0)
+	data, err := io.ReadAll(io.NewSectionReader(file, start, info.Size()-start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	// A partial first line is dropped when reading from the middle of the file
+	if start > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	return lines, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/mqtt"
+
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// defaultMQTTInterval is used when the config leaves the summary interval unset
+const defaultMQTTInterval = 60 * time.Second
+
+// mqttEventQueue is how many events may wait to be published before new
+// ones are dropped
+const mqttEventQueue = 64
+
+// MQTTPublisher publishes periodic summary metrics and events to an MQTT
+// broker, connecting lazily and reconnecting after failures
+type MQTTPublisher struct {
+	config         models.MQTTConfig
+	interval       time.Duration
+	processService *ProcessService
+	hostname       string
+
+	events chan models.Event
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	// client is only used by the publishing goroutine
+	client *mqtt.Client
+
+	mu     sync.Mutex
+	status models.MQTTStatus
+}
+
+// NewMQTTPublisher creates a publisher for config, replacing {host} in the
+// client ID and topics with the hostname. It returns nil when no broker is
+// configured.
+func NewMQTTPublisher(processService *ProcessService, config models.MQTTConfig) *MQTTPublisher {
+	if config.Broker == "" {
+		return nil
+	}
+
+	hostname, _ := os.Hostname()
+	expand := func(s string) string { return strings.ReplaceAll(s, "{host}", hostname) }
+	config.ClientID = expand(config.ClientID)
+	config.SummaryTopic = expand(config.SummaryTopic)
+	config.EventTopic = expand(config.EventTopic)
+
+	interval := defaultMQTTInterval
+	if config.Interval > 0 {
+		interval = time.Duration(config.Interval) * time.Second
+	}
+
+	return &MQTTPublisher{
+		config:         config,
+		interval:       interval,
+		processService: processService,
+		hostname:       hostname,
+		events:         make(chan models.Event, mqttEventQueue),
+		stop:           make(chan struct{}),
+		status:         models.MQTTStatus{Broker: config.Broker},
+	}
+}
+
+// Start publishes a summary right away and then every interval, and
+// publishes events as they are recorded
+func (mp *MQTTPublisher) Start() {
+	mp.wg.Add(1)
+	go mp.run()
+}
+
+// Stop publishes the events still queued, then disconnects from the broker
+func (mp *MQTTPublisher) Stop() {
+	close(mp.stop)
+	mp.wg.Wait()
+}
+
+// Record queues event to be published, dropping it when the queue is full
+// so a slow broker never holds up the action that raised it
+func (mp *MQTTPublisher) Record(event models.Event) error {
+	select {
+	case mp.events <- event:
+		return nil
+	default:
+		mp.mu.Lock()
+		mp.status.Dropped++
+		mp.mu.Unlock()
+		return fmt.Errorf("MQTT event queue is full")
+	}
+}
+
+// Status returns the connection state and publish counts
+func (mp *MQTTPublisher) Status() models.MQTTStatus {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	return mp.status
+}
+
+// run publishes until Stop is called
+func (mp *MQTTPublisher) run() {
+	defer mp.wg.Done()
+	defer mp.disconnect()
+
+	ticker := time.NewTicker(mp.interval)
+	defer ticker.Stop()
+
+	mp.publishSummary()
+	for {
+		select {
+		case <-mp.stop:
+			for {
+				select {
+				case event := <-mp.events:
+					mp.publishEvent(event)
+				default:
+					return
+				}
+			}
+		case event := <-mp.events:
+			mp.publishEvent(event)
+		case <-ticker.C:
+			mp.publishSummary()
+		}
+	}
+}
+
+// publishSummary publishes the current summary metrics as a retained message,
+// so new subscribers see the latest state at once
+func (mp *MQTTPublisher) publishSummary() {
+	summary, err := mp.summary()
+	if err != nil {
+		mp.recordResult(err)
+		return
+	}
+	mp.publishJSON(mp.config.SummaryTopic, summary, true)
+}
+
+// publishEvent publishes event with the hostname added to its fields
+func (mp *MQTTPublisher) publishEvent(event models.Event) {
+	fields := make(map[string]string, len(event.Fields)+1)
+	for key, value := range event.Fields {
+		fields[key] = value
+	}
+	fields["hostname"] = mp.hostname
+	event.Fields = fields
+
+	mp.publishJSON(mp.config.EventTopic, event, false)
+}
+
+// publishJSON publishes v encoded as JSON, reconnecting once if the
+// connection was lost
+func (mp *MQTTPublisher) publishJSON(topic string, v any, retain bool) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		mp.recordResult(fmt.Errorf("failed to encode message: %w", err))
+		return
+	}
+
+	if mp.client != nil {
+		if err := mp.client.Publish(topic, payload, retain); err == nil {
+			mp.recordResult(nil)
+			return
+		}
+		mp.disconnect()
+	}
+
+	client, err := mqtt.Dial(mp.config.Broker, mqtt.Options{
+		ClientID: mp.config.ClientID,
+		Username: mp.config.Username,
+		Password: mp.config.Password,
+		// Summaries keep the connection alive between events
+		KeepAlive: 2 * mp.interval,
+	})
+	if err != nil {
+		mp.recordResult(fmt.Errorf("failed to connect to %s: %w", mp.config.Broker, err))
+		return
+	}
+	mp.client = client
+	mp.recordResult(client.Publish(topic, payload, retain))
+}
+
+// disconnect closes the connection to the broker, if any
+func (mp *MQTTPublisher) disconnect() {
+	if mp.client != nil {
+		mp.client.Close()
+		mp.client = nil
+	}
+}
+
+// recordResult updates the status after a publish attempt
+func (mp *MQTTPublisher) recordResult(err error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.status.Connected = mp.client != nil
+	if err != nil {
+		mp.status.Failed++
+		mp.status.LastError = err.Error()
+		mp.status.LastErrorAt = time.Now()
+		return
+	}
+	mp.status.Published++
+	mp.status.LastPublishAt = time.Now()
+}
+
+// summary collects the metrics published on the summary topic
+func (mp *MQTTPublisher) summary() (*models.MetricsSummary, error) {
+	processes, err := mp.processService.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return summarize(processes, mp.hostname), nil
+}
+
+// summarize totals the usage of processes and adds the load averages
+func summarize(processes []*models.ProcessInfo, hostname string) *models.MetricsSummary {
+	summary := &models.MetricsSummary{
+		Time:      time.Now(),
+		Hostname:  hostname,
+		Processes: len(processes),
+	}
+	for _, proc := range processes {
+		if proc.Status == process.Running {
+			summary.Running++
+		}
+		summary.CPU += proc.CPU
+		summary.Memory += proc.Memory
+	}
+
+	// Load averages are not available on every platform
+	if avg, err := load.Avg(); err == nil {
+		summary.Load1 = avg.Load1
+		summary.Load5 = avg.Load5
+		summary.Load15 = avg.Load15
+	}
+	return summary
+}
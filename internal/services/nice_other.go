@@ -0,0 +1,13 @@
+//go:build !unix
+
+package services
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// setPriority is not available without the Unix setpriority call
+func setPriority(pid int32, value int) error {
+	return fmt.Errorf("changing the nice value is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"syscall"
+)
+
+// setPriority sets the nice value of every thread of pid. Linux applies
+// setpriority to a single thread, so each one in /proc is changed in turn.
+func setPriority(pid int32, value int) error {
+	if runtime.GOOS != "linux" {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), value)
+	}
+
+	tasks, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+	if err != nil {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), value)
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		// Threads may exit while we go
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, value); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+	}
+	return nil
+}
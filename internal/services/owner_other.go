@@ -0,0 +1,10 @@
+//go:build !unix
+
+package services
+
+import "os"
+
+// fileOwner is not available without Unix file ownership
+func fileOwner(info os.FileInfo) (uid, gid uint32, ok bool) {
+	return 0, 0, false
+}
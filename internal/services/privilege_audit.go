@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// capabilityNames are the Linux capabilities by bit number
+var capabilityNames = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// dangerousCapabilities let a process take over the host or other users'
+// files and processes
+var dangerousCapabilities = map[string]bool{
+	"CAP_DAC_OVERRIDE":    true,
+	"CAP_DAC_READ_SEARCH": true,
+	"CAP_FOWNER":          true,
+	"CAP_SETUID":          true,
+	"CAP_SETGID":          true,
+	"CAP_SETPCAP":         true,
+	"CAP_SETFCAP":         true,
+	"CAP_NET_ADMIN":       true,
+	"CAP_SYS_MODULE":      true,
+	"CAP_SYS_RAWIO":       true,
+	"CAP_SYS_PTRACE":      true,
+	"CAP_SYS_ADMIN":       true,
+	"CAP_BPF":             true,
+}
+
+// Risk points of each finding in a privilege audit
+const (
+	riskRoot               = 1
+	riskAllCapabilities    = 1
+	riskCapability         = 2 // per dangerous capability held without root
+	riskWritableExecutable = 5
+)
+
+// AuditPrivileges reports the processes whose privileges stand out: those
+// running as root, holding dangerous capabilities without root, or running
+// with privileges from an executable someone else could replace. Processes
+// are ordered from the riskiest. It is only supported on Linux.
+func AuditPrivileges(processes []*models.ProcessInfo) ([]models.PrivilegeAudit, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("privilege audits are not supported on %s", runtime.GOOS)
+	}
+
+	var audits []models.PrivilegeAudit
+	for _, proc := range processes {
+		// Kernel threads have no executable and always run with every privilege
+		if proc.Executable == "" {
+			continue
+		}
+		capabilities, ok := readEffectiveCapabilities(proc.PID)
+		if !ok {
+			continue
+		}
+		euid, ok := proc.EffectiveUID()
+		if !ok {
+			continue
+		}
+
+		audit := models.PrivilegeAudit{
+			PID:             proc.PID,
+			Name:            proc.Name,
+			Username:        proc.Username,
+			Executable:      proc.Executable,
+			Root:            euid == 0,
+			AllCapabilities: len(capabilities) == len(capabilityNames),
+		}
+		if !audit.AllCapabilities {
+			audit.Capabilities = capabilities
+		}
+
+		if audit.Root {
+			audit.Risk += riskRoot
+			audit.Reasons = append(audit.Reasons, "runs as root")
+		}
+		if audit.AllCapabilities {
+			audit.Risk += riskAllCapabilities
+			audit.Reasons = append(audit.Reasons, "has all capabilities")
+		} else if !audit.Root {
+			var dangerous []string
+			for _, capability := range capabilities {
+				if dangerousCapabilities[capability] {
+					dangerous = append(dangerous, capability)
+				}
+			}
+			if len(dangerous) > 0 {
+				audit.Risk += riskCapability * len(dangerous)
+				audit.Reasons = append(audit.Reasons, "holds "+strings.Join(dangerous, ", ")+" without root")
+			}
+		}
+		// Replacing the executable of a privileged process gains its privileges
+		if audit.Risk > 0 {
+			if writableBy := executableWritableBy(proc.Executable); writableBy != "" {
+				audit.WritableBy = writableBy
+				audit.Risk += riskWritableExecutable
+				audit.Reasons = append(audit.Reasons, "executable writable by "+writableBy)
+			}
+		}
+
+		if audit.Risk > 0 {
+			audits = append(audits, audit)
+		}
+	}
+
+	slices.SortFunc(audits, func(a, b models.PrivilegeAudit) int {
+		return cmp.Or(
+			cmp.Compare(b.Risk, a.Risk),
+			strings.Compare(a.Name, b.Name),
+			cmp.Compare(a.PID, b.PID),
+		)
+	})
+	return audits, nil
+}
+
+// readEffectiveCapabilities returns the names of the capabilities in the
+// CapEff line of /proc/<pid>/status
+func readEffectiveCapabilities(pid int32) ([]string, bool) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "CapEff:")
+		if !ok {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return nil, false
+		}
+		return decodeCapabilities(mask), true
+	}
+	return nil, false
+}
+
+// decodeCapabilities names the capabilities set in mask, ignoring bits
+// newer than capabilityNames
+func decodeCapabilities(mask uint64) []string {
+	var capabilities []string
+	for bit, name := range capabilityNames {
+		if mask&(1<<bit) != 0 {
+			capabilities = append(capabilities, name)
+		}
+	}
+	return capabilities
+}
+
+// executableWritableBy describes who other than root could modify or
+// replace the executable at path, or returns "" if nobody can. The file
+// and the directory holding it are checked.
+func executableWritableBy(path string) string {
+	path = strings.TrimSuffix(path, " (deleted)")
+	if path == "" {
+		return ""
+	}
+	for _, target := range []string{path, filepath.Dir(path)} {
+		info, err := os.Stat(target)
+		if err != nil {
+			continue
+		}
+		mode := info.Mode()
+		// Only an entry's owner may rename it out of a sticky directory
+		sticky := info.IsDir() && mode&os.ModeSticky != 0
+
+		uid, gid, ok := fileOwner(info)
+		switch {
+		case !ok:
+			continue
+		case uid != 0:
+			return fmt.Sprintf("UID %d (owner of %s)", uid, target)
+		case mode&0002 != 0 && !sticky:
+			return fmt.Sprintf("everyone (%s is world-writable)", target)
+		case mode&0020 != 0 && gid != 0 && !sticky:
+			return fmt.Sprintf("GID %d (%s is group-writable)", gid, target)
+		}
+	}
+	return ""
+}
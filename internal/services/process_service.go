@@ -1,55 +1,168 @@
 package services
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"tappmanager/internal/models"
 	"tappmanager/internal/storage"
 
+	psnet "github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
+// ProcessSource supplies processes collected elsewhere, such as by a daemon
+type ProcessSource interface {
+	Processes() ([]*models.ProcessInfo, error)
+}
+
 // ProcessService handles process-related operations
 type ProcessService struct {
-	storage storage.Storage
+	storage    storage.Storage
+	identities *identityCache
+
+	// blockedSince records when each PID was first seen in D state
+	blockedMu    sync.Mutex
+	blockedSince map[int32]time.Time
+
+	// collector holds timing and error counts of the most recent scans
+	collectorMu sync.Mutex
+	collector   models.CollectorStats
+	lastScan    []*models.ProcessInfo
+	lastScanID  uint64
+
+	// remote, when set, is asked for processes before collecting them locally
+	remote   ProcessSource
+	attached bool
+
+	// events record kills and alerts outside tappmanager
+	events []EventSink
 }
 
 // NewProcessService creates a new process service
 func NewProcessService(storage storage.Storage) *ProcessService {
 	return &ProcessService{
-		storage: storage,
+		storage:      storage,
+		identities:   newIdentityCache(),
+		blockedSince: make(map[int32]time.Time),
+	}
+}
+
+// SetRemote makes GetProcesses use processes from source, falling back to
+// collecting them locally whenever source fails. Attached reports attached
+// until GetProcesses first asks source. It must be called before the
+// service is used.
+func (ps *ProcessService) SetRemote(source ProcessSource, attached bool) {
+	ps.remote = source
+	ps.attached = attached
+}
+
+// AddEventSink records kills, and alerts passed to RecordEvent, in sink.
+// It must be called before the service is used.
+func (ps *ProcessService) AddEventSink(sink EventSink) {
+	ps.events = append(ps.events, sink)
+}
+
+// RecordEvent passes event to every event sink. Recording is best effort; a
+// sink that cannot be reached never fails the action.
+func (ps *ProcessService) RecordEvent(event models.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	for _, sink := range ps.events {
+		sink.Record(event)
 	}
 }
 
+// Attached reports whether the last processes came from the remote source
+func (ps *ProcessService) Attached() bool {
+	ps.collectorMu.Lock()
+	defer ps.collectorMu.Unlock()
+	return ps.attached
+}
+
+// LastProcesses returns the processes of the most recent local scan, or nil
+// before the first one
+func (ps *ProcessService) LastProcesses() []*models.ProcessInfo {
+	ps.collectorMu.Lock()
+	defer ps.collectorMu.Unlock()
+	return slices.Clone(ps.lastScan)
+}
+
+// LastScanID identifies the most recent local scan. It changes whenever
+// LastProcesses would return the processes of another scan.
+func (ps *ProcessService) LastScanID() uint64 {
+	ps.collectorMu.Lock()
+	defer ps.collectorMu.Unlock()
+	return ps.lastScanID
+}
+
 // GetProcesses retrieves all processes with detailed information
 func (ps *ProcessService) GetProcesses() ([]*models.ProcessInfo, error) {
+	if ps.remote != nil {
+		processes, err := ps.remote.Processes()
+		ps.collectorMu.Lock()
+		ps.attached = err == nil
+		ps.collectorMu.Unlock()
+		if err == nil {
+			ps.trackBlocked(processes)
+			return processes, nil
+		}
+	}
+
+	start := time.Now()
 	procs, err := process.Processes()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get processes: %w", err)
 	}
 
 	var processInfos []*models.ProcessInfo
+	fieldErrors := make(map[string]int)
+	limits := make(map[string]cgroupLimits)
+	terminals := terminalNames()
+	skipped := 0
 	for _, p := range procs {
-		info, err := ps.getProcessInfo(p)
+		info, err := ps.getProcessInfo(p, fieldErrors, limits, terminals)
 		if err != nil {
+			skipped++
 			continue // Skip processes we can't read
 		}
 		processInfos = append(processInfos, info)
 	}
 
+	markVirtualized(processInfos)
+	ps.trackBlocked(processInfos)
+	ps.recordScan(time.Since(start), len(processInfos), skipped, fieldErrors)
+
 	// Sort by CPU usage to get more accurate data
-	sort.Slice(processInfos, func(i, j int) bool {
-		return processInfos[i].CPU > processInfos[j].CPU
-	})
+	slices.SortFunc(processInfos, processComparator("cpu", "desc"))
+
+	// Callers may sort the returned slice in place, so keep a copy
+	ps.collectorMu.Lock()
+	ps.lastScan = slices.Clone(processInfos)
+	ps.lastScanID++
+	ps.collectorMu.Unlock()
 
 	return processInfos, nil
 }
 
-// getProcessInfo extracts detailed information from a process
-func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInfo, error) {
+// getProcessInfo extracts detailed information from a process.
+// Fields that cannot be read are counted in fieldErrors by name, the
+// limits of each cgroup are looked up once per scan in limits, and terminals
+// names the terminal devices found at the start of the scan.
+func (ps *ProcessService) getProcessInfo(p *process.Process, fieldErrors map[string]int, limits map[string]cgroupLimits, terminals map[uint64]string) (*models.ProcessInfo, error) {
 	info := &models.ProcessInfo{
 		PID: p.Pid,
 	}
@@ -57,6 +170,8 @@ func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInf
 	// Get basic information
 	if name, err := p.Name(); err == nil {
 		info.Name = name
+	} else {
+		fieldErrors["Name"]++
 	}
 
 	if ppid, err := p.Ppid(); err == nil {
@@ -65,6 +180,8 @@ func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInf
 
 	if status, err := p.Status(); err == nil && len(status) > 0 {
 		info.Status = status[0]
+	} else {
+		fieldErrors["Status"]++
 	}
 
 	// Get CPU percentage - use a more reliable method
@@ -89,6 +206,19 @@ func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInf
 
 	if memInfo, err := p.MemoryInfo(); err == nil {
 		info.MemoryBytes = memInfo.RSS
+	} else {
+		fieldErrors["MemoryInfo"]++
+	}
+
+	// Other users' I/O counters need extra privileges, so they are not
+	// counted as field errors
+	if io, err := p.IOCounters(); err == nil {
+		info.IO = &models.ProcessIO{
+			ReadBytes:  io.ReadBytes,
+			WriteBytes: io.WriteBytes,
+			ReadCount:  io.ReadCount,
+			WriteCount: io.WriteCount,
+		}
 	}
 
 	if createTime, err := p.CreateTime(); err == nil {
@@ -97,20 +227,42 @@ func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInf
 
 	if username, err := p.Username(); err == nil {
 		info.Username = username
+	} else {
+		fieldErrors["Username"]++
 	}
 
-	if cmdline, err := p.Cmdline(); err == nil {
-		info.Command = cmdline
+	if uids, err := p.Uids(); err == nil {
+		info.UIDs = uids
+	}
+
+	if gids, err := p.Gids(); err == nil {
+		info.GIDs = gids
+	}
+
+	if args, err := p.CmdlineSlice(); err == nil {
+		info.Args = args
+		info.Command = strings.Join(args, " ")
+	} else {
+		fieldErrors["Cmdline"]++
 	}
 
 	if cwd, err := p.Cwd(); err == nil {
 		info.WorkingDir = cwd
+	} else {
+		fieldErrors["Cwd"]++
+	}
+
+	if exe, err := p.Exe(); err == nil {
+		info.Executable = exe
+	} else {
+		fieldErrors["Exe"]++
 	}
 
 	if numThreads, err := p.NumThreads(); err == nil {
 		info.NumThreads = numThreads
 	} else {
 		info.NumThreads = 0
+		fieldErrors["NumThreads"]++
 	}
 
 	if nice, err := p.Nice(); err == nil {
@@ -119,34 +271,209 @@ func (ps *ProcessService) getProcessInfo(p *process.Process) (*models.ProcessInf
 		info.Nice = 0
 	}
 
+	cgroup := readCgroup(p.Pid)
+	info.ContainerID, info.ContainerRuntime = parseContainerCgroup(cgroup)
+	limit := cgroupLimitsOf(cgroup, limits)
+	info.CgroupCPULimit, info.CgroupMemoryLimit = limit.cpu, limit.memory
+	if info.ContainerID != "" {
+		info.ContainerPrivileged, info.HostNamespaces = inspectContainerProcess(p.Pid)
+	}
+	info.SecurityContext = readSecurityContext(p.Pid)
+
+	var tty uint64
+	info.ProcessGroupID, info.SessionID, tty = readSessionInfo(p.Pid)
+	if tty != 0 {
+		info.Terminal = terminals[tty]
+	}
+
 	// Check if process is running
 	info.IsRunning = true
 
 	return info, nil
 }
 
-// FilterProcesses filters processes based on criteria
+// recordScan stores the timing and error counts of a completed scan
+func (ps *ProcessService) recordScan(duration time.Duration, count, skipped int, fieldErrors map[string]int) {
+	ps.collectorMu.Lock()
+	defer ps.collectorMu.Unlock()
+
+	ps.collector.Scans++
+	ps.collector.LastScanAt = time.Now()
+	ps.collector.LastScanDuration = duration
+	if duration > ps.collector.MaxScanDuration {
+		ps.collector.MaxScanDuration = duration
+	}
+	ps.collector.ProcessCount = count
+	ps.collector.SkippedCount = skipped
+	ps.collector.FieldErrors = fieldErrors
+}
+
+// GetCollectorStats returns a copy of the collector timing and error counts
+func (ps *ProcessService) GetCollectorStats() models.CollectorStats {
+	ps.collectorMu.Lock()
+	defer ps.collectorMu.Unlock()
+
+	stats := ps.collector
+	stats.FieldErrors = make(map[string]int, len(ps.collector.FieldErrors))
+	for field, count := range ps.collector.FieldErrors {
+		stats.FieldErrors[field] = count
+	}
+	return stats
+}
+
+// trackBlocked updates how long each process has been in uninterruptible sleep
+func (ps *ProcessService) trackBlocked(processes []*models.ProcessInfo) {
+	ps.blockedMu.Lock()
+	defer ps.blockedMu.Unlock()
+
+	now := time.Now()
+	blocked := make(map[int32]time.Time)
+	for _, proc := range processes {
+		if proc.Status != process.Blocked {
+			continue
+		}
+		if since, ok := ps.blockedSince[proc.PID]; ok {
+			blocked[proc.PID] = since
+		} else {
+			blocked[proc.PID] = now
+		}
+	}
+	ps.blockedSince = blocked
+}
+
+// GetBlockedProcesses returns processes that have been in D state for at least threshold
+func (ps *ProcessService) GetBlockedProcesses(processes []*models.ProcessInfo, threshold time.Duration) []*models.BlockedProcess {
+	ps.blockedMu.Lock()
+	defer ps.blockedMu.Unlock()
+
+	var blocked []*models.BlockedProcess
+	for _, proc := range processes {
+		since, ok := ps.blockedSince[proc.PID]
+		if !ok || time.Since(since) < threshold {
+			continue
+		}
+		blocked = append(blocked, &models.BlockedProcess{
+			Process:      proc,
+			BlockedSince: since,
+			WaitChannel:  readWaitChannel(proc.PID),
+		})
+	}
+
+	// Longest-blocked first
+	sort.Slice(blocked, func(i, j int) bool {
+		return blocked[i].BlockedSince.Before(blocked[j].BlockedSince)
+	})
+
+	return blocked
+}
+
+// GetDuplicateProcesses groups processes by command line and returns the
+// groups with more than maxInstances processes, largest first. Processes
+// whose name matches one of the exclude globs, and kernel threads without a
+// command line, are ignored.
+func (ps *ProcessService) GetDuplicateProcesses(processes []*models.ProcessInfo, maxInstances int, exclude []string) []*models.DuplicateGroup {
+	byCommand := make(map[string][]*models.ProcessInfo)
+	for _, proc := range processes {
+		if proc.Command == "" || matchesAny(proc.Name, exclude) {
+			continue
+		}
+		byCommand[proc.Command] = append(byCommand[proc.Command], proc)
+	}
+
+	var groups []*models.DuplicateGroup
+	for command, procs := range byCommand {
+		if len(procs) <= maxInstances {
+			continue
+		}
+		sort.Slice(procs, func(i, j int) bool {
+			return procs[i].CreateTime.Before(procs[j].CreateTime)
+		})
+		groups = append(groups, &models.DuplicateGroup{Command: command, Processes: procs})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Processes) != len(groups[j].Processes) {
+			return len(groups[i].Processes) > len(groups[j].Processes)
+		}
+		return groups[i].Command < groups[j].Command
+	})
+
+	return groups
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readWaitChannel returns the kernel function a process is sleeping in (Linux only)
+func readWaitChannel(pid int32) string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/wchan", pid))
+	if err != nil {
+		return ""
+	}
+
+	wchan := strings.TrimSpace(string(data))
+	if wchan == "0" {
+		return ""
+	}
+	return wchan
+}
+
+// FilterProcesses filters processes based on criteria.
+// The matching processes are returned in a new slice; processes itself is
+// left untouched, so callers can keep using the full list.
 func (ps *ProcessService) FilterProcesses(processes []*models.ProcessInfo, filter *models.ProcessFilter) []*models.ProcessInfo {
-	var filtered []*models.ProcessInfo
+	return ps.FilterProcessesInto(make([]*models.ProcessInfo, 0, len(processes)), processes, filter)
+}
+
+// FilterProcessesInto is FilterProcesses writing the matching processes over
+// dst instead, so a caller filtering on every refresh can keep reusing one
+// buffer. dst must not share its array with processes.
+func (ps *ProcessService) FilterProcessesInto(dst, processes []*models.ProcessInfo, filter *models.ProcessFilter) []*models.ProcessInfo {
+	// Nothing to filter out, skip checking every process
+	if isEmptyFilter(filter) {
+		all := append(dst[:0], processes...)
+		clear(all[len(all):cap(all)])
+		return all
+	}
 
+	// Prepare search terms and directory once rather than per process
+	searchTerm := strings.ToLower(filter.SearchTerm)
+	excludeTerm := strings.ToLower(filter.ExcludeSearchTerm)
+	dir, dirPrefix := "", ""
+	if filter.PathPrefix != "" {
+		dir = filepath.Clean(filter.PathPrefix)
+		dirPrefix = strings.TrimSuffix(dir, string(filepath.Separator)) + string(filepath.Separator)
+	}
+
+	filtered := dst[:0]
 	for _, proc := range processes {
 		// Search term filter
-		if filter.SearchTerm != "" {
-			searchTerm := strings.ToLower(filter.SearchTerm)
-			if !strings.Contains(strings.ToLower(proc.Name), searchTerm) &&
-				!strings.Contains(strings.ToLower(proc.Command), searchTerm) &&
-				!strings.Contains(strings.ToLower(proc.Username), searchTerm) {
+		if searchTerm != "" {
+			if !containsFold(proc.Name, searchTerm) &&
+				!containsFold(proc.Command, searchTerm) &&
+				!containsFold(proc.Username, searchTerm) {
 				continue
 			}
 		}
 
 		// CPU filter
-		if proc.CPU < filter.MinCPU || proc.CPU > filter.MaxCPU {
+		if !withinBounds(proc.CPU, filter.MinCPU, filter.MaxCPU) {
 			continue
 		}
 
 		// Memory filter
-		if proc.Memory < filter.MinMemory || proc.Memory > filter.MaxMemory {
+		if !withinBounds(proc.Memory, filter.MinMemory, filter.MaxMemory) {
 			continue
 		}
 
@@ -160,100 +487,198 @@ func (ps *ProcessService) FilterProcesses(processes []*models.ProcessInfo, filte
 			continue
 		}
 
+		// Session filter
+		if filter.SessionID != 0 && proc.SessionID != filter.SessionID {
+			continue
+		}
+
+		// Directory filter
+		if dir != "" && !hasDirPrefix(proc.WorkingDir, dir, dirPrefix) && !hasDirPrefix(proc.Executable, dir, dirPrefix) {
+			continue
+		}
+
+		// Virtualization filter
+		if (filter.Virtualized == models.VirtualizedOnly && proc.Virtualization == "") ||
+			(filter.Virtualized == models.VirtualizedHide && proc.Virtualization != "") {
+			continue
+		}
+
 		// System process filter
 		if !filter.ShowSystem && ps.isSystemProcess(proc) {
 			continue
 		}
 
+		// Exclusions
+		if slices.Contains(filter.ExcludeUsers, proc.Username) ||
+			slices.ContainsFunc(filter.ExcludeNames, func(name string) bool { return strings.EqualFold(name, proc.Name) }) {
+			continue
+		}
+		if excludeTerm != "" &&
+			(containsFold(proc.Name, excludeTerm) || containsFold(proc.Command, excludeTerm) || containsFold(proc.Username, excludeTerm)) {
+			continue
+		}
+
 		filtered = append(filtered, proc)
 	}
 
+	// Drop what dst held past the matches, so it keeps no exited process alive
+	clear(filtered[len(filtered):cap(filtered)])
 	return filtered
 }
 
-// SortProcesses sorts processes based on criteria
+// withinBounds reports whether value is at least low and at most high, a
+// zero high setting no upper bound. Filters left at their zero value, such
+// as the one the Processes view starts with or a --filter without cpu:,
+// would otherwise hide every process using any CPU or memory at all.
+func withinBounds(value, low, high float64) bool {
+	return value >= low && (high <= 0 || value <= high)
+}
+
+// isEmptyFilter reports whether filter would keep every process
+func isEmptyFilter(filter *models.ProcessFilter) bool {
+	return filter.SearchTerm == "" &&
+		filter.MinCPU <= 0 && filter.MaxCPU <= 0 &&
+		filter.MinMemory <= 0 && filter.MaxMemory <= 0 &&
+		filter.Status == "" &&
+		filter.Username == "" &&
+		filter.SessionID == 0 &&
+		filter.PathPrefix == "" &&
+		filter.Virtualized == "" &&
+		filter.ShowSystem &&
+		len(filter.ExcludeUsers) == 0 &&
+		len(filter.ExcludeNames) == 0 &&
+		filter.ExcludeSearchTerm == ""
+}
+
+// containsFold reports whether s contains the lower-case substr, ignoring
+// the case of s, without allocating a lower-cased copy of s
+func containsFold(s, substr string) bool {
+	n := len(substr)
+	if n == 0 {
+		return true
+	}
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDirPrefix reports whether path is the cleaned dir or starts with
+// dirPrefix, which is dir with a trailing separator
+func hasDirPrefix(path, dir, dirPrefix string) bool {
+	if path == "" {
+		return false
+	}
+
+	path = filepath.Clean(path)
+	return path == dir || strings.HasPrefix(path, dirPrefix)
+}
+
+// SortProcesses sorts processes based on criteria.
+// Processes that are already in order are left untouched.
 func (ps *ProcessService) SortProcesses(processes []*models.ProcessInfo, sortConfig *models.ProcessSort) {
-	switch sortConfig.Field {
+	compare := processComparator(sortConfig.Field, sortConfig.Order)
+	if compare == nil {
+		return
+	}
+
+	// Skip the sort entirely when nothing moved since the last refresh
+	if slices.IsSortedFunc(processes, compare) {
+		return
+	}
+
+	slices.SortFunc(processes, compare)
+}
+
+// processComparator returns the comparison for field and order, breaking ties
+// by PID so rows keep a stable order between refreshes. It returns nil for
+// unknown fields.
+func processComparator(field, order string) func(a, b *models.ProcessInfo) int {
+	var compare func(a, b *models.ProcessInfo) int
+	switch field {
 	case "cpu":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].CPU < processes[j].CPU
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].CPU > processes[j].CPU
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.CPU, b.CPU) }
 	case "memory":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Memory < processes[j].Memory
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Memory > processes[j].Memory
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.Memory, b.Memory) }
+	case "memory_bytes":
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.MemoryBytes, b.MemoryBytes) }
 	case "pid":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].PID < processes[j].PID
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].PID > processes[j].PID
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.PID, b.PID) }
 	case "name":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Name < processes[j].Name
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Name > processes[j].Name
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return strings.Compare(a.Name, b.Name) }
 	case "status":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Status < processes[j].Status
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Status > processes[j].Status
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return strings.Compare(a.Status, b.Status) }
 	case "threads":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].NumThreads < processes[j].NumThreads
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].NumThreads > processes[j].NumThreads
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.NumThreads, b.NumThreads) }
 	case "nice":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Nice < processes[j].Nice
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Nice > processes[j].Nice
-			})
-		}
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(a.Nice, b.Nice) }
 	case "user":
-		if sortConfig.Order == "asc" {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Username < processes[j].Username
-			})
-		} else {
-			sort.Slice(processes, func(i, j int) bool {
-				return processes[i].Username > processes[j].Username
-			})
+		compare = func(a, b *models.ProcessInfo) int { return strings.Compare(a.Username, b.Username) }
+	case "io_read":
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(ioReadBytes(a), ioReadBytes(b)) }
+	case "io_write":
+		compare = func(a, b *models.ProcessInfo) int { return cmp.Compare(ioWriteBytes(a), ioWriteBytes(b)) }
+	default:
+		return nil
+	}
+
+	if order == "asc" {
+		return func(a, b *models.ProcessInfo) int {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.PID, b.PID)
+		}
+	}
+	return func(a, b *models.ProcessInfo) int {
+		if c := compare(b, a); c != 0 {
+			return c
 		}
+		return cmp.Compare(a.PID, b.PID)
+	}
+}
+
+// ioReadBytes returns the bytes proc has read, zero when unknown
+func ioReadBytes(proc *models.ProcessInfo) uint64 {
+	if proc.IO == nil {
+		return 0
+	}
+	return proc.IO.ReadBytes
+}
+
+// ioWriteBytes returns the bytes proc has written, zero when unknown
+func ioWriteBytes(proc *models.ProcessInfo) uint64 {
+	if proc.IO == nil {
+		return 0
+	}
+	return proc.IO.WriteBytes
+}
+
+// SortUserStats sorts per-user statistics by the given field in descending order
+func (ps *ProcessService) SortUserStats(userStats []*models.UserStats, field string) {
+	switch field {
+	case "cpu":
+		sort.Slice(userStats, func(i, j int) bool {
+			return userStats[i].CPU > userStats[j].CPU
+		})
+	case "memory":
+		sort.Slice(userStats, func(i, j int) bool {
+			return userStats[i].MemoryBytes > userStats[j].MemoryBytes
+		})
+	case "processes":
+		sort.Slice(userStats, func(i, j int) bool {
+			return userStats[i].ProcessCount > userStats[j].ProcessCount
+		})
+	case "threads":
+		sort.Slice(userStats, func(i, j int) bool {
+			return userStats[i].ThreadCount > userStats[j].ThreadCount
+		})
+	case "user":
+		sort.Slice(userStats, func(i, j int) bool {
+			return userStats[i].Username < userStats[j].Username
+		})
 	}
 }
 
@@ -286,18 +711,544 @@ func (ps *ProcessService) isSystemProcess(proc *models.ProcessInfo) bool {
 	return false
 }
 
+// Signals that can be sent with SendSignal
+const (
+	SignalTerm = "SIGTERM"
+	SignalHup  = "SIGHUP"
+	SignalInt  = "SIGINT"
+	SignalStop = "SIGSTOP"
+	SignalCont = "SIGCONT"
+	SignalKill = "SIGKILL"
+	// Signals runtimes act on, sent by RunRuntimeAction
+	SignalQuit = "SIGQUIT"
+	SignalUsr1 = "SIGUSR1"
+)
+
+// Signals lists the signals SendSignal accepts, gentlest first
+var Signals = []string{SignalTerm, SignalHup, SignalInt, SignalStop, SignalCont, SignalKill}
+
 // KillProcess attempts to kill a process
 func (ps *ProcessService) KillProcess(pid int32) error {
+	return ps.SendSignal(pid, SignalKill)
+}
+
+// terminatePollInterval is how often TerminateGracefully checks whether the
+// process has exited
+const terminatePollInterval = 100 * time.Millisecond
+
+// TerminateGracefully sends SIGTERM to pid and, if it is still running after
+// timeout, SIGKILL. It reports whether the process had to be killed.
+func (ps *ProcessService) TerminateGracefully(pid int32, timeout time.Duration) (bool, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return false, fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+	createTime, _ := proc.CreateTime()
+
+	if err := ps.SendSignal(pid, SignalTerm); err != nil {
+		return false, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for ps.isRunning(pid, createTime) {
+		if time.Now().After(deadline) {
+			return true, ps.SendSignal(pid, SignalKill)
+		}
+		time.Sleep(terminatePollInterval)
+	}
+	return false, nil
+}
+
+// isRunning reports whether the process started at createTime still runs as
+// pid. Zombies have exited and only wait for their parent to reap them.
+func (ps *ProcessService) isRunning(pid int32, createTime int64) bool {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	if started, err := proc.CreateTime(); err == nil && started != createTime {
+		return false
+	}
+	status, err := proc.Status()
+	return err != nil || len(status) == 0 || status[0] != process.Zombie
+}
+
+// SuspendProcess pauses pid with SIGSTOP until ResumeProcess continues it
+func (ps *ProcessService) SuspendProcess(pid int32) error {
+	return ps.SendSignal(pid, SignalStop)
+}
+
+// ResumeProcess continues a process paused by SuspendProcess
+func (ps *ProcessService) ResumeProcess(pid int32) error {
+	return ps.SendSignal(pid, SignalCont)
+}
+
+// SendSignal sends the named signal, one of Signals or the runtime signals,
+// to pid. SIGSTOP and SIGCONT suspend and resume the process, which also
+// works on Windows.
+func (ps *ProcessService) SendSignal(pid int32, signal string) error {
+	// A stopped task manager could never be told to continue itself
+	if signal == SignalStop && int(pid) == os.Getpid() {
+		return fmt.Errorf("cannot suspend the task manager itself")
+	}
+
 	proc, err := process.NewProcess(pid)
 	if err != nil {
 		return fmt.Errorf("failed to get process %d: %w", pid, err)
 	}
 
-	if err := proc.Kill(); err != nil {
-		return fmt.Errorf("failed to kill process %d: %w", pid, err)
+	name, _ := proc.Name()
+	switch signal {
+	case SignalTerm:
+		err = proc.Terminate()
+	case SignalHup:
+		err = proc.SendSignal(syscall.SIGHUP)
+	case SignalInt:
+		err = proc.SendSignal(syscall.SIGINT)
+	case SignalStop:
+		err = proc.Suspend()
+	case SignalCont:
+		err = proc.Resume()
+	case SignalKill:
+		err = proc.Kill()
+	case SignalQuit:
+		err = proc.SendSignal(syscall.SIGQUIT)
+	case SignalUsr1:
+		err = sendUser1(proc)
+	default:
+		return fmt.Errorf("unsupported signal %s", signal)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to send %s to process %d: %w", signal, pid, err)
 	}
 
-	return nil
+	ps.recordSignal(pid, name, signal, err)
+	return err
+}
+
+// recordSignal records an attempt to signal pid in the event log
+func (ps *ProcessService) recordSignal(pid int32, name, signal string, err error) {
+	event := models.Event{
+		Kind:    models.EventSignal,
+		Level:   models.EventNotice,
+		Message: fmt.Sprintf("Sent %s to process %s (PID %d)", signal, name, pid),
+		Fields: map[string]string{
+			"pid":    strconv.Itoa(int(pid)),
+			"name":   name,
+			"signal": signal,
+			"uid":    strconv.Itoa(os.Getuid()), // who sent it
+		},
+	}
+	if signal == SignalKill {
+		event.Kind = models.EventKill
+		event.Message = fmt.Sprintf("Killed process %s (PID %d)", name, pid)
+	}
+	if err != nil {
+		event.Level = models.EventWarning
+		event.Message = fmt.Sprintf("Failed to send %s to process %s (PID %d)", signal, name, pid)
+		event.Fields["error"] = err.Error()
+	}
+	ps.RecordEvent(event)
+}
+
+// Range of nice values; lower values get more CPU time
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// SetNice changes the nice value of pid. Lowering it, or changing another
+// user's process, needs root.
+func (ps *ProcessService) SetNice(pid int32, value int) error {
+	if value < MinNice || value > MaxNice {
+		return fmt.Errorf("nice value must be between %d and %d", MinNice, MaxNice)
+	}
+
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+	name, _ := proc.Name()
+
+	err = setPriority(pid, value)
+	if errors.Is(err, os.ErrPermission) {
+		err = fmt.Errorf("permission denied: lowering the nice value or renicing another user's process needs root")
+	} else if err != nil {
+		err = fmt.Errorf("failed to renice process %d: %w", pid, err)
+	}
+
+	event := models.Event{
+		Kind:    models.EventRenice,
+		Level:   models.EventNotice,
+		Message: fmt.Sprintf("Set nice value of process %s (PID %d) to %d", name, pid, value),
+		Fields: map[string]string{
+			"pid":  strconv.Itoa(int(pid)),
+			"name": name,
+			"nice": strconv.Itoa(value),
+			"uid":  strconv.Itoa(os.Getuid()),
+		},
+	}
+	if err != nil {
+		event.Level = models.EventWarning
+		event.Message = fmt.Sprintf("Failed to set nice value of process %s (PID %d) to %d", name, pid, value)
+		event.Fields["error"] = err.Error()
+	}
+	ps.RecordEvent(event)
+	return err
+}
+
+// IsSameProcess reports whether pid still belongs to the process started at
+// createTime, guarding actions against PID reuse
+func (ps *ProcessService) IsSameProcess(pid int32, createTime time.Time) bool {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	started, err := proc.CreateTime()
+	if err != nil {
+		// Without a start time to compare, trust the PID
+		return true
+	}
+	return createTime.IsZero() || started == createTime.UnixMilli()
+}
+
+// GetConnections returns the sockets held open by pid, ordered by protocol
+// and local address
+func (ps *ProcessService) GetConnections(pid int32) ([]models.Connection, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+
+	stats, err := proc.Connections()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections of process %d: %w", pid, err)
+	}
+
+	connections := make([]models.Connection, 0, len(stats))
+	for _, stat := range stats {
+		connections = append(connections, models.Connection{
+			Protocol:   connectionProtocol(stat),
+			LocalAddr:  connectionAddr(stat, stat.Laddr),
+			RemoteAddr: connectionAddr(stat, stat.Raddr),
+			Status:     connectionStatus(stat.Status),
+		})
+	}
+	slices.SortFunc(connections, func(a, b models.Connection) int {
+		if c := strings.Compare(a.Protocol, b.Protocol); c != 0 {
+			return c
+		}
+		return strings.Compare(a.LocalAddr, b.LocalAddr)
+	})
+	return connections, nil
+}
+
+// GetListeningPorts returns the TCP sockets listening on the host and the
+// unconnected UDP sockets, ordered by port. A socket shared by several
+// processes, such as a server's workers, is listed once with the lowest PID.
+func (ps *ProcessService) GetListeningPorts(processes []*models.ProcessInfo) ([]models.ListeningPort, error) {
+	stats, err := psnet.Connections("inet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	names := make(map[int32]string, len(processes))
+	for _, proc := range processes {
+		names[proc.PID] = proc.Name
+	}
+
+	type socket struct {
+		protocol, address string
+		port              uint32
+	}
+	ports := make(map[socket]models.ListeningPort)
+	for _, stat := range stats {
+		listening := stat.Status == "LISTEN" ||
+			(stat.Type == syscall.SOCK_DGRAM && stat.Raddr.Port == 0)
+		if !listening {
+			continue
+		}
+
+		key := socket{connectionProtocol(stat), stat.Laddr.IP, stat.Laddr.Port}
+		if existing, ok := ports[key]; ok && existing.PID != 0 && (stat.Pid == 0 || existing.PID <= stat.Pid) {
+			continue
+		}
+		ports[key] = models.ListeningPort{
+			Protocol: key.protocol,
+			Address:  key.address,
+			Port:     key.port,
+			PID:      stat.Pid,
+			Process:  names[stat.Pid],
+		}
+	}
+
+	listening := make([]models.ListeningPort, 0, len(ports))
+	for _, port := range ports {
+		listening = append(listening, port)
+	}
+	slices.SortFunc(listening, func(a, b models.ListeningPort) int {
+		if a.Port != b.Port {
+			return int(a.Port) - int(b.Port)
+		}
+		if c := strings.Compare(a.Protocol, b.Protocol); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Address, b.Address)
+	})
+	return listening, nil
+}
+
+// GetDependencies maps which local processes talk to each other over TCP.
+// Every established connection whose two ends are on this host, with the
+// remote end on a listening port, links the client to the server process
+// that accepted it. Dependencies are ordered by server, port and client.
+func (ps *ProcessService) GetDependencies(processes []*models.ProcessInfo) ([]models.Dependency, error) {
+	stats, err := psnet.Connections("tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	names := make(map[int32]string, len(processes))
+	for _, proc := range processes {
+		names[proc.PID] = proc.Name
+	}
+
+	type endpoint struct {
+		ip   string
+		port uint32
+	}
+	listeners := make(map[uint32][]psnet.ConnectionStat) // by port
+	owners := make(map[[2]endpoint]int32)                // by local and remote end
+	for _, stat := range stats {
+		switch stat.Status {
+		case "LISTEN":
+			listeners[stat.Laddr.Port] = append(listeners[stat.Laddr.Port], stat)
+		case "ESTABLISHED":
+			local := endpoint{normalizeIP(stat.Laddr.IP), stat.Laddr.Port}
+			remote := endpoint{normalizeIP(stat.Raddr.IP), stat.Raddr.Port}
+			owners[[2]endpoint{local, remote}] = stat.Pid
+		}
+	}
+
+	type link struct {
+		client, server int32
+		port           uint32
+	}
+	counts := make(map[link]int)
+	for _, stat := range stats {
+		if stat.Status != "ESTABLISHED" {
+			continue
+		}
+		local := endpoint{normalizeIP(stat.Laddr.IP), stat.Laddr.Port}
+		remote := endpoint{normalizeIP(stat.Raddr.IP), stat.Raddr.Port}
+
+		// The other end must be a socket on this host
+		server, ok := owners[[2]endpoint{remote, local}]
+		if !ok {
+			continue
+		}
+		listener := -1
+		for i, l := range listeners[remote.port] {
+			if ip := normalizeIP(l.Laddr.IP); ip == remote.ip || net.ParseIP(ip).IsUnspecified() {
+				listener = i
+				break
+			}
+		}
+		if listener < 0 {
+			// This is the server's end of the connection
+			continue
+		}
+		// Accepted sockets belong to the process serving them, such as a
+		// worker, which is more precise than the listener
+		if server == 0 {
+			server = listeners[remote.port][listener].Pid
+		}
+		if server == stat.Pid {
+			continue
+		}
+		counts[link{stat.Pid, server, remote.port}]++
+	}
+
+	dependencies := make([]models.Dependency, 0, len(counts))
+	for l, n := range counts {
+		dependencies = append(dependencies, models.Dependency{
+			ClientPID:   l.client,
+			ClientName:  names[l.client],
+			ServerPID:   l.server,
+			ServerName:  names[l.server],
+			Port:        l.port,
+			Connections: n,
+		})
+	}
+	slices.SortFunc(dependencies, func(a, b models.Dependency) int {
+		return cmp.Or(
+			strings.Compare(a.ServerName, b.ServerName),
+			cmp.Compare(a.ServerPID, b.ServerPID),
+			cmp.Compare(a.Port, b.Port),
+			strings.Compare(a.ClientName, b.ClientName),
+			cmp.Compare(a.ClientPID, b.ClientPID),
+		)
+	})
+	return dependencies, nil
+}
+
+// normalizeIP writes IPv4-mapped IPv6 addresses as plain IPv4, so both ends
+// of a connection between a tcp and a tcp6 socket compare equal
+func normalizeIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		if v4 := parsed.To4(); v4 != nil {
+			return v4.String()
+		}
+	}
+	return ip
+}
+
+// GetOpenFiles returns the files held open by pid, ordered by descriptor
+func (ps *ProcessService) GetOpenFiles(pid int32) ([]models.OpenFile, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+
+	stats, err := proc.OpenFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open files of process %d: %w", pid, err)
+	}
+
+	files := make([]models.OpenFile, 0, len(stats))
+	for _, stat := range stats {
+		files = append(files, models.OpenFile{FD: stat.Fd, Path: stat.Path})
+	}
+	slices.SortFunc(files, func(a, b models.OpenFile) int {
+		return cmp.Compare(a.FD, b.FD)
+	})
+	return files, nil
+}
+
+// sensitiveEnvPatterns mark environment variables whose values are likely
+// credentials, matched case-insensitively anywhere in the name
+var sensitiveEnvPatterns = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "CREDENTIAL", "API_KEY", "PRIVATE_KEY"}
+
+// GetEnvironment returns the environment of pid ordered by name, flagging
+// variables that look like credentials
+func (ps *ProcessService) GetEnvironment(pid int32) ([]models.EnvVar, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+
+	environ, err := proc.Environ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment of process %d: %w", pid, err)
+	}
+
+	vars := make([]models.EnvVar, 0, len(environ))
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if name == "" {
+			continue
+		}
+		vars = append(vars, models.EnvVar{
+			Name:      name,
+			Value:     value,
+			Sensitive: isSensitiveEnv(name),
+		})
+	}
+	slices.SortFunc(vars, func(a, b models.EnvVar) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return vars, nil
+}
+
+// isSensitiveEnv reports whether the variable name matches a sensitive pattern
+func isSensitiveEnv(name string) bool {
+	name = strings.ToUpper(name)
+	for _, pattern := range sensitiveEnvPatterns {
+		if strings.Contains(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetThreads returns the threads of pid with the CPU time each has used,
+// the busiest first
+func (ps *ProcessService) GetThreads(pid int32) ([]models.Thread, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process %d: %w", pid, err)
+	}
+
+	times, err := proc.Threads()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get threads of process %d: %w", pid, err)
+	}
+
+	threads := make([]models.Thread, 0, len(times))
+	for tid, stat := range times {
+		threads = append(threads, models.Thread{
+			TID:    tid,
+			Name:   readThreadName(pid, tid),
+			User:   stat.User,
+			System: stat.System,
+		})
+	}
+	slices.SortFunc(threads, func(a, b models.Thread) int {
+		return cmp.Or(cmp.Compare(b.User+b.System, a.User+a.System), cmp.Compare(a.TID, b.TID))
+	})
+	return threads, nil
+}
+
+// readThreadName returns the name of thread tid of pid, which Linux keeps
+// apart from the process's name; empty elsewhere
+func readThreadName(pid, tid int32) string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	procRoot := cmp.Or(os.Getenv("HOST_PROC"), "/proc")
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(int(pid)), "task", strconv.Itoa(int(tid)), "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// connectionProtocol names the protocol of a socket from its family and type
+func connectionProtocol(stat psnet.ConnectionStat) string {
+	if stat.Family == syscall.AF_UNIX {
+		return "unix"
+	}
+
+	protocol := "tcp"
+	if stat.Type == syscall.SOCK_DGRAM {
+		protocol = "udp"
+	}
+	if stat.Family == syscall.AF_INET6 {
+		protocol += "6"
+	}
+	return protocol
+}
+
+// connectionStatus returns the TCP state of a socket, empty for
+// connectionless sockets which gopsutil reports as NONE
+func connectionStatus(status string) string {
+	if status == "NONE" {
+		return ""
+	}
+	return status
+}
+
+// connectionAddr formats addr as host:port, or as the socket path for Unix
+// sockets. Unbound addresses are empty.
+func connectionAddr(stat psnet.ConnectionStat, addr psnet.Addr) string {
+	if stat.Family == syscall.AF_UNIX {
+		return addr.IP
+	}
+	if addr.Port == 0 && (addr.IP == "" || net.ParseIP(addr.IP).IsUnspecified()) {
+		return ""
+	}
+	return net.JoinHostPort(addr.IP, strconv.Itoa(int(addr.Port)))
 }
 
 // GetProcessTree returns a hierarchical view of processes
@@ -311,6 +1262,18 @@ func (ps *ProcessService) GetProcessTree(processes []*models.ProcessInfo) map[in
 	return tree
 }
 
+// GetChildren returns the processes among processes that pid started, in
+// the order they are listed
+func (ps *ProcessService) GetChildren(processes []*models.ProcessInfo, pid int32) []*models.ProcessInfo {
+	var children []*models.ProcessInfo
+	for _, proc := range processes {
+		if proc.PPID == pid && proc.PID != pid {
+			children = append(children, proc)
+		}
+	}
+	return children
+}
+
 // GetProcessStats returns statistics about the processes
 func (ps *ProcessService) GetProcessStats(processes []*models.ProcessInfo) map[string]interface{} {
 	stats := make(map[string]interface{})
@@ -319,9 +1282,11 @@ func (ps *ProcessService) GetProcessStats(processes []*models.ProcessInfo) map[s
 	runningProcesses := 0
 	totalCPU := 0.0
 	totalMemory := 0.0
+	var totalMemoryBytes uint64
 	
 	statusCounts := make(map[string]int)
 	userCounts := make(map[string]int)
+	userStats := make(map[string]*models.UserStats)
 	
 	for _, proc := range processes {
 		if proc.IsRunning {
@@ -330,17 +1295,36 @@ func (ps *ProcessService) GetProcessStats(processes []*models.ProcessInfo) map[s
 		
 		totalCPU += proc.CPU
 		totalMemory += proc.Memory
+		totalMemoryBytes += proc.MemoryBytes
 		
 		statusCounts[proc.Status]++
 		userCounts[proc.Username]++
+
+		us, ok := userStats[proc.Username]
+		if !ok {
+			us = &models.UserStats{Username: proc.Username}
+			userStats[proc.Username] = us
+		}
+		us.ProcessCount++
+		us.ThreadCount += int(proc.NumThreads)
+		us.CPU += proc.CPU
+		us.Memory += proc.Memory
+		us.MemoryBytes += proc.MemoryBytes
+	}
+
+	userStatsList := make([]*models.UserStats, 0, len(userStats))
+	for _, us := range userStats {
+		userStatsList = append(userStatsList, us)
 	}
 	
 	stats["total_processes"] = totalProcesses
 	stats["running_processes"] = runningProcesses
 	stats["total_cpu"] = totalCPU
 	stats["total_memory"] = totalMemory
+	stats["total_memory_bytes"] = totalMemoryBytes
 	stats["status_counts"] = statusCounts
 	stats["user_counts"] = userCounts
+	stats["user_stats"] = userStatsList
 	
 	return stats
 }
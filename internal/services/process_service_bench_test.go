@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/testutil"
+)
+
+// benchSizes are the synthetic process counts each benchmark runs against
+var benchSizes = []int{100, 1000, 10000}
+
+func BenchmarkGetProcesses(b *testing.B) {
+	ps := NewProcessService(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ps.GetProcesses(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterProcesses(b *testing.B) {
+	filters := []struct {
+		name   string
+		filter *models.ProcessFilter
+	}{
+		{"none", &models.ProcessFilter{}},
+		{"search", &models.ProcessFilter{SearchTerm: "Postgres"}},
+		{"user", &models.ProcessFilter{Username: "alice"}},
+		{"path", &models.ProcessFilter{PathPrefix: "/home/alice"}},
+	}
+
+	ps := NewProcessService(nil)
+	for _, n := range benchSizes {
+		processes := testutil.SyntheticProcesses(n)
+		for _, f := range filters {
+			b.Run(fmt.Sprintf("%s/n=%d", f.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					ps.FilterProcesses(processes, f.filter)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkSortProcesses(b *testing.B) {
+	ps := NewProcessService(nil)
+	for _, n := range benchSizes {
+		processes := testutil.SyntheticProcesses(n)
+		work := make([]*models.ProcessInfo, n)
+		for _, field := range []string{"cpu", "memory", "pid", "name"} {
+			sortConfig := &models.ProcessSort{Field: field, Order: "desc"}
+			b.Run(fmt.Sprintf("%s/n=%d", field, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					// Start from the same unsorted order every iteration
+					b.StopTimer()
+					copy(work, processes)
+					b.StartTimer()
+					ps.SortProcesses(work, sortConfig)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+
+	"tappmanager/internal/models"
+)
+
+func TestFilterProcessesBounds(t *testing.T) {
+	processes := []*models.ProcessInfo{
+		{PID: 1, Name: "idle", CPU: 0, Memory: 0.1},
+		{PID: 2, Name: "busy", CPU: 150, Memory: 40},
+		{PID: 3, Name: "steady", CPU: 20, Memory: 5},
+	}
+
+	tests := []struct {
+		name   string
+		filter models.ProcessFilter
+		pids   []int32
+	}{
+		{"zero filter keeps everything", models.ProcessFilter{}, []int32{1, 2, 3}},
+		{"minimum only", models.ProcessFilter{MinCPU: 10}, []int32{2, 3}},
+		{"maximum only", models.ProcessFilter{MaxCPU: 100}, []int32{1, 3}},
+		{"range", models.ProcessFilter{MinCPU: 10, MaxCPU: 100}, []int32{3}},
+		{"bounds are inclusive", models.ProcessFilter{MinCPU: 20, MaxCPU: 20}, []int32{3}},
+		{"memory range", models.ProcessFilter{MinMemory: 1, MaxMemory: 10}, []int32{3}},
+		{"memory maximum only", models.ProcessFilter{MaxMemory: 10}, []int32{1, 3}},
+	}
+
+	ps := NewProcessService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.ShowSystem = true
+			got := ps.FilterProcesses(processes, &filter)
+			if len(got) != len(tt.pids) {
+				t.Fatalf("got %d processes, want PIDs %v", len(got), tt.pids)
+			}
+			for i, pid := range tt.pids {
+				if got[i].PID != pid {
+					t.Errorf("process %d is PID %d, want %d", i, got[i].PID, pid)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterProcessesIntoReusesBuffer(t *testing.T) {
+	processes := []*models.ProcessInfo{
+		{PID: 1, Name: "idle"},
+		{PID: 2, Name: "busy", CPU: 150},
+		{PID: 3, Name: "steady", CPU: 20},
+	}
+	ps := NewProcessService(nil)
+	buffer := make([]*models.ProcessInfo, 0, len(processes))
+
+	all := ps.FilterProcessesInto(buffer, processes, &models.ProcessFilter{ShowSystem: true})
+	if len(all) != 3 || &all[0] != &buffer[:1][0] {
+		t.Fatalf("got %d processes outside the buffer, want all 3 in it", len(all))
+	}
+
+	busy := ps.FilterProcessesInto(all, processes, &models.ProcessFilter{ShowSystem: true, MinCPU: 100})
+	if len(busy) != 1 || busy[0].PID != 2 || &busy[0] != &buffer[:1][0] {
+		t.Fatalf("got %v, want PID 2 in the buffer", busy)
+	}
+	// The processes no longer matching must not be kept alive by the buffer
+	for i, proc := range busy[1:cap(busy)] {
+		if proc != nil {
+			t.Errorf("buffer still holds PID %d at %d", proc.PID, i+1)
+		}
+	}
+}
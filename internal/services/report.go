@@ -0,0 +1,603 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+)
+
+// Report formats
+const (
+	ReportMarkdown = "markdown"
+	ReportHTML     = "html"
+	ReportPDF      = "pdf"
+)
+
+// ReportOptions choose what goes into a report
+type ReportOptions struct {
+	Sections []string      // from models.ReportSections, in the order they are shown
+	TopN     int           // processes in each top list
+	Window   time.Duration // how far back load history and alerts go
+	Watch    []string      // process names for the watch section
+	DataDir  string        // where the event history is kept
+
+	// LoadHistory supplies the load samples of a running instance; there
+	// is no load history without one
+	LoadHistory LoadHistorySource
+}
+
+// ParseReportSections parses a comma-separated list of report sections, or
+// "all" for every section
+func ParseReportSections(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "all" {
+		return slices.Clone(models.ReportSections), nil
+	}
+
+	var sections []string
+	for _, section := range strings.Split(list, ",") {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if section == "" || slices.Contains(sections, section) {
+			continue
+		}
+		if !slices.Contains(models.ReportSections, section) {
+			return nil, fmt.Errorf("unknown report section %q (expected %s)", section, strings.Join(models.ReportSections, ", "))
+		}
+		sections = append(sections, section)
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no report sections chosen")
+	}
+	return sections, nil
+}
+
+// BuildReport collects the data of the chosen sections. The diff section
+// compares the running processes with the snapshot saved in store.
+func BuildReport(processService *ProcessService, systemService *SystemService, store storage.Storage, opts ReportOptions) (*models.Report, error) {
+	processes, err := processService.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.Report{
+		GeneratedAt: time.Now(),
+		Sections:    opts.Sections,
+	}
+	hostname := ""
+	if info, err := systemService.GetSystemInfo(); err == nil {
+		report.Host = info
+		hostname = info.Hostname
+	}
+
+	for _, section := range opts.Sections {
+		switch section {
+		case models.ReportSummary:
+			report.Summary = summarize(processes, hostname)
+			report.StatusCounts = make(map[string]int)
+			for _, proc := range processes {
+				report.StatusCounts[proc.Status]++
+			}
+
+		case models.ReportTop:
+			report.TopN = opts.TopN
+			report.TopCPU = topProcesses(processes, opts.TopN, func(proc *models.ProcessInfo) float64 { return proc.CPU })
+			report.TopMemory = topProcesses(processes, opts.TopN, func(proc *models.ProcessInfo) float64 { return proc.Memory })
+
+		case models.ReportUsers:
+			report.Users = processService.GetProcessStats(processes)["user_stats"].([]*models.UserStats)
+			processService.SortUserStats(report.Users, "cpu")
+
+		case models.ReportLoad:
+			report.Window = opts.Window
+			if opts.LoadHistory != nil {
+				// Without a running instance there is simply no history
+				report.LoadHistory, _ = opts.LoadHistory.LoadHistory(report.GeneratedAt.Add(-opts.Window))
+			}
+
+		case models.ReportAlerts:
+			report.Window = opts.Window
+			report.Alerts, err = ReadEventHistory(opts.DataDir, report.GeneratedAt.Add(-opts.Window))
+			if err != nil {
+				return nil, err
+			}
+
+		case models.ReportWatch:
+			report.Watched = watchedProcesses(processes, opts.Watch)
+
+		case models.ReportDiff:
+			snapshot, err := store.LoadProcessSnapshot()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load process snapshot: %w", err)
+			}
+			report.Diff = diffProcesses(snapshot, processes)
+
+		default:
+			return nil, fmt.Errorf("unknown report section %q", section)
+		}
+	}
+	return report, nil
+}
+
+// topProcesses returns the n processes with the highest usage, highest first
+func topProcesses(processes []*models.ProcessInfo, n int, usage func(*models.ProcessInfo) float64) []*models.ProcessInfo {
+	top := slices.Clone(processes)
+	slices.SortStableFunc(top, func(a, b *models.ProcessInfo) int {
+		return cmp.Or(cmp.Compare(usage(b), usage(a)), cmp.Compare(a.PID, b.PID))
+	})
+	return top[:min(n, len(top))]
+}
+
+// diffProcesses lists the processes that started and exited since snapshot.
+// A process is the same one when both its PID and start time match, so a
+// reused PID counts as one process exiting and another starting.
+func diffProcesses(snapshot, processes []*models.ProcessInfo) *models.ProcessDiff {
+	diff := &models.ProcessDiff{
+		HasSnapshot: len(snapshot) > 0,
+		Started:     []*models.ProcessInfo{},
+		Exited:      []*models.ProcessInfo{},
+	}
+	if !diff.HasSnapshot {
+		return diff
+	}
+
+	type key struct {
+		pid     int32
+		created int64
+	}
+	keyOf := func(proc *models.ProcessInfo) key {
+		return key{proc.PID, proc.CreateTime.UnixMilli()}
+	}
+
+	before := make(map[key]bool, len(snapshot))
+	for _, proc := range snapshot {
+		before[keyOf(proc)] = true
+	}
+	now := make(map[key]bool, len(processes))
+	for _, proc := range processes {
+		now[keyOf(proc)] = true
+		if !before[keyOf(proc)] {
+			diff.Started = append(diff.Started, proc)
+		}
+	}
+	for _, proc := range snapshot {
+		if !now[keyOf(proc)] {
+			diff.Exited = append(diff.Exited, proc)
+		}
+	}
+
+	byPID := func(a, b *models.ProcessInfo) int { return cmp.Compare(a.PID, b.PID) }
+	slices.SortFunc(diff.Started, byPID)
+	slices.SortFunc(diff.Exited, byPID)
+	return diff
+}
+
+// reportDocument is a report laid out as headed sections of paragraphs and
+// tables, ready to be written in any format
+type reportDocument struct {
+	Title    string
+	Subtitle string
+	Sections []reportSection
+}
+
+type reportSection struct {
+	Title  string
+	Blocks []reportBlock
+}
+
+// reportBlock is a paragraph of Text, a table when Columns is set or a
+// chart. Charts repeat data shown in a table, so only PDFs draw them.
+type reportBlock struct {
+	Text    string
+	Columns []string
+	Rows    [][]string
+	Bars    *reportBars
+	Lines   *reportLines
+}
+
+// reportBars is a bar chart of one value per label
+type reportBars struct {
+	Title  string
+	Format string // formats each value, e.g. "%.1f%%"
+	Labels []string
+	Values []float64
+}
+
+// reportLines is a line chart of series sampled at the same times
+type reportLines struct {
+	Title     string
+	Times     []time.Time
+	Series    []reportSeries
+	Limit     float64 // drawn across the chart when set, e.g. the core count
+	LimitName string
+}
+
+type reportSeries struct {
+	Name   string
+	Values []float64
+}
+
+// WriteReport writes report to w in format
+func WriteReport(w io.Writer, report *models.Report, format string) error {
+	doc := layoutReport(report)
+	switch format {
+	case ReportMarkdown:
+		return writeMarkdownReport(w, doc)
+	case ReportHTML:
+		return reportTemplate.Execute(w, doc)
+	case ReportPDF:
+		return writePDFReport(w, doc, report.GeneratedAt)
+	default:
+		return fmt.Errorf("unknown report format %q (expected markdown, html or pdf)", format)
+	}
+}
+
+// layoutReport turns the sections of report into text and tables
+func layoutReport(report *models.Report) *reportDocument {
+	doc := &reportDocument{
+		Title:    "Process Report",
+		Subtitle: "Generated " + report.GeneratedAt.Format("2006-01-02 15:04:05 MST"),
+	}
+	if host := report.Host; host != nil {
+		doc.Title += ": " + host.Hostname
+		doc.Subtitle += fmt.Sprintf(" on %s %s (%s) with %s, up %s",
+			host.Platform, host.PlatformVersion, host.KernelArch, formatCores(host.CPUCores), formatReportDuration(host.Uptime()))
+	}
+
+	for _, section := range report.Sections {
+		switch section {
+		case models.ReportSummary:
+			doc.Sections = append(doc.Sections, summarySection(report))
+		case models.ReportTop:
+			doc.Sections = append(doc.Sections, topSection(report))
+		case models.ReportUsers:
+			doc.Sections = append(doc.Sections, usersSection(report))
+		case models.ReportLoad:
+			doc.Sections = append(doc.Sections, loadSection(report))
+		case models.ReportAlerts:
+			doc.Sections = append(doc.Sections, alertsSection(report))
+		case models.ReportWatch:
+			doc.Sections = append(doc.Sections, watchSection(report))
+		case models.ReportDiff:
+			doc.Sections = append(doc.Sections, diffSection(report))
+		}
+	}
+	return doc
+}
+
+func summarySection(report *models.Report) reportSection {
+	summary := report.Summary
+	section := reportSection{Title: "Summary"}
+	section.Blocks = append(section.Blocks,
+		reportBlock{Text: fmt.Sprintf("%d processes, %d running.", summary.Processes, summary.Running)},
+		reportBlock{
+			Columns: []string{"Metric", "Value"},
+			Rows: [][]string{
+				{"CPU (all processes, percent of one core)", fmt.Sprintf("%.1f%%", summary.CPU)},
+				{"Memory (all processes)", fmt.Sprintf("%.1f%%", summary.Memory)},
+				{"Load average (1, 5, 15 min)", fmt.Sprintf("%.2f, %.2f, %.2f", summary.Load1, summary.Load5, summary.Load15)},
+			},
+		},
+	)
+
+	statuses := make([]string, 0, len(report.StatusCounts))
+	for status := range report.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b string) int {
+		return cmp.Or(cmp.Compare(report.StatusCounts[b], report.StatusCounts[a]), strings.Compare(a, b))
+	})
+	counts := reportBlock{Columns: []string{"Status", "Processes"}}
+	chart := &reportBars{Title: "Processes per status", Format: "%.0f"}
+	for _, status := range statuses {
+		counts.Rows = append(counts.Rows, []string{status, fmt.Sprint(report.StatusCounts[status])})
+		chart.Labels = append(chart.Labels, status)
+		chart.Values = append(chart.Values, float64(report.StatusCounts[status]))
+	}
+	section.Blocks = append(section.Blocks, counts, reportBlock{Bars: chart})
+	return section
+}
+
+func topSection(report *models.Report) reportSection {
+	return reportSection{
+		Title: fmt.Sprintf("Top %d Processes", report.TopN),
+		Blocks: []reportBlock{
+			{Text: "By CPU:"},
+			processTable(report.TopCPU),
+			{Bars: processBars("CPU % by process", report.TopCPU, func(proc *models.ProcessInfo) float64 { return proc.CPU })},
+			{Text: "By memory:"},
+			processTable(report.TopMemory),
+			{Bars: processBars("Memory % by process", report.TopMemory, func(proc *models.ProcessInfo) float64 { return proc.Memory })},
+		},
+	}
+}
+
+// processBars charts the usage of processes, labelled by name and PID
+func processBars(title string, processes []*models.ProcessInfo, usage func(*models.ProcessInfo) float64) *reportBars {
+	chart := &reportBars{Title: title, Format: "%.1f%%"}
+	for _, proc := range processes {
+		chart.Labels = append(chart.Labels, fmt.Sprintf("%s (%d)", proc.Name, proc.PID))
+		chart.Values = append(chart.Values, usage(proc))
+	}
+	return chart
+}
+
+// processTable lists processes with their usage
+func processTable(processes []*models.ProcessInfo) reportBlock {
+	table := reportBlock{Columns: []string{"PID", "Name", "User", "CPU %", "Memory %", "Memory"}}
+	for _, proc := range processes {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprint(proc.PID), proc.Name, proc.Username,
+			fmt.Sprintf("%.1f", proc.CPU), fmt.Sprintf("%.1f", proc.Memory), formatBytes(proc.MemoryBytes),
+		})
+	}
+	return table
+}
+
+// reportChartUsers is how many users the CPU chart of the users section shows
+const reportChartUsers = 10
+
+func usersSection(report *models.Report) reportSection {
+	table := reportBlock{Columns: []string{"User", "Processes", "Threads", "CPU %", "Memory %", "Memory"}}
+	chart := &reportBars{Title: "CPU % by user", Format: "%.1f%%"}
+	for i, us := range report.Users {
+		table.Rows = append(table.Rows, []string{
+			us.Username, fmt.Sprint(us.ProcessCount), fmt.Sprint(us.ThreadCount),
+			fmt.Sprintf("%.1f", us.CPU), fmt.Sprintf("%.1f", us.Memory), formatBytes(us.MemoryBytes),
+		})
+		if i < reportChartUsers {
+			chart.Labels = append(chart.Labels, us.Username)
+			chart.Values = append(chart.Values, us.CPU)
+		}
+	}
+	return reportSection{
+		Title:  "Usage by User",
+		Blocks: []reportBlock{table, {Bars: chart}},
+	}
+}
+
+// reportLoadRows is how many periods the load table of the load section
+// splits the history into
+const reportLoadRows = 12
+
+func loadSection(report *models.Report) reportSection {
+	section := reportSection{Title: "Load Average"}
+	samples := report.LoadHistory
+	if len(samples) == 0 {
+		section.Blocks = append(section.Blocks, reportBlock{Text: "No load history: it is recorded by a running tappmanager daemon or UI."})
+		return section
+	}
+
+	cores := runtime.NumCPU()
+	if report.Host != nil && report.Host.CPUCores > 0 {
+		cores = report.Host.CPUCores
+	}
+	peak, sum := samples[0], 0.0
+	chart := &reportLines{
+		Title:     "Load average",
+		Series:    []reportSeries{{Name: "1 min"}, {Name: "5 min"}, {Name: "15 min"}},
+		Limit:     float64(cores),
+		LimitName: formatCores(cores),
+	}
+	for _, sample := range samples {
+		if sample.Load1 > peak.Load1 {
+			peak = sample
+		}
+		sum += sample.Load1
+		chart.Times = append(chart.Times, sample.Timestamp)
+		chart.Series[0].Values = append(chart.Series[0].Values, sample.Load1)
+		chart.Series[1].Values = append(chart.Series[1].Values, sample.Load5)
+		chart.Series[2].Values = append(chart.Series[2].Values, sample.Load15)
+	}
+	section.Blocks = append(section.Blocks,
+		reportBlock{Text: fmt.Sprintf("Over the last %s the 1-minute load averaged %.2f and peaked at %.2f at %s, with %s.",
+			formatReportDuration(report.Window), sum/float64(len(samples)), peak.Load1, peak.Timestamp.Format("2006-01-02 15:04:05"), formatCores(cores))},
+		reportBlock{Lines: chart},
+	)
+
+	// Summarize the history in equal periods, leaving out empty ones
+	first, last := samples[0].Timestamp, samples[len(samples)-1].Timestamp
+	period := max(last.Sub(first)/reportLoadRows, time.Second)
+	layout := "2006-01-02 15:04"
+	if period < time.Minute {
+		layout = "2006-01-02 15:04:05"
+	}
+	table := reportBlock{Columns: []string{"From", "Average", "Peak", "Most Running", "Most Blocked"}}
+	for start := 0; start < len(samples); {
+		periodEnd := first.Add(period * (samples[start].Timestamp.Sub(first)/period + 1))
+		end := start
+		var total, highest float64
+		var running, blocked int
+		for ; end < len(samples) && samples[end].Timestamp.Before(periodEnd); end++ {
+			total += samples[end].Load1
+			highest = max(highest, samples[end].Load1)
+			running = max(running, samples[end].ProcsRunning)
+			blocked = max(blocked, samples[end].ProcsBlocked)
+		}
+		table.Rows = append(table.Rows, []string{
+			samples[start].Timestamp.Format(layout), fmt.Sprintf("%.2f", total/float64(end-start)),
+			fmt.Sprintf("%.2f", highest), fmt.Sprint(running), fmt.Sprint(blocked),
+		})
+		start = end
+	}
+	section.Blocks = append(section.Blocks, table)
+	return section
+}
+
+func alertsSection(report *models.Report) reportSection {
+	section := reportSection{Title: "Alerts"}
+	window := formatReportDuration(report.Window)
+	if len(report.Alerts) == 0 {
+		section.Blocks = append(section.Blocks, reportBlock{Text: "No events were recorded in the last " + window + "."})
+		return section
+	}
+
+	section.Blocks = append(section.Blocks, reportBlock{Text: fmt.Sprintf("Events in the last %s, oldest first: %d.", window, len(report.Alerts))})
+	table := reportBlock{Columns: []string{"Time", "Level", "Kind", "Message"}}
+	for _, event := range report.Alerts {
+		table.Rows = append(table.Rows, []string{event.Time.Format("2006-01-02 15:04:05"), event.Level, event.Kind, event.Message})
+	}
+	section.Blocks = append(section.Blocks, table)
+	return section
+}
+
+func watchSection(report *models.Report) reportSection {
+	section := reportSection{Title: "Watch List"}
+	if len(report.Watched) == 0 {
+		section.Blocks = append(section.Blocks, reportBlock{Text: "No process names are watched."})
+		return section
+	}
+
+	table := reportBlock{Columns: []string{"Name", "Status", "Processes", "PIDs", "CPU %", "Memory %"}}
+	for _, w := range report.Watched {
+		status := "not running"
+		if w.Running {
+			status = "running"
+		}
+		pids := make([]string, len(w.PIDs))
+		for i, pid := range w.PIDs {
+			pids[i] = fmt.Sprint(pid)
+		}
+		table.Rows = append(table.Rows, []string{
+			w.Name, status, fmt.Sprint(w.Count), strings.Join(pids, " "),
+			fmt.Sprintf("%.1f", w.CPU), fmt.Sprintf("%.1f", w.Memory),
+		})
+	}
+	section.Blocks = append(section.Blocks, table)
+	return section
+}
+
+func diffSection(report *models.Report) reportSection {
+	diff := report.Diff
+	section := reportSection{Title: "Changes Since the Last Snapshot"}
+	if !diff.HasSnapshot {
+		section.Blocks = append(section.Blocks, reportBlock{Text: "No process snapshot has been saved, so there is nothing to compare with."})
+		return section
+	}
+
+	section.Blocks = append(section.Blocks, reportBlock{Text: fmt.Sprintf("%d processes started and %d exited.", len(diff.Started), len(diff.Exited))})
+	for _, change := range []struct {
+		label     string
+		processes []*models.ProcessInfo
+	}{{"Started:", diff.Started}, {"Exited:", diff.Exited}} {
+		if len(change.processes) == 0 {
+			continue
+		}
+		table := reportBlock{Columns: []string{"PID", "Name", "User", "Started", "Command"}}
+		for _, proc := range change.processes {
+			table.Rows = append(table.Rows, []string{
+				fmt.Sprint(proc.PID), proc.Name, proc.Username, proc.CreateTime.Format("2006-01-02 15:04:05"), proc.Command,
+			})
+		}
+		section.Blocks = append(section.Blocks, reportBlock{Text: change.label}, table)
+	}
+	return section
+}
+
+// formatCores renders a CPU core count, e.g. "4 CPU cores"
+func formatCores(cores int) string {
+	if cores == 1 {
+		return "1 CPU core"
+	}
+	return fmt.Sprintf("%d CPU cores", cores)
+}
+
+// formatReportDuration renders a duration without zero minutes and seconds,
+// e.g. 24h rather than 24h0m0s
+func formatReportDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "0m"
+	}
+	text := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// formatBytes renders a byte count using binary units (KiB, MiB, GiB, ...)
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// writeMarkdownReport writes doc as Markdown with pipe tables
+func writeMarkdownReport(w io.Writer, doc *reportDocument) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n_%s_\n", doc.Title, doc.Subtitle)
+	for _, section := range doc.Sections {
+		fmt.Fprintf(&b, "\n## %s\n", section.Title)
+		for _, block := range section.Blocks {
+			if block.Text != "" {
+				fmt.Fprintf(&b, "\n%s\n", block.Text)
+			}
+			if block.Columns == nil {
+				continue
+			}
+			b.WriteString("\n" + markdownRow(block.Columns))
+			b.WriteString("|" + strings.Repeat(" --- |", len(block.Columns)) + "\n")
+			for _, row := range block.Rows {
+				b.WriteString(markdownRow(row))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownRow writes cells as a table row, escaping what would break it
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// reportTemplate renders a report as a standalone HTML page
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+.subtitle { color: #666; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 0.2em; margin-top: 1.5em; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ddd; padding: 0.25em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+tr:nth-child(even) td { background: #fafafa; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="subtitle">{{.Subtitle}}</p>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{range .Blocks}}{{if .Columns}}<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{else if .Text}}<p>{{.Text}}</p>
+{{end}}{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Layout of PDF reports, in points
+const (
+	pdfMargin      = 50.0
+	pdfTextSize    = 10.0
+	pdfTableSize   = 8.0
+	pdfLineSpacing = 1.4 // line height as a multiple of the font size
+)
+
+// Layout of charts in PDF reports, in points
+const (
+	pdfChartTextSize = 7.0
+	pdfBarHeight     = 10.0
+	pdfBarLabelWidth = 140.0
+	pdfLineHeight    = 150.0 // height of the plot area of line charts
+	pdfAxisWidth     = 30.0  // room left of line charts for the axis labels
+)
+
+// pdfFont is one of the standard fonts every PDF reader has, which need
+// no font files
+type pdfFont struct {
+	family, style string
+}
+
+var (
+	pdfHelvetica     = pdfFont{"Helvetica", ""}
+	pdfHelveticaBold = pdfFont{"Helvetica", "B"}
+	pdfCourier       = pdfFont{"Courier", ""}
+)
+
+// pdfColor is an RGB color
+type pdfColor struct {
+	R, G, B int
+}
+
+var (
+	pdfBlack     = pdfColor{0, 0, 0}
+	pdfGray      = pdfColor{R: 110, G: 110, B: 110}
+	pdfHeaderRow = pdfColor{R: 235, G: 235, B: 235}
+	pdfRule      = pdfColor{R: 200, G: 200, B: 200}
+	pdfLimit     = pdfColor{R: 200, G: 40, B: 40}
+)
+
+// pdfSeriesColors color the bars and the series of line charts in turn
+var pdfSeriesColors = []pdfColor{
+	{R: 52, G: 101, B: 164},
+	{R: 237, G: 137, B: 54},
+	{R: 78, G: 154, B: 6},
+	{R: 117, G: 80, B: 123},
+}
+
+// pdfLayout places text down the pages of a document, starting a new page
+// when the current one is full. Coordinates are in points from the top left
+// corner of the page.
+type pdfLayout struct {
+	doc *fpdf.Fpdf
+	// encode converts text to the Windows-1252 encoding of the standard
+	// fonts; other characters are written as "."
+	encode        func(string) string
+	width, height float64 // of the page
+	started       bool    // a page has been added
+	y             float64 // top of the next line
+}
+
+// writePDFReport writes doc as an A4 PDF
+func writePDFReport(w io.Writer, doc *reportDocument, generated time.Time) error {
+	pdf := fpdf.New("P", "pt", "A4", "")
+	// Pages are laid out here, so fpdf neither breaks pages nor adds margins
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetProducer("tappmanager", true)
+	pdf.SetCreationDate(generated)
+
+	layout := &pdfLayout{doc: pdf, encode: pdf.UnicodeTranslatorFromDescriptor("")}
+	layout.width, layout.height = pdf.GetPageSize()
+
+	layout.text(doc.Title, pdfHelveticaBold, 18, pdfBlack)
+	layout.paragraph(doc.Subtitle, pdfGray)
+	for _, section := range doc.Sections {
+		layout.space(12)
+		layout.need(3 * 14 * pdfLineSpacing)
+		layout.text(section.Title, pdfHelveticaBold, 14, pdfBlack)
+		layout.rule()
+		for _, block := range section.Blocks {
+			switch {
+			case block.Bars != nil:
+				layout.bars(block.Bars)
+			case block.Lines != nil:
+				layout.lines(block.Lines)
+			case block.Columns != nil:
+				layout.table(block.Columns, block.Rows)
+			default:
+				layout.paragraph(block.Text, pdfBlack)
+			}
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+// need starts a new page unless height fits on the current one
+func (l *pdfLayout) need(height float64) {
+	if !l.started || l.y+height > l.height-pdfMargin {
+		l.doc.AddPage()
+		l.started = true
+		l.y = pdfMargin
+	}
+}
+
+// space leaves a gap before the next line, unless at the top of a page
+func (l *pdfLayout) space(height float64) {
+	if l.started && l.y > pdfMargin {
+		l.y += height
+	}
+}
+
+// text writes one line
+func (l *pdfLayout) text(text string, font pdfFont, size float64, color pdfColor) {
+	height := size * pdfLineSpacing
+	l.need(height)
+	l.drawText(pdfMargin, l.y+size, font, size, color, text)
+	l.y += height
+}
+
+// drawText draws text on the current page with its baseline at y
+func (l *pdfLayout) drawText(x, y float64, font pdfFont, size float64, color pdfColor, text string) {
+	l.doc.SetFont(font.family, font.style, size)
+	l.doc.SetTextColor(color.R, color.G, color.B)
+	l.doc.Text(x, y, l.encode(text))
+}
+
+// textWidth returns the width of text in font at size
+func (l *pdfLayout) textWidth(font pdfFont, size float64, text string) float64 {
+	l.doc.SetFont(font.family, font.style, size)
+	return l.doc.GetStringWidth(l.encode(text))
+}
+
+// line draws a straight line on the current page
+func (l *pdfLayout) line(x1, y1, x2, y2, width float64, color pdfColor) {
+	l.doc.SetLineWidth(width)
+	l.doc.SetDrawColor(color.R, color.G, color.B)
+	l.doc.Line(x1, y1, x2, y2)
+}
+
+// rect fills the rectangle whose top left corner is at x, y
+func (l *pdfLayout) rect(x, y, width, height float64, color pdfColor) {
+	l.doc.SetFillColor(color.R, color.G, color.B)
+	l.doc.Rect(x, y, width, height, "F")
+}
+
+// rule draws a line across the page under the last line
+func (l *pdfLayout) rule() {
+	l.line(pdfMargin, l.y, l.width-pdfMargin, l.y, 0.5, pdfRule)
+	l.y += 4
+}
+
+// paragraph writes text wrapped to the width of the page
+func (l *pdfLayout) paragraph(text string, color pdfColor) {
+	width := l.width - 2*pdfMargin
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line != "" && l.textWidth(pdfHelvetica, pdfTextSize, candidate) > width {
+			l.text(line, pdfHelvetica, pdfTextSize, color)
+			candidate = word
+		}
+		line = candidate
+	}
+	l.text(line, pdfHelvetica, pdfTextSize, color)
+}
+
+// table writes rows in fixed-width columns, shortening the widest columns
+// until the table fits the page. The header row is repeated on each page.
+func (l *pdfLayout) table(columns []string, rows [][]string) {
+	const gap = 2
+	charWidth := l.textWidth(pdfCourier, pdfTableSize, " ")
+	available := int((l.width-2*pdfMargin)/charWidth) - gap*(len(columns)-1)
+
+	widths := make([]int, len(columns))
+	for _, row := range append([][]string{columns}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	for sumWidths(widths) > available {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		widths[widest]--
+	}
+
+	format := func(row []string) string {
+		var b strings.Builder
+		for i, cell := range row {
+			cell = strings.Join(strings.Fields(cell), " ")
+			if utf8.RuneCountInString(cell) > widths[i] {
+				cell = string([]rune(cell)[:widths[i]-1]) + "…"
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+gap))
+			}
+		}
+		return b.String()
+	}
+
+	height := pdfTableSize * pdfLineSpacing
+	header := func() {
+		l.rect(pdfMargin-2, l.y, l.width-2*pdfMargin+4, height, pdfHeaderRow)
+		l.text(format(columns), pdfCourier, pdfTableSize, pdfBlack)
+	}
+
+	l.space(4)
+	l.need(2 * height)
+	header()
+	for _, row := range rows {
+		if l.y+height > l.height-pdfMargin {
+			l.need(height * 2)
+			header()
+		}
+		l.text(format(row), pdfCourier, pdfTableSize, pdfBlack)
+	}
+	l.space(6)
+}
+
+// sumWidths adds up the column widths
+func sumWidths(widths []int) int {
+	sum := 0
+	for _, width := range widths {
+		sum += width
+	}
+	return sum
+}
+
+// bars draws a horizontal bar chart, labels on the left and each value at
+// the end of its bar
+func (l *pdfLayout) bars(chart *reportBars) {
+	if len(chart.Values) == 0 {
+		return
+	}
+	highest := slices.Max(chart.Values)
+	if highest <= 0 {
+		highest = 1
+	}
+	// Leave room after the longest bar for its value
+	barArea := l.width - 2*pdfMargin - pdfBarLabelWidth - 40
+
+	l.space(4)
+	l.need(2*pdfChartTextSize*pdfLineSpacing + pdfBarHeight*2)
+	l.text(chart.Title, pdfHelveticaBold, pdfChartTextSize+1, pdfBlack)
+	for i, value := range chart.Values {
+		l.need(pdfBarHeight + 2)
+		top := l.y
+		label := l.fitText(chart.Labels[i], pdfHelvetica, pdfChartTextSize, pdfBarLabelWidth-6)
+		l.drawText(pdfMargin, top+pdfChartTextSize+1, pdfHelvetica, pdfChartTextSize, pdfBlack, label)
+
+		width := barArea * max(value, 0) / highest
+		l.rect(pdfMargin+pdfBarLabelWidth, top, width, pdfBarHeight, pdfSeriesColors[0])
+		l.drawText(pdfMargin+pdfBarLabelWidth+width+3, top+pdfChartTextSize+1, pdfHelvetica, pdfChartTextSize, pdfGray, fmt.Sprintf(chart.Format, value))
+		l.y += pdfBarHeight + 2
+	}
+	l.space(6)
+}
+
+// lines draws a line chart with the time along the bottom, the values up
+// the left and a legend underneath
+func (l *pdfLayout) lines(chart *reportLines) {
+	if len(chart.Times) == 0 {
+		return
+	}
+	highest := chart.Limit
+	for _, series := range chart.Series {
+		highest = max(highest, slices.Max(series.Values))
+	}
+	if highest <= 0 {
+		highest = 1
+	}
+	highest *= 1.1
+
+	left := pdfMargin + pdfAxisWidth
+	width := l.width - pdfMargin - left
+	first, last := chart.Times[0], chart.Times[len(chart.Times)-1]
+	span := max(last.Sub(first), time.Second)
+	x := func(t time.Time) float64 { return left + width*float64(t.Sub(first))/float64(span) }
+
+	l.space(4)
+	l.need(pdfChartTextSize*pdfLineSpacing*3 + pdfLineHeight + 8)
+	l.text(chart.Title, pdfHelveticaBold, pdfChartTextSize+1, pdfBlack)
+	top := l.y + 4
+	bottom := top + pdfLineHeight
+	y := func(value float64) float64 { return bottom - pdfLineHeight*value/highest }
+
+	// Gridlines at a quarter of the range each
+	for i := 0; i <= 4; i++ {
+		value := highest * float64(i) / 4
+		l.line(left, y(value), left+width, y(value), 0.3, pdfRule)
+		label := fmt.Sprintf("%.2f", value)
+		l.drawText(left-4-l.textWidth(pdfHelvetica, pdfChartTextSize, label), y(value)+2, pdfHelvetica, pdfChartTextSize, pdfGray, label)
+	}
+	if chart.Limit > 0 {
+		l.line(left, y(chart.Limit), left+width, y(chart.Limit), 0.8, pdfLimit)
+		l.drawText(left+2, y(chart.Limit)-2, pdfHelvetica, pdfChartTextSize, pdfLimit, chart.LimitName)
+	}
+
+	l.doc.SetLineJoinStyle("round")
+	for i, series := range chart.Series {
+		if len(series.Values) < 2 {
+			continue
+		}
+		color := pdfSeriesColors[i%len(pdfSeriesColors)]
+		l.doc.SetLineWidth(1)
+		l.doc.SetDrawColor(color.R, color.G, color.B)
+		for j, value := range series.Values {
+			if j == 0 {
+				l.doc.MoveTo(x(chart.Times[j]), y(value))
+			} else {
+				l.doc.LineTo(x(chart.Times[j]), y(value))
+			}
+		}
+		l.doc.DrawPath("D")
+	}
+	l.doc.SetLineJoinStyle("miter")
+
+	// Times at the start, middle and end of the axis
+	layout := "15:04"
+	switch {
+	case span > 24*time.Hour:
+		layout = "01-02 15:04"
+	case span < 10*time.Minute:
+		layout = "15:04:05"
+	}
+	for i, t := range []time.Time{first, first.Add(span / 2), last} {
+		label := t.Format(layout)
+		labelWidth := l.textWidth(pdfHelvetica, pdfChartTextSize, label)
+		l.drawText(x(t)-labelWidth*float64(i)/2, bottom+pdfChartTextSize+3, pdfHelvetica, pdfChartTextSize, pdfGray, label)
+	}
+
+	legendX := left
+	legendY := bottom + 2*pdfChartTextSize + 8
+	for i, series := range chart.Series {
+		color := pdfSeriesColors[i%len(pdfSeriesColors)]
+		l.rect(legendX, legendY-pdfChartTextSize+1, 8, pdfChartTextSize-1, color)
+		l.drawText(legendX+11, legendY, pdfHelvetica, pdfChartTextSize, pdfBlack, series.Name)
+		legendX += 11 + l.textWidth(pdfHelvetica, pdfChartTextSize, series.Name) + 12
+	}
+	l.y = legendY + pdfChartTextSize
+	l.space(6)
+}
+
+// fitText shortens text with an ellipsis until it fits in width
+func (l *pdfLayout) fitText(text string, font pdfFont, size, width float64) string {
+	if l.textWidth(font, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && l.textWidth(font, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
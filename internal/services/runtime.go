@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"debug/buildinfo"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// runtimeToolTimeout bounds how long jstack, jstat or py-spy may take
+const runtimeToolTimeout = 10 * time.Second
+
+// pythonExecutable matches interpreter names such as python3.12 or pypy3,
+// capturing the version
+var pythonExecutable = regexp.MustCompile(`^(?:python|pypy)([0-9.]*)w?$`)
+
+// DetectRuntime works out the language runtime proc runs on. The JVM, Python
+// and Node are recognised by the name of their executable and Go binaries by
+// the build information the Go linker embeds, which also tells the Go
+// version and main module. It returns nil when the runtime is not recognised.
+func DetectRuntime(proc *models.ProcessInfo) *models.RuntimeInfo {
+	executable := proc.Executable
+	if executable == "" && len(proc.Args) > 0 {
+		executable = proc.Args[0]
+	}
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(executable), ".exe"))
+
+	switch {
+	case IsJVM(proc):
+		return &models.RuntimeInfo{Name: models.RuntimeJVM, Main: mainArgument(proc.Args, "-jar", "-cp", "-classpath", "--class-path", "-p", "--module-path")}
+
+	case pythonExecutable.MatchString(name):
+		version := pythonExecutable.FindStringSubmatch(name)[1]
+		return &models.RuntimeInfo{Name: models.RuntimePython, Version: version, Main: mainArgument(proc.Args, "-m", "-X", "-W")}
+
+	case name == "node" || name == "nodejs":
+		return &models.RuntimeInfo{Name: models.RuntimeNode, Main: mainArgument(proc.Args, "-r", "--require")}
+	}
+
+	if proc.Executable == "" {
+		return nil
+	}
+	info, err := buildinfo.ReadFile(proc.Executable)
+	if err != nil {
+		return nil
+	}
+	return &models.RuntimeInfo{Name: models.RuntimeGo, Version: info.GoVersion, Main: info.Path}
+}
+
+// IsJVM reports whether proc runs the java launcher
+func IsJVM(proc *models.ProcessInfo) bool {
+	executable := proc.Executable
+	if executable == "" && len(proc.Args) > 0 {
+		executable = proc.Args[0]
+	}
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(executable), ".exe"))
+	return name == "java" || name == "javaw"
+}
+
+// mainArgument returns the first argument after the executable that is not
+// an option, skipping the values of the options in valued. The value of
+// -jar or -m names the program itself, so it is returned, and programs
+// passed inline with -c or -e show as that option.
+func mainArgument(args []string, valued ...string) string {
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-jar" || arg == "-m") && i+1 < len(args):
+			return args[i+1]
+		case arg == "-c" || arg == "-e":
+			return arg
+		case slices.Contains(valued, arg):
+			i++
+		case !strings.HasPrefix(arg, "-"):
+			return arg
+		}
+	}
+	return ""
+}
+
+// RuntimeAction describes what RunRuntimeAction does for runtime, empty when
+// there is nothing to do. Exits reports whether the process exits after.
+func RuntimeAction(runtime string) (action string, exits bool) {
+	switch runtime {
+	case models.RuntimeJVM:
+		return "Dump thread stacks with jstack", false
+	case models.RuntimePython:
+		return "Dump thread stacks with py-spy", false
+	case models.RuntimeGo:
+		return "Send SIGQUIT: Go writes its goroutine stacks to standard error and exits", true
+	case models.RuntimeNode:
+		return "Send SIGUSR1 to start the inspector on 127.0.0.1:9229", false
+	}
+	return "", false
+}
+
+// RunRuntimeAction runs the action of runtime for pid described by
+// RuntimeAction. The stacks jstack and py-spy print are returned; Go writes
+// its own to the process's standard error, so nothing is returned for it,
+// nor for starting Node's inspector.
+func (ps *ProcessService) RunRuntimeAction(pid int32, runtime string) (string, error) {
+	switch runtime {
+	case models.RuntimeJVM:
+		return runTool(pid, "jstack", strconv.Itoa(int(pid)))
+	case models.RuntimePython:
+		return runTool(pid, "py-spy", "dump", "--pid", strconv.Itoa(int(pid)))
+	case models.RuntimeGo:
+		return "", ps.SendSignal(pid, SignalQuit)
+	case models.RuntimeNode:
+		return "", ps.SendSignal(pid, SignalUsr1)
+	}
+	return "", fmt.Errorf("no runtime action for process %d", pid)
+}
+
+// runTool runs a diagnostic tool for pid and returns what it prints
+func runTool(pid int32, tool string, args ...string) (string, error) {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return "", fmt.Errorf("%s is not installed or not on the PATH", tool)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeToolTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s did not finish within %s", tool, runtimeToolTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s failed for process %d: %w: %s", tool, pid, err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
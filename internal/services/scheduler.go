@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// maxFinishedActions is how many completed or cancelled actions are kept for display
+const maxFinishedActions = 20
+
+// Scheduler runs actions on processes at a set time while the application is open
+type Scheduler struct {
+	mu             sync.Mutex
+	processService *ProcessService
+	nextID         int
+	pending        []*models.ScheduledAction
+	finished       []*models.ScheduledAction // newest first
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(processService *ProcessService) *Scheduler {
+	return &Scheduler{
+		processService: processService,
+		nextID:         1,
+	}
+}
+
+// ScheduleKill schedules proc to be killed at the given time
+func (s *Scheduler) ScheduleKill(proc *models.ProcessInfo, at time.Time) *models.ScheduledAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action := &models.ScheduledAction{
+		ID:          s.nextID,
+		Action:      models.ActionKill,
+		PID:         proc.PID,
+		Name:        proc.Name,
+		CreateTime:  proc.CreateTime,
+		At:          at,
+		ScheduledAt: time.Now(),
+		State:       models.ActionPending,
+	}
+	s.nextID++
+
+	s.pending = append(s.pending, action)
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		return s.pending[i].At.Before(s.pending[j].At)
+	})
+	return action
+}
+
+// Cancel cancels a pending action, reporting whether it was found
+func (s *Scheduler) Cancel(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, action := range s.pending {
+		if action.ID == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			action.State = models.ActionCancelled
+			s.finish(action)
+			return true
+		}
+	}
+	return false
+}
+
+// RunDue runs every pending action due at now and returns them with their outcome
+func (s *Scheduler) RunDue(now time.Time) []models.ScheduledAction {
+	s.mu.Lock()
+	var due []*models.ScheduledAction
+	for len(s.pending) > 0 && !s.pending[0].At.After(now) {
+		due = append(due, s.pending[0])
+		s.pending = s.pending[1:]
+	}
+	s.mu.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	results := make([]models.ScheduledAction, 0, len(due))
+	for _, action := range due {
+		s.run(action)
+		results = append(results, *action)
+	}
+
+	s.mu.Lock()
+	for _, action := range due {
+		s.finish(action)
+	}
+	s.mu.Unlock()
+
+	return results
+}
+
+// run performs a due action and records its outcome
+func (s *Scheduler) run(action *models.ScheduledAction) {
+	action.RanAt = time.Now()
+
+	// The process may have exited and its PID been reused since scheduling
+	if !s.processService.IsSameProcess(action.PID, action.CreateTime) {
+		action.State = models.ActionSkipped
+		action.Error = "process already exited"
+		return
+	}
+
+	if err := s.processService.KillProcess(action.PID); err != nil {
+		action.State = models.ActionFailed
+		action.Error = err.Error()
+		return
+	}
+	action.State = models.ActionDone
+}
+
+// finish moves an action to the finished list. Callers must hold the lock.
+func (s *Scheduler) finish(action *models.ScheduledAction) {
+	s.finished = append([]*models.ScheduledAction{action}, s.finished...)
+	if len(s.finished) > maxFinishedActions {
+		s.finished = s.finished[:maxFinishedActions]
+	}
+}
+
+// Pending returns copies of the pending actions, soonest first
+func (s *Scheduler) Pending() []models.ScheduledAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyActions(s.pending)
+}
+
+// Finished returns copies of recently completed and cancelled actions, newest first
+func (s *Scheduler) Finished() []models.ScheduledAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyActions(s.finished)
+}
+
+// copyActions copies actions so callers cannot race with the scheduler
+func copyActions(actions []*models.ScheduledAction) []models.ScheduledAction {
+	result := make([]models.ScheduledAction, len(actions))
+	for i, action := range actions {
+		result[i] = *action
+	}
+	return result
+}
+
+// ParseScheduleTime parses when an action should run: a duration from now such
+// as 30m or 1h30m, or a clock time such as 18:00, which means tomorrow once it
+// has passed today
+func ParseScheduleTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+
+	if d, err := time.ParseDuration(input); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration must be positive: %s", input)
+		}
+		return now.Add(d), nil
+	}
+
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		clock, err := time.ParseInLocation(layout, input, now.Location())
+		if err != nil {
+			continue
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: use a clock time like 18:00 or a duration like 30m", input)
+}
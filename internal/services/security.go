@@ -0,0 +1,24 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// readSecurityContext returns the LSM label of a process: the SELinux
+// context or AppArmor profile on Linux. Other platforms return "".
+func readSecurityContext(pid int32) string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/attr/current", pid))
+	if err != nil {
+		return ""
+	}
+
+	// The kernel NUL-terminates some labels
+	return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// readSessionInfo returns the process group ID, session ID and controlling
+// terminal device number of a process, zero if it has none. Values are read
+// from /proc/<pid>/stat and are only available on Linux.
+func readSessionInfo(pid int32) (int32, int32, uint64) {
+	if runtime.GOOS != "linux" {
+		return 0, 0, 0
+	}
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, 0
+	}
+
+	return parseSessionStat(string(data))
+}
+
+// parseSessionStat extracts pgrp, session and tty_nr from /proc/<pid>/stat
+// contents
+func parseSessionStat(stat string) (int32, int32, uint64) {
+	// The command name is wrapped in parentheses and may itself contain
+	// spaces or parentheses, so fields are counted from the last ')'
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return 0, 0, 0
+	}
+
+	// Remaining fields: state ppid pgrp session tty_nr ...
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 5 {
+		return 0, 0, 0
+	}
+
+	pgrp, _ := strconv.Atoi(fields[2])
+	session, _ := strconv.Atoi(fields[3])
+	tty, _ := strconv.ParseUint(fields[4], 10, 64)
+	return int32(pgrp), int32(session), tty
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package services
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// sendUser1 is not available without Unix signals
+func sendUser1(proc *process.Process) error {
+	return fmt.Errorf("SIGUSR1 is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package services
+
+import (
+	"syscall"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// sendUser1 sends SIGUSR1 to proc
+func sendUser1(proc *process.Process) error {
+	return proc.SendSignal(syscall.SIGUSR1)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// SystemService handles host-level information
+type SystemService struct{}
+
+// NewSystemService creates a new system service
+func NewSystemService() *SystemService {
+	return &SystemService{}
+}
+
+// GetSystemInfo retrieves host, kernel and CPU information
+func (ss *SystemService) GetSystemInfo() (*models.SystemInfo, error) {
+	hostInfo, err := host.Info()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host info: %w", err)
+	}
+
+	info := &models.SystemInfo{
+		Hostname:        hostInfo.Hostname,
+		OS:              hostInfo.OS,
+		Platform:        hostInfo.Platform,
+		PlatformVersion: hostInfo.PlatformVersion,
+		KernelVersion:   hostInfo.KernelVersion,
+		KernelArch:      hostInfo.KernelArch,
+		BootTime:        time.Unix(int64(hostInfo.BootTime), 0),
+		CPUCores:        runtime.NumCPU(),
+	}
+
+	// CPU model is best-effort; some platforms don't expose it
+	if cpuInfos, err := cpu.Info(); err == nil && len(cpuInfos) > 0 {
+		info.CPUModel = cpuInfos[0].ModelName
+	}
+
+	if cores, err := cpu.Counts(true); err == nil && cores > 0 {
+		info.CPUCores = cores
+	}
+
+	return info, nil
+}
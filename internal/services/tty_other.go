@@ -0,0 +1,8 @@
+//go:build !unix
+
+package services
+
+// terminalNames is not available without Unix terminal devices
+func terminalNames() map[uint64]string {
+	return nil
+}
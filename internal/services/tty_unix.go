@@ -0,0 +1,36 @@
+//go:build unix
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// terminalNames maps the device numbers of terminals to their names below
+// /dev, e.g. "/pts/0". It lists /dev once, so it is built once per scan
+// rather than for every process. Terminals are only looked up on Linux.
+func terminalNames() map[uint64]string {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+
+	paths, _ := filepath.Glob("/dev/tty*")
+	pts, _ := filepath.Glob("/dev/pts/[0-9]*")
+	paths = append(paths, pts...)
+
+	names := make(map[uint64]string, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			continue
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			names[uint64(stat.Rdev)] = strings.TrimPrefix(path, "/dev")
+		}
+	}
+	return names
+}
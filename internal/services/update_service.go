@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// releasesURL is the GitHub API endpoint for the latest published release
+const releasesURL = "https://api.github.com/repos/charithmadhuranga/taskmanager/releases/latest"
+
+// updateCheckTimeout bounds how long an update check may take
+const updateCheckTimeout = 10 * time.Second
+
+// UpdateService checks GitHub releases for newer versions
+type UpdateService struct {
+	client *http.Client
+	url    string
+}
+
+// NewUpdateService creates a new update service
+func NewUpdateService() *UpdateService {
+	return &UpdateService{
+		client: &http.Client{Timeout: updateCheckTimeout},
+		url:    releasesURL,
+	}
+}
+
+// CheckForUpdate fetches the latest release and compares it with current.
+// Development builds are never reported as outdated.
+func (us *UpdateService) CheckForUpdate(ctx context.Context, current string) (*models.ReleaseInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, us.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create update request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := us.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+
+	var release struct {
+		TagName     string    `json:"tag_name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	info := &models.ReleaseInfo{
+		Version:     release.TagName,
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+	}
+	if cmp, ok := compareVersions(release.TagName, current); ok {
+		info.UpdateAvailable = cmp > 0
+	}
+	return info, nil
+}
+
+// compareVersions compares two dotted versions such as v1.2.3, ignoring any
+// pre-release suffix. ok is false when either is not a version number.
+func compareVersions(a, b string) (cmp int, ok bool) {
+	partsA, okA := parseVersion(a)
+	partsB, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x = partsA[i]
+		}
+		if i < len(partsB) {
+			y = partsB[i]
+		}
+		if x != y {
+			if x > y {
+				return 1, true
+			}
+			return -1, true
+		}
+	}
+	return 0, true
+}
+
+// parseVersion splits a version such as v1.2.3-rc1 into its numeric parts
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+
+	fields := strings.Split(v, ".")
+	parts := make([]int, len(fields))
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// virtualizers maps the executable names of compatibility layers and
+// virtual machine monitors, lower-cased and without .exe, to what the
+// processes they run are shown as running under
+var virtualizers = map[string]string{
+	"wine":             "Wine",
+	"wine64":           "Wine",
+	"wine-preloader":   "Wine",
+	"wine64-preloader": "Wine",
+	"wineserver":       "Wine",
+	"wsl":              "WSL",
+	"wslhost":          "WSL",
+	"wslservice":       "WSL",
+	"wslrelay":         "WSL",
+	"vmmemwsl":         "WSL",
+	"vmmem":            "Hyper-V",
+	"vmwp":             "Hyper-V",
+	"qemu-kvm":         "QEMU",
+	"virtualboxvm":     "VirtualBox",
+	"vboxheadless":     "VirtualBox",
+	"vboxsdl":          "VirtualBox",
+	"vmware-vmx":       "VMware",
+	"firecracker":      "Firecracker",
+	"cloud-hypervisor": "Cloud Hypervisor",
+	"crosvm":           "crosvm",
+}
+
+// detectVirtualization works out what proc itself runs under from its
+// executable and name. Wine runs Windows programs through its preloader, so
+// most show by executable; a .exe elsewhere than on Windows runs under Wine,
+// or under WSL's interop when started from a Windows drive mounted in WSL.
+func detectVirtualization(proc *models.ProcessInfo) string {
+	for _, path := range []string{proc.Executable, proc.Name} {
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), ".exe"))
+		if name == "." {
+			continue
+		}
+		if kind, ok := virtualizers[name]; ok {
+			return kind
+		}
+		if strings.HasPrefix(name, "qemu-system-") {
+			return "QEMU"
+		}
+	}
+
+	if runtime.GOOS != "windows" && strings.HasSuffix(strings.ToLower(proc.Name), ".exe") {
+		if strings.HasPrefix(proc.Executable, "/mnt/") {
+			return "WSL"
+		}
+		return "Wine"
+	}
+	return ""
+}
+
+// markVirtualized sets the Virtualization of the processes, which they
+// inherit from the nearest ancestor running under one, so the helpers a
+// hypervisor or Wine starts are marked with it
+func markVirtualized(processes []*models.ProcessInfo) {
+	byPID := make(map[int32]*models.ProcessInfo, len(processes))
+	for _, proc := range processes {
+		byPID[proc.PID] = proc
+	}
+
+	known := make(map[int32]bool, len(processes))
+	// mark sets and returns the Virtualization of proc, marking its
+	// ancestors first; known is set before recursing, which ends cycles
+	var mark func(proc *models.ProcessInfo) string
+	mark = func(proc *models.ProcessInfo) string {
+		if known[proc.PID] {
+			return proc.Virtualization
+		}
+		known[proc.PID] = true
+		proc.Virtualization = detectVirtualization(proc)
+		if parent, ok := byPID[proc.PPID]; proc.Virtualization == "" && ok {
+			proc.Virtualization = mark(parent)
+		}
+		return proc.Virtualization
+	}
+
+	for _, proc := range processes {
+		mark(proc)
+	}
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Supported compression formats for snapshots and the rotated event history.
+// zstd is not offered, as the standard library has no encoder for it.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// CompressionExt returns the file extension appended for a compression format
+func CompressionExt(compression string) (string, error) {
+	switch compression {
+	case "", CompressionNone:
+		return "", nil
+	case CompressionGzip:
+		return ".gz", nil
+	default:
+		return "", fmt.Errorf("unsupported compression: %s", compression)
+	}
+}
+
+// Compress encodes data with the given compression format
+func Compress(data []byte, compression string) ([]byte, error) {
+	if compression != CompressionGzip {
+		if _, err := CompressionExt(compression); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decodes data written by Compress, detecting the format from its
+// header so files stay readable after the configured compression changes
+func Decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported config file formats
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatTOML = "toml"
+)
+
+// configEnvPrefix prefixes environment variables that override config values,
+// e.g. TAPPMANAGER_REFRESH_RATE or TAPPMANAGER_DEFAULT_SORT_FIELD
+const configEnvPrefix = "TAPPMANAGER_"
+
+// configFileNames maps each config file name to its format, in lookup order
+var configFileNames = []struct {
+	name   string
+	format string
+}{
+	{"config.json", ConfigFormatJSON},
+	{"config.yaml", ConfigFormatYAML},
+	{"config.yml", ConfigFormatYAML},
+	{"config.toml", ConfigFormatTOML},
+}
+
+// ValidateConfigFormat reports an error for unsupported config formats
+func ValidateConfigFormat(format string) error {
+	switch format {
+	case ConfigFormatJSON, ConfigFormatYAML, ConfigFormatTOML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported config format: %s (use json, yaml or toml)", format)
+	}
+}
+
+// findConfigFile returns the first existing config file in configDir and its format
+func findConfigFile(configDir string) (string, string, bool) {
+	for _, candidate := range configFileNames {
+		path := filepath.Join(configDir, candidate.name)
+		if _, err := os.Stat(path); err == nil {
+			return path, candidate.format, true
+		}
+	}
+	return "", "", false
+}
+
+// MarshalConfig encodes config in the given format
+func MarshalConfig(config *models.AppConfig, format string) ([]byte, error) {
+	if format == ConfigFormatJSON {
+		return json.MarshalIndent(config, "", "  ")
+	}
+
+	// Go through the JSON form so every format uses the same key names
+	values, err := configValues(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case ConfigFormatYAML:
+		return yaml.Marshal(values)
+	case ConfigFormatTOML:
+		return toml.Marshal(values)
+	default:
+		return nil, ValidateConfigFormat(format)
+	}
+}
+
+// decodeConfigValues decodes a config file in the given format without applying defaults
+func decodeConfigValues(data []byte, format string) (map[string]any, error) {
+	var err error
+	fileValues := make(map[string]any)
+	switch format {
+	case ConfigFormatJSON:
+		err = json.Unmarshal(data, &fileValues)
+	case ConfigFormatYAML:
+		err = yaml.Unmarshal(data, &fileValues)
+	case ConfigFormatTOML:
+		err = toml.Unmarshal(data, &fileValues)
+	default:
+		err = ValidateConfigFormat(format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return fileValues, nil
+}
+
+// unmarshalConfig decodes data in the given format, migrates it to the current
+// schema and merges it over the defaults. With withEnv set, environment
+// variable overrides are applied last.
+func unmarshalConfig(data []byte, format string, withEnv bool) (*models.AppConfig, error) {
+	fileValues, err := decodeConfigValues(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateConfigValues(fileValues); err != nil {
+		return nil, err
+	}
+
+	values, err := configValues(models.NewAppConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	mergeConfigValues(values, fileValues)
+	if withEnv {
+		if err := applyConfigEnv(values, configEnvPrefix); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonData, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var config models.AppConfig
+	if err := json.Unmarshal(jsonData, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &config, nil
+}
+
+// configValues converts config into a map keyed by its JSON field names
+func configValues(config *models.AppConfig) (map[string]any, error) {
+	jsonData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	values := make(map[string]any)
+	if err := json.Unmarshal(jsonData, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	restoreIntegers(values)
+	return values, nil
+}
+
+// restoreIntegers turns whole numbers, which JSON decodes as float64, back into
+// integers so YAML and TOML files do not show them as 2.0
+func restoreIntegers(values map[string]any) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]any:
+			restoreIntegers(v)
+		case float64:
+			if v == math.Trunc(v) {
+				values[key] = int64(v)
+			}
+		}
+	}
+}
+
+// mergeConfigValues copies src into dst, merging nested sections key by key
+func mergeConfigValues(dst, src map[string]any) {
+	for key, value := range src {
+		nested, ok := value.(map[string]any)
+		if existing, isMap := dst[key].(map[string]any); ok && isMap {
+			mergeConfigValues(existing, nested)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// applyConfigEnv overrides values from environment variables named after
+// their key path, parsing each override as the type of the value it replaces
+func applyConfigEnv(values map[string]any, prefix string) error {
+	for key, value := range values {
+		name := prefix + strings.ToUpper(key)
+		if nested, ok := value.(map[string]any); ok {
+			if err := applyConfigEnv(nested, name+"_"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch value.(type) {
+		case []any:
+			// Lists can only be set in the config file
+			continue
+		case bool:
+			values[key], err = strconv.ParseBool(env)
+		case int, int64, float64:
+			values[key], err = strconv.ParseFloat(env, 64)
+		default:
+			values[key] = env
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
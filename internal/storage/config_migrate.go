@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+
+	"tappmanager/internal/models"
+)
+
+// configMigration upgrades decoded config file values from the previous
+// schema version to version
+type configMigration struct {
+	version     int
+	description string
+	migrate     func(values map[string]any)
+}
+
+// configMigrations lists every schema upgrade in order. Config files written
+// before schema versioning have no schema_version and are version 1.
+var configMigrations = []configMigration{
+	{
+		version:     2,
+		description: "processes view refresh defaults to refresh_rate",
+		migrate: func(values map[string]any) {
+			// The processes view refreshed at refresh_rate before it had its own interval
+			copyConfigValue(values, "refresh_rate", "processes_refresh")
+		},
+	},
+}
+
+// configSchemaVersion returns the schema version of decoded config file values
+func configSchemaVersion(values map[string]any) int {
+	switch v := values["schema_version"].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 1
+	}
+}
+
+// migrateConfigValues upgrades decoded config file values to the current
+// schema version in place
+func migrateConfigValues(values map[string]any) error {
+	version := configSchemaVersion(values)
+	if version > models.ConfigSchemaVersion {
+		return fmt.Errorf("config schema version %d is newer than supported version %d", version, models.ConfigSchemaVersion)
+	}
+
+	for _, migration := range configMigrations {
+		if migration.version > version {
+			migration.migrate(values)
+			version = migration.version
+		}
+	}
+
+	values["schema_version"] = version
+	return nil
+}
+
+// backupConfigFile keeps a copy of a config file before it is migrated from
+// schema version, so settings are never lost to a faulty migration
+func backupConfigFile(configFile string, data []byte, version int) (string, error) {
+	backupFile := fmt.Sprintf("%s.v%d.bak", configFile, version)
+	if err := writePrivateFile(backupFile, data); err != nil {
+		return "", fmt.Errorf("failed to back up config before migration: %w", err)
+	}
+	return backupFile, nil
+}
+
+// copyConfigValue sets an unset key to the value of another key
+func copyConfigValue(values map[string]any, fromKey, toKey string) {
+	value, ok := values[fromKey]
+	if !ok {
+		return
+	}
+	if _, exists := values[toKey]; !exists {
+		values[toKey] = value
+	}
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tappmanager/internal/models"
+)
+
+func TestMigrateConfigValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]any
+		refresh any // processes_refresh after migrating, nil if unset
+		wantErr bool
+	}{
+		{
+			name:    "unversioned file takes refresh rate",
+			values:  map[string]any{"refresh_rate": float64(5)},
+			refresh: float64(5),
+		},
+		{
+			name:    "unversioned file keeps its processes refresh",
+			values:  map[string]any{"refresh_rate": float64(5), "processes_refresh": float64(1)},
+			refresh: float64(1),
+		},
+		{
+			name:   "unversioned file without refresh rate",
+			values: map[string]any{},
+		},
+		{
+			name:   "current version is left alone",
+			values: map[string]any{"schema_version": float64(2), "refresh_rate": float64(5)},
+		},
+		{
+			name:    "newer version is refused",
+			values:  map[string]any{"schema_version": float64(models.ConfigSchemaVersion + 1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrateConfigValues(tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := configSchemaVersion(tt.values); got != models.ConfigSchemaVersion {
+				t.Errorf("schema version %d, want %d", got, models.ConfigSchemaVersion)
+			}
+			if got := tt.values["processes_refresh"]; got != tt.refresh {
+				t.Errorf("processes_refresh = %v, want %v", got, tt.refresh)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMigratesWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	original := []byte(`{"refresh_rate": 7, "theme": "dark"}`)
+	if err := os.WriteFile(configFile, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := NewJSONStorage(dir, dir).LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ProcessesRefresh != 7 || config.RefreshRate != 7 {
+		t.Errorf("refresh %d, processes refresh %d, want both 7", config.RefreshRate, config.ProcessesRefresh)
+	}
+
+	// The original is kept next to the config, named after its version
+	backupFile := configFile + ".v1.bak"
+	backup, err := os.ReadFile(backupFile)
+	if err != nil {
+		t.Fatalf("no backup: %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Errorf("backup holds %s, want %s", backup, original)
+	}
+
+	// The config itself is rewritten in the current schema
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rewritten map[string]any
+	if err := json.Unmarshal(data, &rewritten); err != nil {
+		t.Fatal(err)
+	}
+	if got := configSchemaVersion(rewritten); got != models.ConfigSchemaVersion {
+		t.Errorf("rewritten schema version %d, want %d", got, models.ConfigSchemaVersion)
+	}
+	if rewritten["processes_refresh"] != float64(7) || rewritten["theme"] != "dark" {
+		t.Errorf("rewritten config lost values: %s", data)
+	}
+
+	// A migrated config is not backed up again
+	if err := os.Remove(backupFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJSONStorage(dir, dir).LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(backupFile); !os.IsNotExist(err) {
+		t.Errorf("current config was backed up again")
+	}
+}
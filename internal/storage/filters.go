@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// savedFiltersFile is the file in the config directory saved filters are kept in
+const savedFiltersFile = "filters.json"
+
+// LoadSavedFilters returns the saved filters in the order they were saved
+func (s *JSONStorage) LoadSavedFilters() ([]models.SavedFilter, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.configDir, savedFiltersFile))
+	if os.IsNotExist(err) {
+		return []models.SavedFilter{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved filters: %w", err)
+	}
+
+	var filters []models.SavedFilter
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved filters: %w", err)
+	}
+	return filters, nil
+}
+
+// SaveSavedFilters replaces the saved filters
+func (s *JSONStorage) SaveSavedFilters(filters []models.SavedFilter) (err error) {
+	defer s.recordWrite(time.Now(), &err)
+
+	if err := s.ensureDirectories(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(filters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved filters: %w", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.configDir, savedFiltersFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write saved filters: %w", err)
+	}
+	return nil
+}
@@ -6,11 +6,17 @@ import "tappmanager/internal/models"
 type Storage interface {
 	// Configuration operations
 	LoadConfig() (*models.AppConfig, error)
+	LoadFileConfig() (*models.AppConfig, error)
 	SaveConfig(config *models.AppConfig) error
+	ConfigFile() (path string, format string)
 	
 	// Process data operations
 	SaveProcessSnapshot(processes []*models.ProcessInfo) error
 	LoadProcessSnapshot() ([]*models.ProcessInfo, error)
+
+	// Saved filter operations
+	LoadSavedFilters() ([]models.SavedFilter, error)
+	SaveSavedFilters(filters []models.SavedFilter) error
 	
 	// Backup operations
 	CreateBackup() error
@@ -19,5 +25,16 @@ type Storage interface {
 	
 	// Export operations
 	ExportProcesses(format string) (string, error) // json, csv, xml
+	ExportView(name, text, styled string) (textPath, styledPath string, err error)
 	ImportProcesses(data string, format string) error
+
+	// State operations
+	ExportState(path string) (string, error)
+	ImportState(path string) error
+
+	// Retention operations
+	Prune(dryRun bool) (*models.PruneReport, error)
+
+	// Diagnostics operations
+	GetWriteStats() models.StorageStats
 }
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"tappmanager/internal/models"
@@ -16,16 +18,28 @@ import (
 
 // JSONStorage implements Storage interface using JSON files
 type JSONStorage struct {
+	configDir  string
 	dataDir    string
 	backupDir  string
 	config     *models.AppConfig
+
+	// configFormat is the format new config files are written in; empty keeps
+	// the format of the existing file
+	configFormat string
+
 	processes  []*models.ProcessInfo
+
+	// writeStats tracks latency of config and snapshot writes
+	writeMu    sync.Mutex
+	writeStats models.StorageStats
 }
 
-// NewJSONStorage creates a new JSON storage instance
-func NewJSONStorage(dataDir string) *JSONStorage {
+// NewJSONStorage creates a new JSON storage instance keeping the config file
+// and other settings in configDir and everything else in dataDir
+func NewJSONStorage(configDir, dataDir string) *JSONStorage {
 	backupDir := filepath.Join(dataDir, "backups")
 	return &JSONStorage{
+		configDir: configDir,
 		dataDir:   dataDir,
 		backupDir: backupDir,
 		config:    models.NewAppConfig(),
@@ -35,6 +49,9 @@ func NewJSONStorage(dataDir string) *JSONStorage {
 
 // ensureDirectories creates necessary directories if they don't exist
 func (s *JSONStorage) ensureDirectories() error {
+	if err := os.MkdirAll(s.configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
 	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
@@ -44,16 +61,48 @@ func (s *JSONStorage) ensureDirectories() error {
 	return nil
 }
 
-// LoadConfig loads the application configuration
+// SetConfigFormat selects the format config files are written in. An existing
+// config in another format is converted on the next LoadConfig.
+func (s *JSONStorage) SetConfigFormat(format string) error {
+	if err := ValidateConfigFormat(format); err != nil {
+		return err
+	}
+	s.configFormat = format
+	return nil
+}
+
+// ConfigFile returns the path and format of the config file, whether or not it exists yet
+func (s *JSONStorage) ConfigFile() (string, string) {
+	if path, format, ok := findConfigFile(s.configDir); ok && (s.configFormat == "" || s.configFormat == format) {
+		return path, format
+	}
+
+	format := s.configFormat
+	if format == "" {
+		format = ConfigFormatJSON
+	}
+	return filepath.Join(s.configDir, "config."+format), format
+}
+
+// LoadConfig loads the application configuration. Values come from the
+// defaults, then the config file, then TAPPMANAGER_* environment variables
+// (which command line flags are passed on as). A config that is changed and
+// saved should come from LoadFileConfig instead, so the overrides are not
+// written to the file.
 func (s *JSONStorage) LoadConfig() (*models.AppConfig, error) {
 	if err := s.ensureDirectories(); err != nil {
 		return nil, err
 	}
 
-	configFile := filepath.Join(s.dataDir, "config.json")
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		// Create default config if file doesn't exist
-		s.config = models.NewAppConfig()
+	configFile, format, ok := findConfigFile(s.configDir)
+	if !ok {
+		// Use defaults if no config file exists yet
+		config, err := unmarshalConfig([]byte("{}"), ConfigFormatJSON, true)
+		if err != nil {
+			return nil, err
+		}
+		config.DataDir = s.dataDir
+		s.config = config
 		return s.config, nil
 	}
 
@@ -62,72 +111,218 @@ func (s *JSONStorage) LoadConfig() (*models.AppConfig, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	var config models.AppConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	fileValues, err := decodeConfigValues(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older files are migrated to the current schema and rewritten, keeping a
+	// copy of the original
+	schemaVersion := configSchemaVersion(fileValues)
+	migrate := schemaVersion < models.ConfigSchemaVersion
+	if migrate {
+		if _, err := backupConfigFile(configFile, data, schemaVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if migrate || (s.configFormat != "" && s.configFormat != format) {
+		if err := s.convertConfig(configFile, data, format); err != nil {
+			return nil, err
+		}
 	}
 
-	s.config = &config
+	config, err := unmarshalConfig(data, format, true)
+	if err != nil {
+		return nil, err
+	}
+
+	config.DataDir = s.dataDir
+	s.config = config
 	return s.config, nil
 }
 
+// LoadFileConfig loads the configuration as stored, from the defaults and
+// the config file only, leaving out environment and flag overrides
+func (s *JSONStorage) LoadFileConfig() (*models.AppConfig, error) {
+	configFile, format, ok := findConfigFile(s.configDir)
+	if !ok {
+		return unmarshalConfig([]byte("{}"), ConfigFormatJSON, false)
+	}
+
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return unmarshalConfig(data, format, false)
+}
+
+// convertConfig rewrites the config file found at configFile in the current
+// schema and the selected format. Environment overrides are left out so they
+// are not persisted.
+func (s *JSONStorage) convertConfig(configFile string, data []byte, format string) error {
+	config, err := unmarshalConfig(data, format, false)
+	if err != nil {
+		return err
+	}
+
+	if err := s.writeConfig(config); err != nil {
+		return err
+	}
+
+	if newFile, _ := s.ConfigFile(); newFile == configFile {
+		return nil
+	}
+	if err := os.Remove(configFile); err != nil {
+		return fmt.Errorf("failed to remove old config: %w", err)
+	}
+	return nil
+}
+
 // SaveConfig saves the application configuration
-func (s *JSONStorage) SaveConfig(config *models.AppConfig) error {
+func (s *JSONStorage) SaveConfig(config *models.AppConfig) (err error) {
+	defer s.recordWrite(time.Now(), &err)
+
 	if err := s.ensureDirectories(); err != nil {
 		return err
 	}
 
+	// A config struct always holds the current schema
+	config.SchemaVersion = models.ConfigSchemaVersion
 	config.UpdatedAt = time.Now()
-	configFile := filepath.Join(s.dataDir, "config.json")
-	jsonData, err := json.MarshalIndent(config, "", "  ")
+	if err := s.writeConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config
+	return nil
+}
+
+// writeConfig encodes config to the config file in its current format
+func (s *JSONStorage) writeConfig(config *models.AppConfig) error {
+	configFile, format := s.ConfigFile()
+	data, err := MarshalConfig(config, format)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := ioutil.WriteFile(configFile, jsonData, 0644); err != nil {
+	if err := writePrivateFile(configFile, data); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
-
-	s.config = config
 	return nil
 }
 
+// writePrivateFile writes data to path readable by its owner only. The
+// config holds credentials such as the MQTT password and Grafana token, so
+// it and every copy of it are written this way. A file written before with
+// looser permissions is restricted before the data goes in.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
 // SaveProcessSnapshot saves a snapshot of current processes
-func (s *JSONStorage) SaveProcessSnapshot(processes []*models.ProcessInfo) error {
+func (s *JSONStorage) SaveProcessSnapshot(processes []*models.ProcessInfo) (err error) {
+	defer s.recordWrite(time.Now(), &err)
+
 	if err := s.ensureDirectories(); err != nil {
 		return err
 	}
 
-	snapshotFile := filepath.Join(s.dataDir, "process_snapshot.json")
+	ext, err := CompressionExt(s.config.SnapshotCompression)
+	if err != nil {
+		return err
+	}
+
 	jsonData, err := json.MarshalIndent(processes, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal process snapshot: %w", err)
 	}
 
-	if err := ioutil.WriteFile(snapshotFile, jsonData, 0644); err != nil {
+	fileData, err := Compress(jsonData, s.config.SnapshotCompression)
+	if err != nil {
+		return err
+	}
+
+	snapshotFile := filepath.Join(s.dataDir, "process_snapshot.json")
+	if err := ioutil.WriteFile(snapshotFile+ext, fileData, 0644); err != nil {
 		return fmt.Errorf("failed to write process snapshot: %w", err)
 	}
 
+	// Remove the snapshot left behind by a previous compression setting
+	for _, stale := range snapshotFiles(snapshotFile) {
+		if stale != snapshotFile+ext {
+			if err := os.Remove(stale); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove stale process snapshot: %w", err)
+			}
+		}
+	}
+
 	s.processes = processes
 	return nil
 }
 
+// snapshotFiles returns every path a snapshot may be stored at, one per compression format
+func snapshotFiles(snapshotFile string) []string {
+	return []string{snapshotFile, snapshotFile + ".gz"}
+}
+
+// recordWrite records the latency and outcome of a write that started at start
+func (s *JSONStorage) recordWrite(start time.Time, err *error) {
+	latency := time.Since(start)
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.writeStats.Writes++
+	if *err != nil {
+		s.writeStats.Errors++
+	}
+	s.writeStats.LastWriteAt = time.Now()
+	s.writeStats.LastWriteLatency = latency
+	if latency > s.writeStats.MaxWriteLatency {
+		s.writeStats.MaxWriteLatency = latency
+	}
+}
+
+// GetWriteStats returns the latency and outcome counts of storage writes
+func (s *JSONStorage) GetWriteStats() models.StorageStats {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.writeStats
+}
+
 // LoadProcessSnapshot loads the last saved process snapshot
 func (s *JSONStorage) LoadProcessSnapshot() ([]*models.ProcessInfo, error) {
 	if err := s.ensureDirectories(); err != nil {
 		return nil, err
 	}
 
-	snapshotFile := filepath.Join(s.dataDir, "process_snapshot.json")
-	if _, err := os.Stat(snapshotFile); os.IsNotExist(err) {
+	// Load whichever snapshot was written most recently, compressed or not
+	var snapshotFile string
+	var modTime time.Time
+	for _, candidate := range snapshotFiles(filepath.Join(s.dataDir, "process_snapshot.json")) {
+		if info, err := os.Stat(candidate); err == nil && info.ModTime().After(modTime) {
+			snapshotFile, modTime = candidate, info.ModTime()
+		}
+	}
+	if snapshotFile == "" {
 		return []*models.ProcessInfo{}, nil
 	}
 
-	data, err := ioutil.ReadFile(snapshotFile)
+	fileData, err := ioutil.ReadFile(snapshotFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read process snapshot: %w", err)
 	}
 
+	data, err := Decompress(fileData)
+	if err != nil {
+		return nil, err
+	}
+
 	var processes []*models.ProcessInfo
 	if err := json.Unmarshal(data, &processes); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal process snapshot: %w", err)
@@ -156,7 +351,7 @@ func (s *JSONStorage) CreateBackup() error {
 		return fmt.Errorf("failed to marshal backup data: %w", err)
 	}
 
-	if err := ioutil.WriteFile(backupFile, jsonData, 0644); err != nil {
+	if err := writePrivateFile(backupFile, jsonData); err != nil {
 		return fmt.Errorf("failed to write backup file: %w", err)
 	}
 
@@ -165,11 +360,16 @@ func (s *JSONStorage) CreateBackup() error {
 
 // RestoreBackup restores data from a backup file
 func (s *JSONStorage) RestoreBackup(backupPath string) error {
-	data, err := ioutil.ReadFile(backupPath)
+	fileData, err := ioutil.ReadFile(backupPath)
 	if err != nil {
 		return fmt.Errorf("failed to read backup file: %w", err)
 	}
 
+	data, err := Decompress(fileData)
+	if err != nil {
+		return err
+	}
+
 	var backupData map[string]interface{}
 	if err := json.Unmarshal(data, &backupData); err != nil {
 		return fmt.Errorf("failed to unmarshal backup data: %w", err)
@@ -231,39 +431,59 @@ func (s *JSONStorage) ExportProcesses(format string) (string, error) {
 		return "", err
 	}
 
+	data, err := EncodeProcesses(s.processes, format)
+	if err != nil {
+		return "", err
+	}
+
 	timestamp := time.Now().Format("20060102_150405")
-	
+	filename := filepath.Join(s.dataDir, fmt.Sprintf("processes_export_%s.%s", timestamp, format))
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write export file: %w", err)
+	}
+	return filename, nil
+}
+
+// ExportView writes a capture of the view called name to the data
+// directory twice: as plain text and, with its ANSI styling, as .ans
+func (s *JSONStorage) ExportView(name, text, styled string) (string, string, error) {
+	if err := s.ensureDirectories(); err != nil {
+		return "", "", err
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	base := filepath.Join(s.dataDir, fmt.Sprintf("view_capture_%s_%s", name, timestamp))
+	if err := ioutil.WriteFile(base+".txt", []byte(text), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write view capture: %w", err)
+	}
+	if err := ioutil.WriteFile(base+".ans", []byte(styled), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write view capture: %w", err)
+	}
+	return base + ".txt", base + ".ans", nil
+}
+
+// EncodeProcesses encodes processes as an export in format, json or csv
+func EncodeProcesses(processes []*models.ProcessInfo, format string) ([]byte, error) {
 	switch format {
 	case "json":
-		filename := filepath.Join(s.dataDir, fmt.Sprintf("processes_export_%s.json", timestamp))
-		jsonData, err := json.MarshalIndent(s.processes, "", "  ")
+		jsonData, err := json.MarshalIndent(processes, "", "  ")
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal processes for export: %w", err)
-		}
-		if err := ioutil.WriteFile(filename, jsonData, 0644); err != nil {
-			return "", fmt.Errorf("failed to write export file: %w", err)
+			return nil, fmt.Errorf("failed to marshal processes for export: %w", err)
 		}
-		return filename, nil
-		
-	case "csv":
-		filename := filepath.Join(s.dataDir, fmt.Sprintf("processes_export_%s.csv", timestamp))
-		file, err := os.Create(filename)
-		if err != nil {
-			return "", fmt.Errorf("failed to create CSV file: %w", err)
-		}
-		defer file.Close()
+		return jsonData, nil
 
-		writer := csv.NewWriter(file)
-		defer writer.Flush()
+	case "csv":
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
 
 		// Write header
 		header := []string{"PID", "PPID", "Name", "Status", "CPU%", "Memory%", "MemoryBytes", "Username", "Command", "WorkingDir", "NumThreads", "Nice", "CreateTime"}
 		if err := writer.Write(header); err != nil {
-			return "", fmt.Errorf("failed to write CSV header: %w", err)
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
 		}
 
 		// Write data
-		for _, proc := range s.processes {
+		for _, proc := range processes {
 			record := []string{
 				strconv.Itoa(int(proc.PID)),
 				strconv.Itoa(int(proc.PPID)),
@@ -280,13 +500,18 @@ func (s *JSONStorage) ExportProcesses(format string) (string, error) {
 				proc.CreateTime.Format(time.RFC3339),
 			}
 			if err := writer.Write(record); err != nil {
-				return "", fmt.Errorf("failed to write CSV record: %w", err)
+				return nil, fmt.Errorf("failed to write CSV record: %w", err)
 			}
 		}
-		return filename, nil
-		
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write CSV record: %w", err)
+		}
+		return buf.Bytes(), nil
+
 	default:
-		return "", fmt.Errorf("unsupported export format: %s", format)
+		return nil, fmt.Errorf("unsupported export format: %s", format)
 	}
 }
 
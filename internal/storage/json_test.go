@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestConfigCopiesArePrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not Unix modes on Windows")
+	}
+	dir := t.TempDir()
+	s := NewJSONStorage(dir, dir)
+
+	// A config written by an earlier release is readable by everyone
+	configFile, _ := s.ConfigFile()
+	if err := os.WriteFile(configFile, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.MQTT.Password = "secret"
+	if err := s.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateBackup(); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := s.ExportState("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, err := filepath.Glob(filepath.Join(s.backupDir, "backup_*.json"))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups %v, %v", backups, err)
+	}
+
+	for _, path := range []string{configFile, backups[0], archive} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s has permissions %o, want 600", filepath.Base(path), perm)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// PruneInterval is how often stored files are checked against their retention policies
+const PruneInterval = time.Hour
+
+// storedFileKind groups stored files that share a retention policy
+type storedFileKind struct {
+	name    string
+	pattern string
+	policy  models.RetentionPolicy
+}
+
+// Prune deletes exports, view captures, snapshots, backups and rotated event
+// histories that fall outside their retention policies. With dryRun set nothing is deleted and the report lists
+// what would have been.
+func (s *JSONStorage) Prune(dryRun bool) (*models.PruneReport, error) {
+	kinds := []storedFileKind{
+		{name: "export", pattern: filepath.Join(s.dataDir, "processes_export_*"), policy: s.config.ExportRetention},
+		{name: "capture", pattern: filepath.Join(s.dataDir, "view_capture_*"), policy: s.config.ExportRetention},
+		{name: "snapshot", pattern: filepath.Join(s.dataDir, "process_snapshot.json*"), policy: s.config.SnapshotRetention},
+		{name: "backup", pattern: filepath.Join(s.backupDir, "backup_*"), policy: s.config.BackupRetention},
+		// Only the rotated files; events.jsonl itself is still written to
+		{name: "event history", pattern: filepath.Join(s.dataDir, "events.jsonl.*"), policy: s.config.EventRetention},
+	}
+
+	report := &models.PruneReport{DryRun: dryRun, RunAt: time.Now()}
+	for _, kind := range kinds {
+		files, err := expiredFiles(kind, report.RunAt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if !dryRun {
+				if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+					return nil, fmt.Errorf("failed to prune %s: %w", file.Path, err)
+				}
+			}
+			report.Files = append(report.Files, file)
+			report.FreedBytes += file.Size
+		}
+	}
+
+	return report, nil
+}
+
+// expiredFiles returns the files of kind that are older than its maximum age
+// or, newest first, push its total size past the maximum
+func expiredFiles(kind storedFileKind, now time.Time) ([]models.PrunedFile, error) {
+	if kind.policy.MaxAge <= 0 && kind.policy.MaxSize <= 0 {
+		return nil, nil
+	}
+
+	paths, err := filepath.Glob(kind.pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s files: %w", kind.name, err)
+	}
+
+	var files []models.PrunedFile
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, models.PrunedFile{Path: path, Kind: kind.name, Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	// Newest files are kept first when trimming to the size limit
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime.After(files[j].ModTime)
+	})
+
+	maxAge := time.Duration(kind.policy.MaxAge) * 24 * time.Hour
+	maxSize := int64(kind.policy.MaxSize) << 20
+
+	var expired []models.PrunedFile
+	var kept int64
+	for _, file := range files {
+		switch {
+		case maxAge > 0 && now.Sub(file.ModTime) > maxAge:
+			file.Reason = fmt.Sprintf("older than %d days", kind.policy.MaxAge)
+		case maxSize > 0 && kept+file.Size > maxSize:
+			file.Reason = fmt.Sprintf("over %d MiB of %s files", kind.policy.MaxSize, kind.name)
+		default:
+			kept += file.Size
+			continue
+		}
+		expired = append(expired, file)
+	}
+
+	return expired, nil
+}
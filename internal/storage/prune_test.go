@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// writeStoredFile writes size bytes to name in dir, last modified age ago
+func writeStoredFile(t *testing.T, dir, name string, size int, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneSelection(t *testing.T) {
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		config func(*models.AppConfig)
+		pruned []string
+	}{
+		{
+			name:   "defaults keep everything",
+			config: func(*models.AppConfig) {},
+			pruned: nil,
+		},
+		{
+			name: "max age",
+			config: func(c *models.AppConfig) {
+				c.ExportRetention = models.RetentionPolicy{MaxAge: 7}
+			},
+			pruned: []string{"processes_export_old.csv", "view_capture_old.txt"},
+		},
+		{
+			name: "max size keeps the newest",
+			config: func(c *models.AppConfig) {
+				c.BackupRetention = models.RetentionPolicy{MaxSize: 1}
+			},
+			pruned: []string{"backup_oldest.json"},
+		},
+		{
+			name: "each kind by its own policy",
+			config: func(c *models.AppConfig) {
+				c.SnapshotRetention = models.RetentionPolicy{MaxAge: 1}
+				c.BackupRetention = models.RetentionPolicy{MaxAge: 3}
+			},
+			pruned: []string{"process_snapshot.json.gz", "backup_oldest.json"},
+		},
+		{
+			name: "rotated event history",
+			config: func(c *models.AppConfig) {
+				c.EventRetention = models.RetentionPolicy{MaxAge: 1}
+			},
+			pruned: []string{"events.jsonl.1", "events.jsonl.1.gz"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := NewJSONStorage(dir, dir)
+			if err := s.ensureDirectories(); err != nil {
+				t.Fatal(err)
+			}
+			tt.config(s.config)
+
+			writeStoredFile(t, dir, "processes_export_new.csv", 10, day)
+			writeStoredFile(t, dir, "processes_export_old.csv", 10, 10*day)
+			writeStoredFile(t, dir, "view_capture_old.txt", 10, 8*day)
+			writeStoredFile(t, dir, "process_snapshot.json.gz", 10, 2*day)
+			writeStoredFile(t, dir, "config.json", 10, 100*day)
+			writeStoredFile(t, dir, "events.jsonl", 10, 5*day)
+			writeStoredFile(t, dir, "events.jsonl.1", 10, 3*day)
+			writeStoredFile(t, dir, "events.jsonl.1.gz", 10, 6*day)
+			writeStoredFile(t, s.backupDir, "backup_newest.json", 512<<10, day)
+			writeStoredFile(t, s.backupDir, "backup_middle.json", 256<<10, 2*day)
+			writeStoredFile(t, s.backupDir, "backup_oldest.json", 512<<10, 4*day)
+
+			report, err := s.Prune(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, file := range report.Files {
+				names = append(names, filepath.Base(file.Path))
+				if file.Reason == "" {
+					t.Errorf("%s has no reason", file.Path)
+				}
+			}
+			slices.Sort(names)
+			want := slices.Clone(tt.pruned)
+			slices.Sort(want)
+			if !slices.Equal(names, want) {
+				t.Fatalf("dry run selected %v, want %v", names, want)
+			}
+
+			// A dry run deletes nothing, a real run exactly what it reported
+			for _, file := range report.Files {
+				if _, err := os.Stat(file.Path); err != nil {
+					t.Errorf("dry run removed %s", file.Path)
+				}
+			}
+			if _, err := s.Prune(false); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range report.Files {
+				if _, err := os.Stat(file.Path); !os.IsNotExist(err) {
+					t.Errorf("%s was not removed", file.Path)
+				}
+			}
+			for _, name := range []string{"config.json", "events.jsonl"} {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s was removed", name)
+				}
+			}
+		})
+	}
+}
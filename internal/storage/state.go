@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// stateArchiveVersion is bumped whenever the archive layout changes incompatibly
+const stateArchiveVersion = 1
+
+// stateFiles are the config directory files, besides the config, that make up
+// the user's setup. Features that persist their own state add their file here.
+var stateFiles = []string{
+	"shortcuts.json",
+	savedFiltersFile,
+}
+
+// ExportState writes the config and every state file into a single archive.
+// An empty path writes a timestamped archive into the data directory.
+func (s *JSONStorage) ExportState(path string) (string, error) {
+	if err := s.ensureDirectories(); err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path = filepath.Join(s.dataDir, fmt.Sprintf("state_export_%s.json", time.Now().Format("20060102_150405")))
+	}
+
+	// Overrides from the environment and flags apply to this run only, so
+	// they are not carried over to another machine
+	config, err := s.LoadFileConfig()
+	if err != nil {
+		return "", err
+	}
+
+	archive := models.StateArchive{
+		Version:    stateArchiveVersion,
+		ExportedAt: time.Now(),
+		Config:     config,
+		Files:      make(map[string]json.RawMessage),
+	}
+
+	for _, name := range stateFiles {
+		data, err := ioutil.ReadFile(filepath.Join(s.configDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if !json.Valid(data) {
+			return "", fmt.Errorf("failed to export %s: not valid JSON", name)
+		}
+		archive.Files[name] = data
+	}
+
+	jsonData, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state archive: %w", err)
+	}
+
+	if err := writePrivateFile(path, jsonData); err != nil {
+		return "", fmt.Errorf("failed to write state archive: %w", err)
+	}
+
+	return path, nil
+}
+
+// ImportState restores the config and state files from an archive written by
+// ExportState. The local data directory setting is kept.
+func (s *JSONStorage) ImportState(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read state archive: %w", err)
+	}
+
+	var archive models.StateArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return fmt.Errorf("failed to unmarshal state archive: %w", err)
+	}
+	if archive.Version > stateArchiveVersion {
+		return fmt.Errorf("state archive version %d is newer than supported version %d", archive.Version, stateArchiveVersion)
+	}
+	if archive.Config == nil {
+		return fmt.Errorf("state archive has no config")
+	}
+
+	// Archives from older versions hold an older config schema
+	var raw struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal state archive: %w", err)
+	}
+	config, err := unmarshalConfig(raw.Config, ConfigFormatJSON, false)
+	if err != nil {
+		return err
+	}
+
+	// Only restore files we know about, so an archive cannot write elsewhere
+	known := make(map[string]bool, len(stateFiles))
+	for _, name := range stateFiles {
+		known[name] = true
+	}
+	for name := range archive.Files {
+		if !known[name] {
+			return fmt.Errorf("state archive contains unknown file: %s", name)
+		}
+	}
+
+	if err := s.ensureDirectories(); err != nil {
+		return err
+	}
+
+	for name, fileData := range archive.Files {
+		if err := ioutil.WriteFile(filepath.Join(s.configDir, name), fileData, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	config.DataDir = s.config.DataDir
+	return s.SaveConfig(config)
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tappmanager/internal/models"
+)
+
+func TestExportStateLeavesOutOverrides(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"schema_version": 2, "refresh_rate": 5}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TAPPMANAGER_REFRESH_RATE", "1")
+
+	s := NewJSONStorage(dir, dir)
+	config, err := s.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RefreshRate != 1 {
+		t.Fatalf("refresh rate %d, want the override", config.RefreshRate)
+	}
+
+	path, err := s.ExportState(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive models.StateArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatal(err)
+	}
+	if archive.Config.RefreshRate != 5 {
+		t.Errorf("archived refresh rate %d, want 5 from the config file", archive.Config.RefreshRate)
+	}
+}
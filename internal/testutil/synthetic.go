@@ -0,0 +1,58 @@
+// Package testutil provides reproducible synthetic workloads for benchmarks.
+package testutil
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"tappmanager/internal/models"
+)
+
+// syntheticSeed keeps generated workloads identical between runs
+const syntheticSeed = 4222
+
+var (
+	syntheticNames    = []string{"bash", "sshd", "postgres", "nginx", "python3", "java", "node", "chrome", "systemd", "containerd", "kworker/0:1", "rsyslogd"}
+	syntheticUsers    = []string{"root", "www-data", "postgres", "alice", "bob", "nobody"}
+	syntheticStatuses = []string{"running", "sleep", "sleep", "sleep", "idle", "blocked", "stop", "zombie"}
+	syntheticDirs     = []string{"/", "/root", "/home/alice/src/app", "/home/bob", "/var/lib/postgresql", "/srv/www", "/tmp"}
+)
+
+// SyntheticProcesses returns n fake processes with realistic field distributions.
+// The same n always produces the same processes.
+func SyntheticProcesses(n int) []*models.ProcessInfo {
+	rng := rand.New(rand.NewSource(syntheticSeed))
+	now := time.Now()
+
+	processes := make([]*models.ProcessInfo, n)
+	for i := range processes {
+		name := syntheticNames[rng.Intn(len(syntheticNames))]
+		dir := syntheticDirs[rng.Intn(len(syntheticDirs))]
+		args := []string{"/usr/bin/" + name, "--worker", fmt.Sprintf("%d", i)}
+		pid := int32(i + 1)
+
+		processes[i] = &models.ProcessInfo{
+			PID:            pid,
+			PPID:           int32(rng.Intn(i + 1)),
+			Name:           name,
+			Status:         syntheticStatuses[rng.Intn(len(syntheticStatuses))],
+			CPU:            rng.ExpFloat64() * 5,
+			Memory:         rng.Float64() * 10,
+			MemoryBytes:    uint64(rng.Int63n(4 << 30)),
+			CreateTime:     now.Add(-time.Duration(rng.Int63n(int64(72 * time.Hour)))),
+			Username:       syntheticUsers[rng.Intn(len(syntheticUsers))],
+			Command:        fmt.Sprintf("/usr/bin/%s --worker %d", name, i),
+			Args:           args,
+			WorkingDir:     dir,
+			Executable:     "/usr/bin/" + name,
+			NumThreads:     int32(1 + rng.Intn(64)),
+			Nice:           int32(rng.Intn(40) - 20),
+			IsRunning:      true,
+			SessionID:      int32(1 + rng.Intn(50)),
+			ProcessGroupID: pid,
+		}
+	}
+
+	return processes
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"tappmanager/internal/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ansiSequence matches the terminal escape sequences lipgloss and termenv
+// write: CSI sequences such as colors, OSC sequences such as hyperlinks and
+// two-character escapes
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// stripANSI removes the escape sequences from rendered text
+func stripANSI(rendered string) string {
+	return ansiSequence.ReplaceAllString(rendered, "")
+}
+
+// captureView saves rendered, the view called name as shown on screen, in
+// the data directory with and without its styling
+func captureView(store storage.Storage, name, rendered string) tea.Cmd {
+	return func() tea.Msg {
+		// Trailing padding only makes the text files harder to paste
+		lines := strings.Split(stripANSI(rendered), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		text := strings.Join(lines, "\n") + "\n"
+
+		textPath, styledPath, err := store.ExportView(name, text, rendered+"\x1b[0m\n")
+		return captureMsg{TextPath: textPath, StyledPath: styledPath, Error: err}
+	}
+}
+
+// Messages
+type captureMsg struct {
+	TextPath   string
+	StyledPath string
+	Error      error
+}
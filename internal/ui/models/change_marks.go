@@ -0,0 +1,84 @@
+package models
+
+import (
+	"math"
+
+	"tappmanager/internal/models"
+)
+
+// Least changes marked, so that noise between refreshes is not: CPU and
+// memory in percentage points, and resident memory in bytes
+const (
+	cpuChangeMin         = 1.0
+	memoryChangeMin      = 0.1
+	memoryBytesChangeMin = 1 << 20
+)
+
+// Widths of the changes shown in absolute mode, without their arrow
+const (
+	cpuChangeWidth         = 5 // 100.0
+	memoryChangeWidth      = 5 // 12.50
+	memoryBytesChangeWidth = 9 // 512.0 MiB
+)
+
+// usageFigures are the CPU and memory of a process as the table shows them
+type usageFigures struct {
+	cpu         float64 // percent, of its cgroup's CPU limit if it has one
+	memory      float64 // percent, of its cgroup's memory limit if it has one
+	memoryBytes uint64
+}
+
+// figuresOf returns the figures of the processes by PID
+func figuresOf(processes []*models.ProcessInfo) map[int32]usageFigures {
+	figures := make(map[int32]usageFigures, len(processes))
+	for _, proc := range processes {
+		cpu, _ := cpuShare(proc)
+		memory, _ := memoryShare(proc)
+		figures[proc.PID] = usageFigures{cpu: cpu, memory: memory, memoryBytes: proc.MemoryBytes}
+	}
+	return figures
+}
+
+// changeMark returns the mark following a figure that changed by delta
+// since the previous scan: ▲ or ▼, followed in absolute mode by the change
+// formatted by format. Changes below least, and figures with no previous
+// scan to compare to, are not marked.
+func (m ProcessesModel) changeMark(compared bool, delta, least float64, format func(delta float64) string) string {
+	if m.changes == models.ChangesOff || !compared || math.Abs(delta) < least {
+		return ""
+	}
+
+	mark := "▼"
+	if delta > 0 {
+		mark = "▲"
+	}
+	if m.changes == models.ChangesAbsolute {
+		// Apart from the figure, which the arrow alone follows directly
+		mark = " " + mark + format(math.Abs(delta))
+	}
+	return mark
+}
+
+// columnMarkWidth returns the width kept for change marks at the end of the
+// cells of the column id, zero for columns whose changes are not marked
+func (m ProcessesModel) columnMarkWidth(id string) int {
+	width := 0
+	switch {
+	case id == "cpu":
+		width = cpuChangeWidth
+	case id == "memory" && m.memoryBytes:
+		width = memoryBytesChangeWidth
+	case id == "memory":
+		width = memoryChangeWidth
+	default:
+		return 0
+	}
+
+	switch m.changes {
+	case models.ChangesOff:
+		return 0
+	case models.ChangesAbsolute:
+		return width + 2
+	}
+	return 1
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+)
+
+// sparkBlocks are the block characters used to draw sparklines, lowest first
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// barEighths are the blocks ending a bar, from none to seven eighths of a cell
+var barEighths = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+// renderBar renders percent, out of 100, as a horizontal bar width cells
+// wide, filled in eighths of a cell and padded with spaces
+func renderBar(percent float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	eighths := int(min(max(percent, 0), 100)/100*float64(width*8) + 0.5)
+	full := eighths / 8
+	bar := strings.Repeat("█", full)
+	if full < width {
+		bar += string(barEighths[eighths%8]) + strings.Repeat(" ", width-full-1)
+	}
+	return bar
+}
+
+// renderSparkline renders values as a single-line block chart scaled to max.
+// Only the most recent width values are drawn.
+func renderSparkline(values []float64, max float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	if max <= 0 {
+		for _, v := range values {
+			if v > max {
+				max = v
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if max <= 0 || v <= 0 {
+			b.WriteRune(sparkBlocks[0])
+			continue
+		}
+		level := int(v / max * float64(len(sparkBlocks)-1))
+		if level >= len(sparkBlocks) {
+			level = len(sparkBlocks) - 1
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+
+	return b.String()
+}
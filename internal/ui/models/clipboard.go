@@ -0,0 +1,20 @@
+package models
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// copyToClipboard copies text to the system clipboard using the OSC 52
+// terminal escape sequence, which also works over SSH
+func copyToClipboard(label, text string) tea.Cmd {
+	return func() tea.Msg {
+		termenv.Copy(text)
+		return clipboardMsg{Label: label}
+	}
+}
+
+// Messages
+type clipboardMsg struct {
+	Label string
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"maps"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// columnTitles describe the optional columns in the column chooser
+var columnTitles = map[string]string{
+	models.ColumnPID:       "PID",
+	models.ColumnPPID:      "Parent PID",
+	models.ColumnCPUBar:    "CPU as a bar",
+	models.ColumnMemoryBar: "Memory as a bar",
+	models.ColumnThreads:   "Threads",
+	models.ColumnNice:      "Nice",
+	models.ColumnIO:        "Disk read and written",
+	models.ColumnStart:     "Start time",
+	models.ColumnCommand:   "Command line",
+}
+
+// columnMenu shows or hides the optional columns of the processes table
+type columnMenu struct {
+	open  bool
+	index int             // into models.ProcessColumns
+	shown map[string]bool // columns ticked, applied on Enter
+}
+
+// Open shows the menu with the columns currently shown ticked
+func (c *columnMenu) Open(shown map[string]bool) {
+	c.open = true
+	c.index = 0
+	c.shown = maps.Clone(shown)
+}
+
+// Update handles a key while the menu is open. It returns the columns to
+// show, in table order, once Enter applies them.
+func (c *columnMenu) Update(msg tea.KeyMsg) []string {
+	switch msg.String() {
+	case "esc":
+		c.open = false
+
+	case "up", "k":
+		c.index = (c.index + len(models.ProcessColumns) - 1) % len(models.ProcessColumns)
+
+	case "down", "j":
+		c.index = (c.index + 1) % len(models.ProcessColumns)
+
+	case " ", "x":
+		column := models.ProcessColumns[c.index]
+		c.shown[column] = !c.shown[column]
+
+	case "enter":
+		c.open = false
+		columns := []string{}
+		for _, column := range models.ProcessColumns {
+			if c.shown[column] {
+				columns = append(columns, column)
+			}
+		}
+		return columns
+	}
+	return nil
+}
+
+// Hint describes the keys for the status bar
+func (c columnMenu) Hint() string {
+	return "↑/↓: choose column | Space: show/hide | Enter: apply and save | Esc: cancel"
+}
+
+// View renders the optional columns one per line, ticked if shown
+func (c columnMenu) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render("Columns") + "\n\n"
+	for i, column := range models.ProcessColumns {
+		box := "[ ]"
+		if c.shown[column] {
+			box = "[x]"
+		}
+		line := box + " " + columnTitles[column]
+		if i == c.index {
+			line = selectedStyle.Render(line)
+		}
+		content += "  " + line + "\n"
+	}
+	content += "\n" + dimStyle.Render("Name, status, CPU, memory and user are always shown.") + "\n"
+	return content
+}
+
+// saveColumns stores the columns to show in the config, so they are shown
+// at the next start too. The config is loaded as stored so environment and
+// flag overrides are not written to the file along with the columns.
+func saveColumns(store storage.Storage, columns []string) tea.Cmd {
+	return func() tea.Msg {
+		config, err := store.LoadFileConfig()
+		if err == nil {
+			config.Columns = columns
+			err = store.SaveConfig(config)
+		}
+		return columnsSavedMsg{Error: err}
+	}
+}
+
+// Messages
+type columnsSavedMsg struct {
+	Error error
+}
@@ -1,19 +1,79 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"tappmanager/internal/models"
+)
 
 // AppConfig represents the application configuration for Bubble Tea
 type AppConfig struct {
-	RefreshRate    int           `json:"refresh_rate"`
-	ShowSystem     bool          `json:"show_system"`
-	DefaultSort    ProcessSort   `json:"default_sort"`
-	DefaultFilter  ProcessFilter `json:"default_filter"`
-	AutoRefresh    bool          `json:"auto_refresh"`
-	Theme          string        `json:"theme"`
-	DataDir        string        `json:"data_dir"`
-	Version        string        `json:"version"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	SchemaVersion   int           `json:"schema_version"`
+	RefreshRate     int           `json:"refresh_rate"`
+	ShowSystem      bool          `json:"show_system"`
+	DefaultSort     ProcessSort   `json:"default_sort"`
+	DefaultFilter   ProcessFilter `json:"default_filter"`
+	AutoRefresh     bool          `json:"auto_refresh"`
+	AutoBackup      bool          `json:"auto_backup"`
+	BackupCount     int           `json:"backup_count"`
+	Theme           string        `json:"theme"`
+	DataDir         string        `json:"data_dir"`
+	Version         string        `json:"version"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	DStateThreshold int           `json:"dstate_threshold"`
+
+	DuplicateThreshold int      `json:"duplicate_threshold"`
+	DuplicateExclude   []string `json:"duplicate_exclude"`
+
+	ProcessesRefresh int `json:"processes_refresh"`
+	DetailsRefresh   int `json:"details_refresh"`
+	StatsRefresh     int `json:"stats_refresh"`
+
+	ProcessCap int `json:"process_cap"`
+
+	HistoryBudget    int `json:"history_budget"`
+	HistoryRetention int `json:"history_retention"`
+
+	SnapshotCompression string `json:"snapshot_compression"`
+
+	ExportRetention   models.RetentionPolicy `json:"export_retention"`
+	SnapshotRetention models.RetentionPolicy `json:"snapshot_retention"`
+	BackupRetention   models.RetentionPolicy `json:"backup_retention"`
+	EventRetention    models.RetentionPolicy `json:"event_retention"`
+
+	CheckUpdates bool `json:"check_updates"`
+
+	LogFiles      map[string][]string   `json:"log_files"`
+	LogHighlights []models.LogHighlight `json:"log_highlights"`
+
+	Budgets []models.ResourceBudget `json:"budgets"`
+
+	ExportSchedules []models.ExportSchedule `json:"export_schedules"`
+
+	CPULimitPercent int `json:"cpu_limit_percent"`
+
+	KillTimeout int `json:"kill_timeout"`
+
+	IdleThreshold int `json:"idle_threshold"`
+
+	ListenerInterval int `json:"listener_interval"`
+
+	HashAllowlist string `json:"hash_allowlist"`
+
+	ControlSocket bool `json:"control_socket"`
+
+	EventLog string `json:"event_log"`
+
+	MQTT models.MQTTConfig `json:"mqtt"`
+
+	Grafana models.GrafanaConfig `json:"grafana"`
+
+	Watch []string `json:"watch"`
+
+	Columns []string `json:"columns"`
+
+	ChangeIndicators string `json:"change_indicators"`
 }
 
 // ProcessSort represents sorting options for processes
@@ -37,6 +97,8 @@ type ProcessFilter struct {
 // NewAppConfig creates a new AppConfig instance with default values
 func NewAppConfig() *AppConfig {
 	return &AppConfig{
+		SchemaVersion: models.ConfigSchemaVersion,
+
 		RefreshRate: 2,
 		ShowSystem:  false,
 		DefaultSort: ProcessSort{
@@ -54,10 +116,67 @@ func NewAppConfig() *AppConfig {
 			ShowSystem: false,
 		},
 		AutoRefresh: true,
+		AutoBackup:  true,
+		BackupCount: 10,
 		Theme:       "default",
 		DataDir:     "~/.tappmanager",
 		Version:     "1.0.0",
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+
+		DStateThreshold: 10,
+
+		DuplicateThreshold: 1,
+		DuplicateExclude:   []string{"chrome", "chromium", "firefox", "code", "electron", "postgres", "nginx", "httpd", "apache2", "php-fpm*"},
+
+		ProcessesRefresh: 2,
+		DetailsRefresh:   3,
+		StatsRefresh:     5,
+
+		ProcessCap: 2000,
+
+		HistoryBudget:    1024,
+		HistoryRetention: 86400,
+
+		SnapshotCompression: "none",
+
+		CheckUpdates: false,
+
+		LogFiles: map[string][]string{},
+		LogHighlights: []models.LogHighlight{
+			{Pattern: `(?i)\b(error|fatal|panic)\b`, Color: "196"},
+			{Pattern: `(?i)\bwarn(ing)?\b`, Color: "220"},
+		},
+
+		Budgets: []models.ResourceBudget{},
+
+		ExportSchedules: []models.ExportSchedule{},
+
+		CPULimitPercent: 50,
+
+		KillTimeout: 5,
+
+		IdleThreshold: 3600,
+
+		ListenerInterval: 30,
+
+		ControlSocket: true,
+
+		MQTT: models.MQTTConfig{
+			ClientID:     "tappmanager-{host}",
+			SummaryTopic: "tappmanager/{host}/summary",
+			EventTopic:   "tappmanager/{host}/events",
+			Interval:     60,
+		},
+
+		Grafana: models.GrafanaConfig{
+			Tags: []string{"tappmanager"},
+		},
+
+		Watch: []string{},
+
+		Columns: []string{models.ColumnPID, models.ColumnThreads, models.ColumnNice},
+
+		ChangeIndicators: models.ChangesArrows,
 	}
 }
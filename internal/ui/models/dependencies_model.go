@@ -0,0 +1,202 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dependenciesReloadInterval is how often the dependencies view reloads the map
+const dependenciesReloadInterval = 5 * time.Second
+
+// DependenciesModel shows which local processes talk to each other, grouped
+// by the server they connect to
+type DependenciesModel struct {
+	processService *services.ProcessService
+	dependencies   []models.Dependency
+	err            error
+	offset         int // first line shown
+	width          int
+	height         int
+}
+
+// NewDependenciesModel creates a new dependencies model
+func NewDependenciesModel(processService *services.ProcessService) *DependenciesModel {
+	return &DependenciesModel{processService: processService}
+}
+
+// Init initializes the model
+func (m DependenciesModel) Init() tea.Cmd {
+	return m.loadDependencies()
+}
+
+// Update handles messages and updates the model
+func (m DependenciesModel) Update(msg tea.Msg) (DependenciesModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.scroll(-1)
+
+		case "down", "j":
+			m.scroll(1)
+
+		case "pgup":
+			m.scroll(-m.visibleLines())
+
+		case "pgdown":
+			m.scroll(m.visibleLines())
+
+		case "r":
+			cmd = m.loadDependencies()
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case dependenciesMsg:
+		m.dependencies = msg.Dependencies
+		m.err = msg.Error
+		m.scroll(0)
+
+	case refreshTimerMsg:
+		cmd = m.loadDependencies()
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	return m, cmd
+}
+
+// UpdateSize updates the model with new dimensions
+func (m DependenciesModel) UpdateSize(width, height int) DependenciesModel {
+	m.width = width
+	m.height = height
+	m.scroll(0)
+	return m
+}
+
+// visibleLines returns how many lines of the map fit in the view
+func (m DependenciesModel) visibleLines() int {
+	// Borders, padding, title, status and controls
+	return max(m.height-11, 1)
+}
+
+// scroll moves the first line shown by delta, keeping the map in view
+func (m *DependenciesModel) scroll(delta int) {
+	m.offset = max(min(m.offset+delta, len(m.lines())-m.visibleLines()), 0)
+}
+
+// lines renders the map as a header per server and port followed by the
+// processes connected to it
+func (m DependenciesModel) lines() []string {
+	var lines []string
+	for i, dep := range m.dependencies {
+		if i == 0 || dep.ServerPID != m.dependencies[i-1].ServerPID || dep.Port != m.dependencies[i-1].Port {
+			lines = append(lines, fmt.Sprintf("%s on :%d", processLabel(dep.ServerName, dep.ServerPID), dep.Port))
+		}
+		connections := "connection"
+		if dep.Connections != 1 {
+			connections += "s"
+		}
+		lines = append(lines, fmt.Sprintf("  ← %-40s %d %s", processLabel(dep.ClientName, dep.ClientPID), dep.Connections, connections))
+	}
+	return lines
+}
+
+// processLabel names a process by name and PID, or as unknown when it
+// belongs to a user we cannot see
+func processLabel(name string, pid int32) string {
+	if pid == 0 {
+		return "(unknown process)"
+	}
+	if name == "" {
+		name = "?"
+	}
+	return fmt.Sprintf("%s (PID %d)", name, pid)
+}
+
+// View renders the dependencies view
+func (m DependenciesModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	serverStyle := lipgloss.NewStyle().
+		Foreground(colors.text).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render("Service Dependencies (local TCP connections by listening process):") + "\n"
+	lines := m.lines()
+	switch {
+	case m.err != nil:
+		content += valueStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+	case len(lines) == 0:
+		content += dimStyle.Render("No connections between local processes.") + "\n"
+	default:
+		end := min(m.offset+m.visibleLines(), len(lines))
+		for _, line := range lines[m.offset:end] {
+			if len(line) > 0 && line[0] != ' ' {
+				content += serverStyle.Render(line) + "\n"
+			} else {
+				content += valueStyle.Render(line) + "\n"
+			}
+		}
+	}
+
+	status := fmt.Sprintf("%d links", len(m.dependencies))
+	if len(lines) > m.visibleLines() {
+		status += fmt.Sprintf(" | Lines %d-%d of %d", m.offset+1, min(m.offset+m.visibleLines(), len(lines)), len(lines))
+	}
+	content += "\n" + dimStyle.Render(status) + "\n"
+
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render("↑/↓, PgUp/PgDn: scroll | r: reload | esc: back")
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(1, 2).
+		Render(fullContent)
+}
+
+// loadDependencies maps the connections between the current processes
+func (m DependenciesModel) loadDependencies() tea.Cmd {
+	return func() tea.Msg {
+		processes, err := m.processService.GetProcesses()
+		if err != nil {
+			return dependenciesMsg{Error: err}
+		}
+		dependencies, err := m.processService.GetDependencies(processes)
+		return dependenciesMsg{Dependencies: dependencies, Error: err}
+	}
+}
+
+// Messages
+type dependenciesMsg struct {
+	Dependencies []models.Dependency
+	Error        error
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"tappmanager/internal/models"
@@ -12,32 +15,120 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// cpuLimitStep is how much +/- change a CPU limit, in percent
+const cpuLimitStep = 5
+
+// detailsHistoryLen is how many refreshes of the shown process's CPU and
+// memory are kept for its sparklines
+const detailsHistoryLen = 300
+
+// Tabs of the details view
+const (
+	detailsTabOverview = iota
+	detailsTabResources
+	detailsTabOpenFiles
+	detailsTabConnections
+	detailsTabEnvironment
+	detailsTabThreads
+	detailsTabChildren
+	detailsTabRuntime
+	detailsTabJVM // only shown for JVMs
+	detailsTabCount
+)
+
+// detailsTabNames are the titles of the details view tabs, by tab
+var detailsTabNames = [detailsTabCount]string{"Overview", "Resources", "Files", "Network", "Environment", "Threads", "Children", "Runtime", "JVM"}
+
 // DetailsModel handles the process details view
 type DetailsModel struct {
 	processService *services.ProcessService
+	limiter        *services.CPULimiter
+	limitPercent   int // CPU limit applied by L
+	killTimeout    time.Duration
 	processes      []*models.ProcessInfo
 	selectedIndex  int
+	// shownPID is the selected process, followed to its position in the
+	// list as refreshes reorder it; zero until one is chosen
+	shownPID int32
+	showArgs       bool
+	argIndex       int
+	statusMessage  string
+	signals        signalMenu
+	// renicing is set while a nice value is being typed into niceInput
+	renicing  bool
+	niceInput string
 	width          int
 	height         int
+	refreshRate    time.Duration
 	refreshing     bool
+
+	// tab is the details tab shown for the selected process
+	tab int
+	// Connections of connectionsPID, loaded while the connections tab is shown
+	connections    []models.Connection
+	connectionsPID int32
+	connectionsErr error
+	// Open files of openFilesPID, loaded while the open files tab is shown
+	openFiles    []models.OpenFile
+	openFilesPID int32
+	openFilesErr error
+	// Environment of environmentPID, loaded while the environment tab is shown;
+	// revealSecrets shows the values of sensitive variables
+	environment    []models.EnvVar
+	environmentPID int32
+	environmentErr error
+	revealSecrets  bool
+	// Threads of threadsPID, loaded while the threads tab is shown, and
+	// their CPU percent since the load before, at threadsAt; threadsByTID
+	// orders them by thread ID rather than by CPU
+	threads      []models.Thread
+	threadsPID   int32
+	threadsErr   error
+	threadsAt    time.Time
+	threadCPU    map[int32]float64
+	threadsByTID bool
+	// Children of childrenPID, listed while the children tab is shown, and
+	// the one chosen to show next
+	children    []*models.ProcessInfo
+	childrenPID int32
+	childIndex  int
+	// Runtime of runtimePID, detected while the runtime tab is shown, and
+	// the output of its action; confirmingAction is set while waiting for
+	// the action that makes the process exit to be confirmed
+	runtime          *models.RuntimeInfo
+	runtimePID       int32
+	runtimeOutput    []string
+	confirmingAction bool
+	// Heap and GC figures of jvmStatsPID, loaded while the JVM tab is shown
+	jvmStats    *models.JVMStats
+	jvmStatsPID int32
+	jvmStatsErr error
+	// listOffset is the first row shown on the list tabs
+	listOffset int
+	// CPU percent and memory bytes of historyPID at each refresh, oldest
+	// first, drawn as sparklines on the resources tab
+	cpuHistory    []float64
+	memoryHistory []float64
+	historyPID    int32
 }
 
 // NewDetailsModel creates a new details model
-func NewDetailsModel(processService *services.ProcessService) *DetailsModel {
+func NewDetailsModel(processService *services.ProcessService, limiter *services.CPULimiter, config *models.AppConfig) *DetailsModel {
 	return &DetailsModel{
 		processService: processService,
+		limiter:        limiter,
+		limitPercent:   config.CPULimitPercent,
+		killTimeout:    time.Duration(config.KillTimeout) * time.Second,
 		processes:      []*models.ProcessInfo{},
 		selectedIndex:  0,
+		refreshRate:    refreshInterval(config.DetailsRefresh, defaultDetailsRefresh),
 		refreshing:     false,
 	}
 }
 
 // Init initializes the model
 func (m DetailsModel) Init() tea.Cmd {
-	return tea.Batch(
-		m.refreshProcesses(),
-		m.startRefreshTimer(),
-	)
+	return m.refreshProcesses()
 }
 
 // Update handles messages and updates the model
@@ -46,28 +137,173 @@ func (m DetailsModel) Update(msg tea.Msg) (DetailsModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.signals.open {
+			cmd = m.signals.Update(msg, m.processService)
+			break
+		}
+		if m.renicing {
+			cmd = m.updateNicePrompt(msg)
+			break
+		}
+		if m.confirmingAction {
+			// Any key but T again cancels
+			m.confirmingAction = false
+			m.statusMessage = ""
+			if msg.String() == "t" {
+				cmd = m.runRuntimeAction()
+			}
+			break
+		}
+		if m.tab == detailsTabChildren {
+			var handled bool
+			if cmd, handled = m.updateChildren(msg); handled {
+				break
+			}
+		}
+
 		switch msg.String() {
 		case "up", "k":
 			if m.selectedIndex > 0 {
 				m.selectedIndex--
+				m.shownPID = m.processes[m.selectedIndex].PID
+				m.argIndex = 0
+				m.listOffset = 0
+				m.revealSecrets = false
+				m.keepTabShown()
+				cmd = m.loadTab()
 			}
 
 		case "down", "j":
 			if m.selectedIndex < len(m.processes)-1 {
 				m.selectedIndex++
+				m.shownPID = m.processes[m.selectedIndex].PID
+				m.argIndex = 0
+				m.listOffset = 0
+				m.revealSecrets = false
+				m.keepTabShown()
+				cmd = m.loadTab()
+			}
+
+		case "tab":
+			m.tab = m.nextTab(1)
+			m.listOffset = 0
+			cmd = m.loadTab()
+
+		case "shift+tab":
+			m.tab = m.nextTab(-1)
+			m.listOffset = 0
+			cmd = m.loadTab()
+
+		case "pgdown":
+			m.scrollList(m.listRows())
+
+		case "pgup":
+			m.scrollList(-m.listRows())
+
+		case "m":
+			if m.tab == detailsTabEnvironment {
+				m.revealSecrets = !m.revealSecrets
+			}
+
+		case "u", "U":
+			// Walk up the tree to the parent
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				proc := m.processes[m.selectedIndex]
+				if proc.PPID == 0 {
+					m.statusMessage = fmt.Sprintf("%s (PID %d) has no parent", proc.Name, proc.PID)
+					break
+				}
+				cmd = m.showRelative(proc.PPID, "parent")
+			}
+
+		case "c", "C":
+			m.tab = detailsTabChildren
+			m.listOffset = 0
+			cmd = m.loadTab()
+
+		case "o":
+			if m.tab == detailsTabThreads {
+				m.threadsByTID = !m.threadsByTID
+				m.sortThreads()
 			}
 
 		case "r":
-			cmd = m.refreshProcesses()
+			cmd = tea.Batch(m.refreshProcesses(), m.loadTab())
 
 		case "ctrl+k":
 			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
-				cmd = m.killProcess(m.processes[m.selectedIndex].PID)
+				proc := m.processes[m.selectedIndex]
+				m.statusMessage = fmt.Sprintf("Terminating %s (PID %d)...", proc.Name, proc.PID)
+				cmd = terminateProcess(m.processService, proc, m.killTimeout)
+			}
+
+		case "alt+k":
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				cmd = m.killProcess(m.processes[m.selectedIndex])
+			}
+
+		case "S":
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				m.signals.Open(m.processes[m.selectedIndex])
+			}
+
+		case "<", ">":
+			// Step the nice value; lower values raise the priority
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				proc := m.processes[m.selectedIndex]
+				delta := 1
+				if msg.String() == "<" {
+					delta = -1
+				}
+				cmd = m.setNice(proc, int(proc.Nice)+delta)
+			}
+
+		case "n":
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				m.renicing = true
+				m.niceInput = ""
 			}
 
 		case "f":
 			cmd = m.showSearchDialog()
 
+		case "a":
+			// Toggle between raw command line and parsed argv
+			m.showArgs = !m.showArgs
+			m.argIndex = 0
+
+		case "[":
+			if m.showArgs && m.argIndex > 0 {
+				m.argIndex--
+			}
+
+		case "]":
+			if m.showArgs && m.selectedIndex < len(m.processes) && m.argIndex < len(m.processes[m.selectedIndex].Args)-1 {
+				m.argIndex++
+			}
+
+		case "y":
+			cmd = m.copyCommand()
+
+		case "t":
+			if m.tab == detailsTabRuntime && m.selectedRuntime() != nil {
+				if action, exits := services.RuntimeAction(m.runtime.Name); exits {
+					m.confirmingAction = true
+					m.statusMessage = action + ". Press T again to confirm, any other key to cancel"
+				} else {
+					cmd = m.runRuntimeAction()
+				}
+			}
+
+		case "L":
+			m.toggleCPULimit()
+
+		case "+", "=":
+			m.adjustCPULimit(cpuLimitStep)
+
+		case "-":
+			m.adjustCPULimit(-cpuLimitStep)
+
 		case "esc":
 			// Return to processes view
 			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
@@ -76,18 +312,74 @@ func (m DetailsModel) Update(msg tea.Msg) (DetailsModel, tea.Cmd) {
 	case refreshProcessesMsg:
 		m.processes = msg.Processes
 		m.refreshing = false
-		// Keep selected index within bounds
-		if m.selectedIndex >= len(m.processes) {
-			m.selectedIndex = len(m.processes) - 1
+		m.selectShown()
+		m.recordHistory()
+		// Keep the shown tab as current as the process list
+		m.keepTabShown()
+		cmd = m.loadTab()
+
+	case connectionsMsg:
+		m.connections = msg.Connections
+		m.connectionsPID = msg.PID
+		m.connectionsErr = msg.Error
+		m.scrollList(0)
+
+	case openFilesMsg:
+		m.openFiles = msg.Files
+		m.openFilesPID = msg.PID
+		m.openFilesErr = msg.Error
+		m.scrollList(0)
+
+	case environmentMsg:
+		m.environment = msg.Environment
+		m.environmentPID = msg.PID
+		m.environmentErr = msg.Error
+		m.scrollList(0)
+
+	case threadsMsg:
+		m.updateThreads(msg)
+		m.scrollList(0)
+
+	case childrenMsg:
+		if msg.PID != m.childrenPID {
+			m.childIndex = 0
+		}
+		m.children = msg.Children
+		m.childrenPID = msg.PID
+		m.childIndex = max(min(m.childIndex, len(m.children)-1), 0)
+		m.scrollList(0)
+
+	case jvmStatsMsg:
+		m.jvmStats = msg.Stats
+		m.jvmStatsPID = msg.PID
+		m.jvmStatsErr = msg.Error
+
+	case runtimeMsg:
+		if msg.PID != m.runtimePID {
+			m.runtimeOutput = nil
+		}
+		m.runtime = msg.Runtime
+		m.runtimePID = msg.PID
+		m.scrollList(0)
+
+	case runtimeActionMsg:
+		if msg.Error != nil {
+			m.statusMessage = fmt.Sprintf("Failed for PID %d: %v", msg.PID, msg.Error)
+			break
 		}
-		if m.selectedIndex < 0 {
-			m.selectedIndex = 0
+		m.statusMessage = fmt.Sprintf("Done for PID %d: %s", msg.PID, msg.Action)
+		if msg.PID == m.runtimePID && msg.Output != "" {
+			m.runtimeOutput = strings.Split(strings.TrimRight(msg.Output, "\n"), "\n")
+			m.listOffset = 0
 		}
+		cmd = m.refreshProcesses()
 
 	case refreshTimerMsg:
 		cmd = m.refreshProcesses()
 
 	case killProcessMsg:
+		// The main model reports the outcome
+		m.statusMessage = ""
 		if msg.Success {
 			// Process killed successfully, select next process
 			if m.selectedIndex < len(m.processes)-1 {
@@ -95,6 +387,29 @@ func (m DetailsModel) Update(msg tea.Msg) (DetailsModel, tea.Cmd) {
 			} else if m.selectedIndex > 0 {
 				m.selectedIndex--
 			}
+			if m.selectedIndex < len(m.processes) {
+				m.shownPID = m.processes[m.selectedIndex].PID
+			}
+		}
+
+	case clipboardMsg:
+		m.statusMessage = "Copied " + msg.Label + " to clipboard"
+
+	case signalMsg:
+		m.statusMessage = ""
+		cmd = m.refreshProcesses()
+
+	case niceMsg:
+		if msg.Error != nil {
+			m.statusMessage = fmt.Sprintf("Failed to renice %s: %v", msg.Name, msg.Error)
+			break
+		}
+		m.statusMessage = fmt.Sprintf("Set nice value of %s (PID %d) to %d", msg.Name, msg.PID, msg.Nice)
+		// Show the new value before the next refresh, so steps add up
+		for _, proc := range m.processes {
+			if proc.PID == msg.PID {
+				proc.Nice = int32(msg.Nice)
+			}
 		}
 
 	case SwitchViewMsg:
@@ -111,6 +426,15 @@ func (m DetailsModel) UpdateSize(width, height int) DetailsModel {
 	return m
 }
 
+// InputMode reports whether the signal menu is open or a nice value is
+// being typed
+func (m DetailsModel) InputMode() InputMode {
+	if m.signals.open || m.renicing || m.confirmingAction {
+		return ModeForm
+	}
+	return ModeNormal
+}
+
 // View renders the details view
 func (m DetailsModel) View() string {
 	if m.refreshing {
@@ -128,7 +452,28 @@ func (m DetailsModel) View() string {
 	proc := m.processes[m.selectedIndex]
 	
 	// Create details content
-	content := m.renderProcessDetails(proc)
+	var content string
+	switch m.tab {
+	case detailsTabConnections:
+		content = m.renderConnections(proc)
+	case detailsTabOpenFiles:
+		content = m.renderOpenFiles(proc)
+	case detailsTabEnvironment:
+		content = m.renderEnvironment(proc)
+	case detailsTabResources:
+		content = m.renderResources(proc)
+	case detailsTabThreads:
+		content = m.renderThreads(proc)
+	case detailsTabChildren:
+		content = m.renderChildren(proc)
+	case detailsTabRuntime:
+		content = m.renderRuntime(proc)
+	case detailsTabJVM:
+		content = m.renderJVM(proc)
+	default:
+		content = m.renderProcessDetails(proc)
+	}
+	content = m.renderTabs() + "\n\n" + content
 	
 	// Add navigation info
 	nav := m.renderNavigation()
@@ -145,7 +490,7 @@ func (m DetailsModel) View() string {
 
 	styledContent := contentStyle.
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(1, 2).
 		Render(fullContent)
 
@@ -155,15 +500,15 @@ func (m DetailsModel) View() string {
 // renderProcessDetails renders detailed process information
 func (m DetailsModel) renderProcessDetails(proc *models.ProcessInfo) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Bold(true)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("230"))
+		Foreground(colors.text)
 
 	// Basic Information
 	basicInfo := titleStyle.Render("Basic Information:") + "\n"
@@ -172,21 +517,29 @@ func (m DetailsModel) renderProcessDetails(proc *models.ProcessInfo) string {
 	basicInfo += labelStyle.Render("Name:") + " " + valueStyle.Render(proc.Name) + "\n"
 	basicInfo += labelStyle.Render("Status:") + " " + valueStyle.Render(proc.Status) + "\n"
 	basicInfo += labelStyle.Render("User:") + " " + valueStyle.Render(proc.Username) + "\n"
+	if proc.Terminal != "" {
+		basicInfo += labelStyle.Render("TTY:") + " " + valueStyle.Render(proc.Terminal) + "\n"
+	}
+	if proc.Virtualization != "" {
+		basicInfo += labelStyle.Render("Runs Under:") + " " + valueStyle.Render(proc.Virtualization) + "\n"
+	}
+	if proc.SessionID != 0 {
+		basicInfo += labelStyle.Render("Session / Process Group:") + " " + valueStyle.Render(fmt.Sprintf("%d / %d", proc.SessionID, proc.ProcessGroupID)) + "\n"
+	}
 
-	// Resource Usage
-	resourceInfo := "\n" + titleStyle.Render("Resource Usage:") + "\n"
-	resourceInfo += labelStyle.Render("CPU Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", proc.CPU)) + "\n"
-	resourceInfo += labelStyle.Render("Memory Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", proc.Memory)) + "\n"
-	resourceInfo += labelStyle.Render("Memory (Bytes):") + " " + valueStyle.Render(strconv.FormatUint(proc.MemoryBytes, 10)) + "\n"
-	resourceInfo += labelStyle.Render("Number of Threads:") + " " + valueStyle.Render(strconv.Itoa(int(proc.NumThreads))) + "\n"
-	resourceInfo += labelStyle.Render("Nice Value:") + " " + valueStyle.Render(strconv.Itoa(int(proc.Nice))) + "\n"
+	// Identity
+	identityInfo := m.renderIdentity(proc, titleStyle, labelStyle, valueStyle)
 
 	// Process Information
 	processInfo := "\n" + titleStyle.Render("Process Information:") + "\n"
-	processInfo += labelStyle.Render("Command:") + " " + valueStyle.Render(proc.Command) + "\n"
+	processInfo += m.renderCommand(proc, labelStyle, valueStyle)
+	processInfo += labelStyle.Render("Executable:") + " " + valueStyle.Render(proc.Executable) + "\n"
 	processInfo += labelStyle.Render("Working Directory:") + " " + valueStyle.Render(proc.WorkingDir) + "\n"
 	processInfo += labelStyle.Render("Create Time:") + " " + valueStyle.Render(proc.CreateTime.Format("2006-01-02 15:04:05")) + "\n"
 	processInfo += labelStyle.Render("Running:") + " " + valueStyle.Render(fmt.Sprintf("%t", proc.IsRunning)) + "\n"
+	if proc.SecurityContext != "" {
+		processInfo += labelStyle.Render("Security Context:") + " " + valueStyle.Render(proc.SecurityContext) + "\n"
+	}
 
 	// Navigation
 	navigation := "\n" + titleStyle.Render("Navigation:") + "\n"
@@ -194,9 +547,708 @@ func (m DetailsModel) renderProcessDetails(proc *models.ProcessInfo) string {
 	navigation += "Ctrl+R - Refresh\n"
 	navigation += "Ctrl+K - Kill selected process\n"
 	navigation += "Ctrl+F - Search processes\n"
+	navigation += "A - Toggle raw/parsed command line\n"
+	navigation += "[/] - Select previous/next argument\n"
+	navigation += "Y - Copy command line or selected argument\n"
+	navigation += "Tab/Shift+Tab - Switch tab: resources, files, network, environment, threads, children, runtime\n"
+	navigation += "U - Show the parent process, C - List the children\n"
 	navigation += "Esc - Return to processes view\n"
 
-	return basicInfo + resourceInfo + processInfo + navigation
+	return basicInfo + identityInfo + processInfo + navigation
+}
+
+// renderResources renders the CPU, memory and disk usage of proc and its
+// CPU limit
+func (m DetailsModel) renderResources(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	resourceInfo := titleStyle.Render(fmt.Sprintf("Resources of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	resourceInfo += labelStyle.Render("CPU Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", proc.CPU))
+	if share, limited := cpuShare(proc); limited {
+		limit := strconv.FormatFloat(proc.CgroupCPULimit, 'f', -1, 64) + " CPU"
+		resourceInfo += " " + lipgloss.NewStyle().Foreground(lipgloss.Color(usageColor(share, true))).Render(fmt.Sprintf("(%.0f%% of its %s limit)", share, limit))
+	}
+	resourceInfo += "\n"
+	resourceInfo += labelStyle.Render("Memory Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", proc.Memory))
+	if share, limited := memoryShare(proc); limited {
+		resourceInfo += " " + lipgloss.NewStyle().Foreground(lipgloss.Color(usageColor(share, true))).Render(fmt.Sprintf("(%.0f%% of its %s limit)", share, formatBytes(proc.CgroupMemoryLimit)))
+	}
+	resourceInfo += "\n"
+	resourceInfo += labelStyle.Render("Memory (Bytes):") + " " + valueStyle.Render(strconv.FormatUint(proc.MemoryBytes, 10)) + "\n"
+	resourceInfo += labelStyle.Render("Number of Threads:") + " " + valueStyle.Render(strconv.Itoa(int(proc.NumThreads))) + "\n"
+	resourceInfo += labelStyle.Render("Nice Value:") + " " + valueStyle.Render(strconv.Itoa(int(proc.Nice))) + "\n"
+	if proc.IO != nil {
+		resourceInfo += labelStyle.Render("Disk Read:") + " " + valueStyle.Render(fmt.Sprintf("%s in %d ops", formatBytes(proc.IO.ReadBytes), proc.IO.ReadCount)) + "\n"
+		resourceInfo += labelStyle.Render("Disk Written:") + " " + valueStyle.Render(fmt.Sprintf("%s in %d ops", formatBytes(proc.IO.WriteBytes), proc.IO.WriteCount)) + "\n"
+	}
+
+	// History
+	resourceInfo += m.renderHistory(proc, titleStyle, labelStyle, valueStyle)
+
+	// CPU Limit
+	resourceInfo += "\n" + m.renderCPULimit(proc, labelStyle, valueStyle)
+	resourceInfo += "\n" + lipgloss.NewStyle().Foreground(colors.dim).Render("Shift+L - Toggle CPU limit, +/- to adjust it") + "\n"
+
+	return resourceInfo
+}
+
+// recordHistory adds the selected process's CPU and memory to its history,
+// starting over when another process is selected
+func (m *DetailsModel) recordHistory() {
+	if m.selectedIndex >= len(m.processes) {
+		return
+	}
+	proc := m.processes[m.selectedIndex]
+	if proc.PID != m.historyPID {
+		m.cpuHistory, m.memoryHistory = nil, nil
+		m.historyPID = proc.PID
+	}
+	// Append to copies, as earlier models may share the backing arrays
+	m.cpuHistory = append(slices.Clone(m.cpuHistory), proc.CPU)
+	m.memoryHistory = append(slices.Clone(m.memoryHistory), float64(proc.MemoryBytes))
+	if len(m.cpuHistory) > detailsHistoryLen {
+		m.cpuHistory = m.cpuHistory[len(m.cpuHistory)-detailsHistoryLen:]
+		m.memoryHistory = m.memoryHistory[len(m.memoryHistory)-detailsHistoryLen:]
+	}
+}
+
+// renderHistory renders sparklines of proc's CPU and memory since it was
+// selected, the newest on the right
+func (m DetailsModel) renderHistory(proc *models.ProcessInfo, titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	history := "\n" + titleStyle.Render("History:") + "\n"
+	if m.historyPID != proc.PID || len(m.cpuHistory) < 2 {
+		return history + valueStyle.Render("Collecting samples...") + "\n"
+	}
+
+	// Borders and padding take 10 columns, the labels and figures 36
+	chartWidth := max(m.width-46, 10)
+	cpu := m.cpuHistory[max(len(m.cpuHistory)-chartWidth, 0):]
+	memory := m.memoryHistory[max(len(m.memoryHistory)-chartWidth, 0):]
+
+	// A full bar is one busy core, unless the process used more
+	cpuPeak := slices.Max(cpu)
+	history += labelStyle.Render("CPU:   ") + " " + valueStyle.Render(renderSparkline(cpu, max(cpuPeak, 100), chartWidth)) + " " +
+		valueStyle.Render(fmt.Sprintf("peak %.1f%%", cpuPeak)) + "\n"
+
+	// Memory is scaled to its own peak, so slow growth shows
+	first, last := memory[0], memory[len(memory)-1]
+	history += labelStyle.Render("Memory:") + " " + valueStyle.Render(renderSparkline(memory, slices.Max(memory), chartWidth)) + " " +
+		valueStyle.Render(fmt.Sprintf("%s → %s", formatBytes(uint64(first)), formatBytes(uint64(last)))) + "\n"
+	history += labelStyle.Render("Span:  ") + " " + valueStyle.Render(fmt.Sprintf("last %d refreshes, every %s", len(cpu), m.refreshRate)) + "\n"
+	return history
+}
+
+// updateThreads takes in newly loaded threads, working out the CPU percent
+// of each from the time it used since the previous load of the same process
+func (m *DetailsModel) updateThreads(msg threadsMsg) {
+	cpu := make(map[int32]float64, len(msg.Threads))
+	if elapsed := msg.At.Sub(m.threadsAt).Seconds(); msg.PID == m.threadsPID && m.threadsErr == nil && elapsed > 0 {
+		previous := make(map[int32]float64, len(m.threads))
+		for _, thread := range m.threads {
+			previous[thread.TID] = thread.User + thread.System
+		}
+		for _, thread := range msg.Threads {
+			if used, ok := previous[thread.TID]; ok {
+				cpu[thread.TID] = max(thread.User+thread.System-used, 0) / elapsed * 100
+			}
+		}
+	}
+
+	m.threads = msg.Threads
+	m.threadsPID = msg.PID
+	m.threadsErr = msg.Error
+	m.threadsAt = msg.At
+	m.threadCPU = cpu
+	m.sortThreads()
+}
+
+// sortThreads orders the threads by thread ID or by CPU: by CPU percent,
+// then by the CPU time used, which orders them before percents are known
+func (m *DetailsModel) sortThreads() {
+	// Sort a copy, as earlier models share the slice
+	m.threads = slices.Clone(m.threads)
+	slices.SortFunc(m.threads, func(a, b models.Thread) int {
+		if m.threadsByTID {
+			return cmp.Compare(a.TID, b.TID)
+		}
+		return cmp.Or(
+			cmp.Compare(m.threadCPU[b.TID], m.threadCPU[a.TID]),
+			cmp.Compare(b.User+b.System, a.User+a.System),
+			cmp.Compare(a.TID, b.TID),
+		)
+	})
+}
+
+// renderThreads renders the threads of proc with their CPU use
+func (m DetailsModel) renderThreads(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	order := "by CPU"
+	if m.threadsByTID {
+		order = "by thread ID"
+	}
+	content := titleStyle.Render(fmt.Sprintf("Threads of %s (PID %d), %s:", proc.Name, proc.PID, order)) + "\n"
+	switch {
+	case m.threadsPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case m.threadsErr != nil:
+		return content + dimStyle.Render(fmt.Sprintf("Unavailable: %v", m.threadsErr)) + "\n"
+	case len(m.threads) == 0:
+		return content + dimStyle.Render("No threads") + "\n"
+	}
+
+	format := "%8s  %-16.16s %7s %10s %10s %10s"
+	content += headerStyle.Render(fmt.Sprintf(format, "TID", "Name", "CPU %", "User", "System", "Total")) + "\n"
+	end := min(m.listOffset+m.listRows(), len(m.threads))
+	for _, thread := range m.threads[m.listOffset:end] {
+		// The percent needs a previous load to compare to
+		cpu := "-"
+		if percent, ok := m.threadCPU[thread.TID]; ok {
+			cpu = fmt.Sprintf("%.1f", percent)
+		}
+		content += valueStyle.Render(fmt.Sprintf(format, strconv.Itoa(int(thread.TID)), thread.Name, cpu,
+			fmt.Sprintf("%.2fs", thread.User), fmt.Sprintf("%.2fs", thread.System), fmt.Sprintf("%.2fs", thread.User+thread.System))) + "\n"
+	}
+	return content + m.renderListPosition(len(m.threads), dimStyle)
+}
+
+// updateChildren handles the keys choosing a child on the children tab: the
+// arrows move between the children and Enter shows the one chosen. It
+// reports whether the key was one of them.
+func (m *DetailsModel) updateChildren(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.childrenPID != m.shownPID || len(m.children) == 0 {
+		return nil, false
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.childIndex = max(m.childIndex-1, 0)
+	case "down", "j":
+		m.childIndex = min(m.childIndex+1, len(m.children)-1)
+	case "enter":
+		return m.showRelative(m.children[m.childIndex].PID, "child"), true
+	default:
+		return nil, false
+	}
+
+	// Keep the chosen child in view
+	if m.childIndex < m.listOffset {
+		m.listOffset = m.childIndex
+	} else if m.childIndex >= m.listOffset+m.listRows() {
+		m.listOffset = m.childIndex - m.listRows() + 1
+	}
+	return nil, true
+}
+
+// showRelative shows the process pid, the parent or a child of the one
+// shown, staying on the current tab
+func (m *DetailsModel) showRelative(pid int32, relation string) tea.Cmd {
+	if !slices.ContainsFunc(m.processes, func(p *models.ProcessInfo) bool { return p.PID == pid }) {
+		m.statusMessage = fmt.Sprintf("The %s process %d is not listed", relation, pid)
+		return nil
+	}
+	m.statusMessage = ""
+	m.ShowProcess(pid)
+	m.keepTabShown()
+	return m.loadTab()
+}
+
+// renderChildren renders the processes proc started
+func (m DetailsModel) renderChildren(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("Children of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.childrenPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case len(m.children) == 0:
+		return content + dimStyle.Render("No child processes") + "\n"
+	}
+
+	// Borders and padding take 10 columns, the PID, CPU, memory and user
+	// columns and spacing 41
+	nameWidth := max(m.width-51, 10)
+	format := fmt.Sprintf("%%7s %%-%d.%ds %%7s %%9s %%-14.14s", nameWidth, nameWidth)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	content += headerStyle.Render(fmt.Sprintf(format, "PID", "Name", "CPU %", "Memory", "User")) + "\n"
+	end := min(m.listOffset+m.listRows(), len(m.children))
+	for i, child := range m.children[m.listOffset:end] {
+		style := valueStyle
+		if m.listOffset+i == m.childIndex {
+			style = selectedStyle
+		}
+		content += style.Render(fmt.Sprintf(format, strconv.Itoa(int(child.PID)), child.Name,
+			fmt.Sprintf("%.1f", child.CPU), formatBytes(child.MemoryBytes), child.Username)) + "\n"
+	}
+	return content + m.renderListPosition(len(m.children), dimStyle)
+}
+
+// renderTabs renders the tab bar with the current tab highlighted
+func (m DetailsModel) renderTabs() string {
+	activeStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text).
+		Bold(true).
+		Padding(0, 1)
+
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Padding(0, 1)
+
+	var tabs []string
+	for tab, name := range detailsTabNames {
+		if !m.tabShown(tab) {
+			continue
+		}
+		if tab == m.tab {
+			tabs = append(tabs, activeStyle.Render(name))
+		} else {
+			tabs = append(tabs, inactiveStyle.Render(name))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+// ShowProcess selects the process pid, once the list has it; zero keeps
+// the selection
+func (m *DetailsModel) ShowProcess(pid int32) {
+	if pid == 0 || pid == m.shownPID {
+		return
+	}
+	m.shownPID = pid
+	m.argIndex = 0
+	m.listOffset = 0
+	m.revealSecrets = false
+	m.selectShown()
+}
+
+// selectShown selects shownPID in the list. Once it is no longer listed, the
+// selection stays in place, kept within the list, and moves to the process
+// there.
+func (m *DetailsModel) selectShown() {
+	if i := slices.IndexFunc(m.processes, func(p *models.ProcessInfo) bool { return p.PID == m.shownPID }); i >= 0 {
+		m.selectedIndex = i
+		return
+	}
+
+	m.selectedIndex = max(min(m.selectedIndex, len(m.processes)-1), 0)
+	if len(m.processes) == 0 {
+		return
+	}
+	if m.shownPID != 0 {
+		m.statusMessage = fmt.Sprintf("Process %d is no longer running", m.shownPID)
+	}
+	m.shownPID = m.processes[m.selectedIndex].PID
+}
+
+// tabShown reports whether tab applies to the selected process; the JVM tab
+// is only shown for JVMs
+func (m DetailsModel) tabShown(tab int) bool {
+	if tab != detailsTabJVM {
+		return true
+	}
+	return m.selectedIndex < len(m.processes) && services.IsJVM(m.processes[m.selectedIndex])
+}
+
+// nextTab returns the tab step tabs away from the current one, skipping the
+// tabs not shown
+func (m DetailsModel) nextTab(step int) int {
+	tab := m.tab
+	for {
+		tab = (tab + step + detailsTabCount) % detailsTabCount
+		if m.tabShown(tab) {
+			return tab
+		}
+	}
+}
+
+// keepTabShown moves to the overview once the selected process has no
+// current tab
+func (m *DetailsModel) keepTabShown() {
+	if !m.tabShown(m.tab) {
+		m.tab = detailsTabOverview
+	}
+}
+
+// renderConnections renders the sockets held open by proc
+func (m DetailsModel) renderConnections(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("Connections of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.connectionsPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case m.connectionsErr != nil:
+		return content + dimStyle.Render(fmt.Sprintf("Unavailable: %v", m.connectionsErr)) + "\n"
+	case len(m.connections) == 0:
+		return content + dimStyle.Render("No open connections") + "\n"
+	}
+
+	// Borders and padding take 10 columns; the local and remote addresses
+	// share what the protocol, state and spacing leave
+	addrWidth := max((m.width-10-6-12-3)/2, 15)
+	format := fmt.Sprintf("%%-6s %%-%d.%ds %%-%d.%ds %%s", addrWidth, addrWidth, addrWidth, addrWidth)
+
+	content += headerStyle.Render(fmt.Sprintf(format, "Proto", "Local Address", "Remote Address", "State")) + "\n"
+	end := min(m.listOffset+m.listRows(), len(m.connections))
+	for _, conn := range m.connections[m.listOffset:end] {
+		content += valueStyle.Render(fmt.Sprintf(format, conn.Protocol, conn.LocalAddr, conn.RemoteAddr, conn.Status)) + "\n"
+	}
+	return content + m.renderListPosition(len(m.connections), dimStyle)
+}
+
+// renderOpenFiles renders the files held open by proc
+func (m DetailsModel) renderOpenFiles(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("Open Files of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.openFilesPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case m.openFilesErr != nil:
+		return content + dimStyle.Render(fmt.Sprintf("Unavailable: %v", m.openFilesErr)) + "\n"
+	case len(m.openFiles) == 0:
+		return content + dimStyle.Render("No open files") + "\n"
+	}
+
+	// Borders and padding take 10 columns, the descriptor column 7
+	pathWidth := max(m.width-17, 10)
+
+	content += headerStyle.Render(fmt.Sprintf("%6s %s", "FD", "Path")) + "\n"
+	end := min(m.listOffset+m.listRows(), len(m.openFiles))
+	for _, file := range m.openFiles[m.listOffset:end] {
+		path := file.Path
+		if runes := []rune(path); len(runes) > pathWidth {
+			// Keep the end of the path, which names the file
+			path = "..." + string(runes[len(runes)-pathWidth+3:])
+		}
+		content += valueStyle.Render(fmt.Sprintf("%6d %s", file.FD, path)) + "\n"
+	}
+	return content + m.renderListPosition(len(m.openFiles), dimStyle)
+}
+
+// renderEnvironment renders the environment of proc, masking the values of
+// sensitive variables unless they were revealed
+func (m DetailsModel) renderEnvironment(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	maskedStyle := lipgloss.NewStyle().
+		Foreground(colors.warning)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("Environment of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.environmentPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case m.environmentErr != nil:
+		return content + dimStyle.Render(fmt.Sprintf("Unavailable: %v", m.environmentErr)) + "\n"
+	case len(m.environment) == 0:
+		return content + dimStyle.Render("Empty environment") + "\n"
+	}
+
+	// Borders and padding take 10 columns
+	lineWidth := max(m.width-10, 20)
+
+	end := min(m.listOffset+m.listRows(), len(m.environment))
+	for _, env := range m.environment[m.listOffset:end] {
+		name := env.Name + "="
+		value, style := env.Value, valueStyle
+		if env.Sensitive && !m.revealSecrets {
+			value, style = "********", maskedStyle
+		}
+		if runes := []rune(value); len(name)+len(runes) > lineWidth {
+			value = string(runes[:max(lineWidth-len(name)-3, 0)]) + "..."
+		}
+		content += labelStyle.Render(name) + style.Render(value) + "\n"
+	}
+	return content + m.renderListPosition(len(m.environment), dimStyle)
+}
+
+// renderRuntime renders the language runtime of proc, its action and what
+// the action printed
+func (m DetailsModel) renderRuntime(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("Runtime of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.runtimePID != proc.PID:
+		return content + dimStyle.Render("Detecting...") + "\n"
+	case m.runtime == nil:
+		return content + dimStyle.Render("No Go, JVM, Python or Node runtime recognised") + "\n"
+	}
+
+	content += labelStyle.Render("Runtime:") + " " + valueStyle.Render(runtimeNames[m.runtime.Name]) + "\n"
+	if m.runtime.Version != "" {
+		content += labelStyle.Render("Version:") + " " + valueStyle.Render(m.runtime.Version) + "\n"
+	}
+	if m.runtime.Main != "" {
+		content += labelStyle.Render("Main:") + " " + valueStyle.Render(m.runtime.Main) + "\n"
+	}
+	action, _ := services.RuntimeAction(m.runtime.Name)
+	content += labelStyle.Render("T:") + " " + valueStyle.Render(action) + "\n\n"
+
+	if len(m.runtimeOutput) == 0 {
+		return content
+	}
+	// Borders and padding take 10 columns
+	lineWidth := max(m.width-10, 20)
+	end := min(m.listOffset+m.listRows(), len(m.runtimeOutput))
+	for _, line := range m.runtimeOutput[m.listOffset:end] {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if runes := []rune(line); len(runes) > lineWidth {
+			line = string(runes[:lineWidth-3]) + "..."
+		}
+		content += valueStyle.Render(line) + "\n"
+	}
+	return content + m.renderListPosition(len(m.runtimeOutput), dimStyle)
+}
+
+// renderJVM renders the heap and garbage collection figures of the JVM
+// running as proc
+func (m DetailsModel) renderJVM(proc *models.ProcessInfo) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render(fmt.Sprintf("JVM of %s (PID %d):", proc.Name, proc.PID)) + "\n"
+	switch {
+	case m.jvmStatsPID != proc.PID:
+		return content + dimStyle.Render("Loading...") + "\n"
+	case m.jvmStatsErr != nil:
+		return content + dimStyle.Render(fmt.Sprintf("Unavailable: %v", m.jvmStatsErr)) + "\n"
+	}
+
+	stats := m.jvmStats
+	usage := func(used, capacity uint64) string {
+		if capacity == 0 {
+			return formatBytes(used)
+		}
+		return fmt.Sprintf("%s of %s (%.0f%%)", formatBytes(used), formatBytes(capacity), float64(used)/float64(capacity)*100)
+	}
+
+	content += labelStyle.Render("Resident Memory:") + " " + valueStyle.Render(formatBytes(proc.MemoryBytes)) + "\n"
+	content += labelStyle.Render("Heap:") + " " + valueStyle.Render(usage(stats.HeapUsed, stats.HeapCapacity)) + "\n"
+	content += labelStyle.Render("Old Generation:") + " " + valueStyle.Render(usage(stats.OldUsed, stats.OldCapacity)) + "\n"
+	content += labelStyle.Render("Metaspace:") + " " + valueStyle.Render(usage(stats.MetaspaceUsed, stats.MetaspaceCapacity)) + "\n"
+	content += labelStyle.Render("Young GCs:") + " " + valueStyle.Render(fmt.Sprintf("%d taking %.2fs", stats.YoungGCs, stats.YoungGCTime)) + "\n"
+	content += labelStyle.Render("Full GCs:") + " " + valueStyle.Render(fmt.Sprintf("%d taking %.2fs", stats.FullGCs, stats.FullGCTime)) + "\n"
+
+	gcTime := fmt.Sprintf("%.2fs", stats.GCTime)
+	if uptime := time.Since(proc.CreateTime).Seconds(); !proc.CreateTime.IsZero() && uptime > 0 {
+		gcTime += fmt.Sprintf(" (%.1f%% of uptime)", stats.GCTime/uptime*100)
+	}
+	content += labelStyle.Render("GC Time:") + " " + valueStyle.Render(gcTime) + "\n"
+	return content
+}
+
+// runtimeNames are the display names of the runtimes
+var runtimeNames = map[string]string{
+	models.RuntimeGo:     "Go",
+	models.RuntimeJVM:    "JVM",
+	models.RuntimePython: "Python",
+	models.RuntimeNode:   "Node.js",
+}
+
+// renderListPosition renders which rows of a list of total are shown, or
+// nothing when the whole list fits
+func (m DetailsModel) renderListPosition(total int, dimStyle lipgloss.Style) string {
+	if total <= m.listRows() {
+		return ""
+	}
+	end := min(m.listOffset+m.listRows(), total)
+	return dimStyle.Render(fmt.Sprintf("Rows %d-%d of %d (PgUp/PgDn to scroll)", m.listOffset+1, end, total)) + "\n"
+}
+
+// listRows returns how many rows of a list tab fit in the view
+func (m DetailsModel) listRows() int {
+	// Borders, padding, tabs, title, header, the position line and navigation
+	rows := m.height - 14
+	if m.tab == detailsTabRuntime {
+		// The runtime, version, main and action lines
+		rows -= 4
+	}
+	return max(rows, 1)
+}
+
+// listLen returns the number of rows on the current tab
+func (m DetailsModel) listLen() int {
+	switch m.tab {
+	case detailsTabConnections:
+		return len(m.connections)
+	case detailsTabOpenFiles:
+		return len(m.openFiles)
+	case detailsTabEnvironment:
+		return len(m.environment)
+	case detailsTabThreads:
+		return len(m.threads)
+	case detailsTabChildren:
+		return len(m.children)
+	case detailsTabRuntime:
+		return len(m.runtimeOutput)
+	}
+	return 0
+}
+
+// scrollList moves the list on the current tab by delta rows, keeping it
+// within bounds
+func (m *DetailsModel) scrollList(delta int) {
+	m.listOffset = max(min(m.listOffset+delta, m.listLen()-m.listRows()), 0)
+}
+
+// renderCommand renders the command line either raw or as an indexed argv list
+func (m DetailsModel) renderCommand(proc *models.ProcessInfo, labelStyle, valueStyle lipgloss.Style) string {
+	if !m.showArgs || len(proc.Args) == 0 {
+		return labelStyle.Render("Command:") + " " + valueStyle.Render(proc.Command) + "\n"
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	command := labelStyle.Render(fmt.Sprintf("Command (%d args):", len(proc.Args))) + "\n"
+	for i, arg := range proc.Args {
+		line := fmt.Sprintf("  argv[%d] %s", i, arg)
+		if i == m.argIndex {
+			command += selectedStyle.Render(line) + "\n"
+		} else {
+			command += valueStyle.Render(line) + "\n"
+		}
+	}
+	return command
+}
+
+// renderIdentity renders real and effective user and group IDs with resolved names
+func (m DetailsModel) renderIdentity(proc *models.ProcessInfo, titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	identityInfo := "\n" + titleStyle.Render("Identity:") + "\n"
+	if len(proc.UIDs) == 0 && len(proc.GIDs) == 0 {
+		return identityInfo + valueStyle.Render("Unavailable") + "\n"
+	}
+
+	uids := m.formatIDs(proc.UIDs, m.processService.ResolveUser)
+	gids := m.formatIDs(proc.GIDs, m.processService.ResolveGroup)
+
+	uidStyle := valueStyle
+	if proc.IsSetuid() {
+		// A real/effective mismatch means the process gained (or dropped) privileges
+		uidStyle = lipgloss.NewStyle().Foreground(colors.err).Bold(true)
+		uids += " [setuid]"
+	}
+
+	identityInfo += labelStyle.Render("UID (real/effective):") + " " + uidStyle.Render(uids) + "\n"
+	identityInfo += labelStyle.Render("GID (real/effective):") + " " + valueStyle.Render(gids) + "\n"
+
+	return identityInfo
+}
+
+// formatIDs formats the real and effective IDs as "id (name) / id (name)"
+func (m DetailsModel) formatIDs(ids []int32, resolve func(int32) string) string {
+	if len(ids) > 2 {
+		ids = ids[:2]
+	}
+
+	var parts []string
+	for _, id := range ids {
+		part := strconv.Itoa(int(id))
+		if name := resolve(id); name != "" {
+			part += " (" + name + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " / ")
 }
 
 // renderNavigation renders navigation information
@@ -206,10 +1258,23 @@ func (m DetailsModel) renderNavigation() string {
 	}
 
 	navStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Italic(true)
 
-	return navStyle.Render(fmt.Sprintf("Process %d of %d", m.selectedIndex+1, len(m.processes)))
+	if m.signals.open {
+		return m.signals.View()
+	}
+	if m.renicing && m.selectedIndex < len(m.processes) {
+		proc := m.processes[m.selectedIndex]
+		return fmt.Sprintf("Nice value for %s (PID %d, now %d, %d to %d): %s█ | Enter: set, Esc: cancel",
+			proc.Name, proc.PID, proc.Nice, services.MinNice, services.MaxNice, m.niceInput)
+	}
+
+	nav := fmt.Sprintf("Process %d of %d", m.selectedIndex+1, len(m.processes))
+	if m.statusMessage != "" {
+		nav += " | " + m.statusMessage
+	}
+	return navStyle.Render(nav)
 }
 
 // refreshProcesses refreshes the process list
@@ -224,22 +1289,191 @@ func (m DetailsModel) refreshProcesses() tea.Cmd {
 	}
 }
 
-// startRefreshTimer starts the refresh timer
-func (m DetailsModel) startRefreshTimer() tea.Cmd {
+// loadTab loads what the current tab shows for the selected process
+func (m DetailsModel) loadTab() tea.Cmd {
+	if m.selectedIndex >= len(m.processes) {
+		return nil
+	}
+	pid := m.processes[m.selectedIndex].PID
+
+	switch m.tab {
+	case detailsTabConnections:
+		return func() tea.Msg {
+			connections, err := m.processService.GetConnections(pid)
+			return connectionsMsg{PID: pid, Connections: connections, Error: err}
+		}
+	case detailsTabOpenFiles:
+		return func() tea.Msg {
+			files, err := m.processService.GetOpenFiles(pid)
+			return openFilesMsg{PID: pid, Files: files, Error: err}
+		}
+	case detailsTabEnvironment:
+		return func() tea.Msg {
+			environment, err := m.processService.GetEnvironment(pid)
+			return environmentMsg{PID: pid, Environment: environment, Error: err}
+		}
+	case detailsTabThreads:
+		return func() tea.Msg {
+			threads, err := m.processService.GetThreads(pid)
+			return threadsMsg{PID: pid, Threads: threads, Error: err, At: time.Now()}
+		}
+	case detailsTabChildren:
+		processes := slices.Clone(m.processes)
+		return func() tea.Msg {
+			return childrenMsg{PID: pid, Children: m.processService.GetChildren(processes, pid)}
+		}
+	case detailsTabJVM:
+		return func() tea.Msg {
+			stats, err := m.processService.JVMStats(pid)
+			return jvmStatsMsg{PID: pid, Stats: stats, Error: err}
+		}
+	case detailsTabRuntime:
+		proc := *m.processes[m.selectedIndex]
+		return func() tea.Msg {
+			return runtimeMsg{PID: pid, Runtime: services.DetectRuntime(&proc)}
+		}
+	}
+	return nil
+}
+
+// selectedRuntime returns the runtime of the selected process once detected
+func (m DetailsModel) selectedRuntime() *models.RuntimeInfo {
+	if m.selectedIndex >= len(m.processes) || m.processes[m.selectedIndex].PID != m.runtimePID {
+		return nil
+	}
+	return m.runtime
+}
+
+// runRuntimeAction runs the action of the selected process's runtime
+func (m *DetailsModel) runRuntimeAction() tea.Cmd {
+	runtime := m.selectedRuntime()
+	if runtime == nil {
+		return nil
+	}
+	pid := m.runtimePID
+	action, _ := services.RuntimeAction(runtime.Name)
+	m.statusMessage = action + "..."
 	return func() tea.Msg {
-		time.Sleep(3 * time.Second)
-		return refreshTimerMsg{}
+		output, err := m.processService.RunRuntimeAction(pid, runtime.Name)
+		return runtimeActionMsg{PID: pid, Action: action, Output: output, Error: err}
+	}
+}
+
+// toggleCPULimit limits the selected process to the configured CPU share,
+// or removes its limit
+func (m *DetailsModel) toggleCPULimit() {
+	if m.selectedIndex >= len(m.processes) {
+		return
+	}
+	proc := m.processes[m.selectedIndex]
+
+	if limit, ok := m.limiter.Limit(proc.PID); ok && limit.Error == "" {
+		m.limiter.RemoveLimit(proc.PID)
+		m.statusMessage = fmt.Sprintf("Removed CPU limit from %s (PID %d)", proc.Name, proc.PID)
+		return
+	}
+	m.setCPULimit(proc, m.limitPercent)
+}
+
+// adjustCPULimit changes the limit of the selected process by delta percent
+func (m *DetailsModel) adjustCPULimit(delta int) {
+	if m.selectedIndex >= len(m.processes) {
+		return
+	}
+	proc := m.processes[m.selectedIndex]
+
+	limit, ok := m.limiter.Limit(proc.PID)
+	if !ok || limit.Error != "" {
+		m.statusMessage = "Press L to limit this process first"
+		return
+	}
+	m.setCPULimit(proc, limit.Percent+delta)
+}
+
+// setCPULimit limits proc to percent and reports the result
+func (m *DetailsModel) setCPULimit(proc *models.ProcessInfo, percent int) {
+	if err := m.limiter.SetLimit(proc.PID, proc.Name, percent); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to limit %s: %v", proc.Name, err)
+		return
+	}
+	limit, _ := m.limiter.Limit(proc.PID)
+	m.statusMessage = fmt.Sprintf("Limited %s (PID %d) to %d%% CPU", proc.Name, proc.PID, limit.Percent)
+}
+
+// renderCPULimit renders the CPU limit of proc and its measured effect
+func (m DetailsModel) renderCPULimit(proc *models.ProcessInfo, labelStyle, valueStyle lipgloss.Style) string {
+	limit, ok := m.limiter.Limit(proc.PID)
+	switch {
+	case !ok:
+		return labelStyle.Render("CPU Limit:") + " " + valueStyle.Render("none") + "\n"
+	case limit.Error != "":
+		return labelStyle.Render("CPU Limit:") + " " + valueStyle.Render(fmt.Sprintf("ended (%s)", limit.Error)) + "\n"
+	}
+
+	measured := "measuring..."
+	if time.Since(limit.StartedAt) > 2*time.Second {
+		measured = fmt.Sprintf("%.1f%% measured", limit.MeasuredCPU)
+	}
+	return labelStyle.Render("CPU Limit:") + " " + valueStyle.Render(fmt.Sprintf("%d%%, %s, since %s", limit.Percent, measured, limit.StartedAt.Format("15:04:05"))) + "\n"
+}
+
+// copyCommand copies the selected argument in parsed mode, or the full command line otherwise
+func (m DetailsModel) copyCommand() tea.Cmd {
+	if len(m.processes) == 0 || m.selectedIndex >= len(m.processes) {
+		return nil
+	}
+
+	proc := m.processes[m.selectedIndex]
+	if m.showArgs && m.argIndex < len(proc.Args) {
+		return copyToClipboard(fmt.Sprintf("argv[%d]", m.argIndex), proc.Args[m.argIndex])
+	}
+	return copyToClipboard("command line", proc.Command)
+}
+
+// updateNicePrompt edits the nice value prompt and applies the value on Enter
+func (m *DetailsModel) updateNicePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.renicing = false
+
+	case tea.KeyEnter:
+		m.renicing = false
+		value, err := strconv.Atoi(strings.TrimSpace(m.niceInput))
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Invalid nice value: %q", m.niceInput)
+			break
+		}
+		if m.selectedIndex < len(m.processes) {
+			return m.setNice(m.processes[m.selectedIndex], value)
+		}
+
+	case tea.KeyBackspace:
+		if len(m.niceInput) > 0 {
+			m.niceInput = m.niceInput[:len(m.niceInput)-1]
+		}
+
+	case tea.KeyRunes:
+		m.niceInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// setNice changes the nice value of proc
+func (m DetailsModel) setNice(proc *models.ProcessInfo, value int) tea.Cmd {
+	return func() tea.Msg {
+		err := m.processService.SetNice(proc.PID, value)
+		return niceMsg{PID: proc.PID, Name: proc.Name, Nice: value, Error: err}
 	}
 }
 
 // killProcess kills the selected process
-func (m DetailsModel) killProcess(pid int32) tea.Cmd {
+func (m DetailsModel) killProcess(proc *models.ProcessInfo) tea.Cmd {
 	return func() tea.Msg {
-		err := m.processService.KillProcess(pid)
+		err := m.processService.KillProcess(proc.PID)
 		if err != nil {
-			return killProcessMsg{Error: err}
+			return killProcessMsg{Error: err, PID: proc.PID, Name: proc.Name, Signal: services.SignalKill}
 		}
-		return killProcessMsg{Success: true}
+		return killProcessMsg{Success: true, PID: proc.PID, Name: proc.Name, Signal: services.SignalKill}
 	}
 }
 
@@ -256,3 +1490,58 @@ func (m DetailsModel) showSearchDialog() tea.Cmd {
 type searchProcessMsg struct {
 	Query string
 }
+
+type niceMsg struct {
+	PID   int32
+	Name  string
+	Nice  int
+	Error error
+}
+
+type connectionsMsg struct {
+	PID         int32
+	Connections []models.Connection
+	Error       error
+}
+
+type openFilesMsg struct {
+	PID   int32
+	Files []models.OpenFile
+	Error error
+}
+
+type environmentMsg struct {
+	PID         int32
+	Environment []models.EnvVar
+	Error       error
+}
+
+type threadsMsg struct {
+	PID     int32
+	Threads []models.Thread
+	Error   error
+	At      time.Time // when the threads were read
+}
+
+type childrenMsg struct {
+	PID      int32
+	Children []*models.ProcessInfo
+}
+
+type jvmStatsMsg struct {
+	PID   int32
+	Stats *models.JVMStats
+	Error error
+}
+
+type runtimeMsg struct {
+	PID     int32
+	Runtime *models.RuntimeInfo
+}
+
+type runtimeActionMsg struct {
+	PID    int32
+	Action string
+	Output string
+	Error  error
+}
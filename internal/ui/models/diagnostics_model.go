@@ -0,0 +1,299 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diagnosticsInterval is how often the diagnostics view reloads while open
+const diagnosticsInterval = 2 * time.Second
+
+// DiagnosticsModel handles the collector health diagnostics view
+type DiagnosticsModel struct {
+	diagnosticsService *services.DiagnosticsService
+	diagnostics        *models.Diagnostics
+	pruneReport        *models.PruneReport
+	pruneError         error
+	width              int
+	height             int
+}
+
+// NewDiagnosticsModel creates a new diagnostics model
+func NewDiagnosticsModel(diagnosticsService *services.DiagnosticsService) *DiagnosticsModel {
+	return &DiagnosticsModel{
+		diagnosticsService: diagnosticsService,
+	}
+}
+
+// Init initializes the model
+func (m DiagnosticsModel) Init() tea.Cmd {
+	return m.loadDiagnostics()
+}
+
+// Update handles messages and updates the model
+func (m DiagnosticsModel) Update(msg tea.Msg) (DiagnosticsModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			cmd = m.loadDiagnostics()
+
+		case "x":
+			cmd = m.previewPrune()
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case diagnosticsMsg:
+		m.diagnostics = msg.Diagnostics
+
+	case pruneReportMsg:
+		m.pruneReport = msg.Report
+		m.pruneError = msg.Error
+
+	case refreshTimerMsg:
+		cmd = m.loadDiagnostics()
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	return m, cmd
+}
+
+// UpdateSize updates the model with new dimensions
+func (m DiagnosticsModel) UpdateSize(width, height int) DiagnosticsModel {
+	m.width = width
+	m.height = height
+	return m
+}
+
+// View renders the diagnostics view
+func (m DiagnosticsModel) View() string {
+	if m.diagnostics == nil {
+		return "Collecting diagnostics...\n"
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	content := m.renderCollector(titleStyle, labelStyle, valueStyle)
+	content += m.renderStorage(titleStyle, labelStyle, valueStyle)
+	content += m.renderHistory(titleStyle, labelStyle, valueStyle)
+	content += m.renderRuntime(titleStyle, labelStyle, valueStyle)
+	content += m.renderMQTT(titleStyle, labelStyle, valueStyle)
+	content += m.renderGrafana(titleStyle, labelStyle, valueStyle)
+	content += m.renderPrunePreview(titleStyle, labelStyle, valueStyle)
+
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render(fmt.Sprintf("Updated every %s | r: refresh | x: prune preview | esc: back", diagnosticsInterval))
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(1, 2).
+		Render(fullContent)
+}
+
+// renderCollector renders process scan timing and per-field error counts
+func (m DiagnosticsModel) renderCollector(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	stats := m.diagnostics.Collector
+
+	collector := titleStyle.Render("Collector:") + "\n"
+	if stats.Scans == 0 {
+		return collector + valueStyle.Render("No process scans completed yet") + "\n"
+	}
+
+	scanned := stats.ProcessCount + stats.SkippedCount
+	collector += labelStyle.Render("Scans:") + " " + valueStyle.Render(fmt.Sprintf("%d (last %s ago)", stats.Scans, time.Since(stats.LastScanAt).Truncate(time.Second))) + "\n"
+	collector += labelStyle.Render("Scan Duration:") + " " + valueStyle.Render(fmt.Sprintf("%s (max %s)", stats.LastScanDuration.Truncate(time.Millisecond), stats.MaxScanDuration.Truncate(time.Millisecond))) + "\n"
+	collector += labelStyle.Render("Processes:") + " " + valueStyle.Render(fmt.Sprintf("%d collected, %d skipped", stats.ProcessCount, stats.SkippedCount)) + "\n"
+
+	collector += "\n" + titleStyle.Render("Field Errors (last scan):") + "\n"
+	if len(stats.FieldErrors) == 0 {
+		return collector + valueStyle.Render("None") + "\n"
+	}
+
+	fields := make([]string, 0, len(stats.FieldErrors))
+	for field := range stats.FieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		ci, cj := stats.FieldErrors[fields[i]], stats.FieldErrors[fields[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return fields[i] < fields[j]
+	})
+
+	for _, field := range fields {
+		count := stats.FieldErrors[field]
+		collector += labelStyle.Render(fmt.Sprintf("%-12s", field+":")) + " " + valueStyle.Render(fmt.Sprintf("%d (%.1f%% of scanned)", count, float64(count)/float64(scanned)*100)) + "\n"
+	}
+
+	return collector
+}
+
+// renderStorage renders storage write latency
+func (m DiagnosticsModel) renderStorage(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	stats := m.diagnostics.Storage
+
+	storage := "\n" + titleStyle.Render("Storage:") + "\n"
+	if stats.Writes == 0 {
+		return storage + valueStyle.Render("No writes recorded yet") + "\n"
+	}
+
+	storage += labelStyle.Render("Writes:") + " " + valueStyle.Render(fmt.Sprintf("%d (%d failed)", stats.Writes, stats.Errors)) + "\n"
+	storage += labelStyle.Render("Write Latency:") + " " + valueStyle.Render(fmt.Sprintf("%s (max %s)", stats.LastWriteLatency.Truncate(time.Microsecond), stats.MaxWriteLatency.Truncate(time.Microsecond))) + "\n"
+	storage += labelStyle.Render("Last Write:") + " " + valueStyle.Render(stats.LastWriteAt.Format("2006-01-02 15:04:05")) + "\n"
+
+	return storage
+}
+
+// renderHistory renders the memory used by the load history against its budget
+func (m DiagnosticsModel) renderHistory(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	stats := m.diagnostics.History
+
+	history := "\n" + titleStyle.Render("History:") + "\n"
+	history += labelStyle.Render("Memory:") + " " + valueStyle.Render(fmt.Sprintf("%s of %s budget (%.1f%%)", formatBytes(stats.MemoryBytes), formatBytes(stats.BudgetBytes), float64(stats.MemoryBytes)/float64(stats.BudgetBytes)*100)) + "\n"
+	history += labelStyle.Render("Samples:") + " " + valueStyle.Render(fmt.Sprintf("%d raw, %d at 10s, %d at 1m", stats.RawSamples, stats.MediumSamples, stats.CoarseSamples)) + "\n"
+
+	span := "empty"
+	if !stats.OldestSample.IsZero() {
+		span = formatUptime(time.Since(stats.OldestSample))
+	}
+	history += labelStyle.Render("Retention:") + " " + valueStyle.Render(fmt.Sprintf("%s of %s kept", span, formatUptime(stats.Retention))) + "\n"
+
+	return history
+}
+
+// renderRuntime renders the memory usage of the application itself
+func (m DiagnosticsModel) renderRuntime(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	stats := m.diagnostics.Runtime
+
+	runtimeInfo := "\n" + titleStyle.Render("Application:") + "\n"
+	runtimeInfo += labelStyle.Render("Heap In Use:") + " " + valueStyle.Render(fmt.Sprintf("%s of %s reserved", formatBytes(stats.HeapAlloc), formatBytes(stats.HeapSys))) + "\n"
+	runtimeInfo += labelStyle.Render("Total From OS:") + " " + valueStyle.Render(formatBytes(stats.Sys)) + "\n"
+	runtimeInfo += labelStyle.Render("GC Cycles:") + " " + valueStyle.Render(fmt.Sprintf("%d", stats.NumGC)) + "\n"
+	runtimeInfo += labelStyle.Render("Goroutines:") + " " + valueStyle.Render(fmt.Sprintf("%d", stats.Goroutines)) + "\n"
+
+	return runtimeInfo + "\n"
+}
+
+// renderMQTT renders the state of publishing to the MQTT broker, if enabled
+func (m DiagnosticsModel) renderMQTT(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	status := m.diagnostics.MQTT
+	if status == nil {
+		return ""
+	}
+
+	connection := "disconnected"
+	if status.Connected {
+		connection = "connected"
+	}
+
+	mqtt := "\n" + titleStyle.Render("MQTT:") + "\n"
+	mqtt += labelStyle.Render("Broker:") + " " + valueStyle.Render(fmt.Sprintf("%s (%s)", status.Broker, connection)) + "\n"
+	mqtt += labelStyle.Render("Messages:") + " " + valueStyle.Render(fmt.Sprintf("%d published, %d failed, %d events dropped", status.Published, status.Failed, status.Dropped)) + "\n"
+	if !status.LastPublishAt.IsZero() {
+		mqtt += labelStyle.Render("Last Publish:") + " " + valueStyle.Render(status.LastPublishAt.Format("2006-01-02 15:04:05")) + "\n"
+	}
+	if status.LastError != "" {
+		mqtt += labelStyle.Render("Last Error:") + " " + valueStyle.Render(fmt.Sprintf("%s at %s", status.LastError, status.LastErrorAt.Format("15:04:05"))) + "\n"
+	}
+	return mqtt
+}
+
+// renderGrafana renders the state of pushing annotations to Grafana, if enabled
+func (m DiagnosticsModel) renderGrafana(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	status := m.diagnostics.Grafana
+	if status == nil {
+		return ""
+	}
+
+	grafana := "\n" + titleStyle.Render("Grafana:") + "\n"
+	grafana += labelStyle.Render("URL:") + " " + valueStyle.Render(status.URL) + "\n"
+	grafana += labelStyle.Render("Annotations:") + " " + valueStyle.Render(fmt.Sprintf("%d pushed, %d failed, %d dropped", status.Pushed, status.Failed, status.Dropped)) + "\n"
+	if !status.LastPushAt.IsZero() {
+		grafana += labelStyle.Render("Last Push:") + " " + valueStyle.Render(status.LastPushAt.Format("2006-01-02 15:04:05")) + "\n"
+	}
+	if status.LastError != "" {
+		grafana += labelStyle.Render("Last Error:") + " " + valueStyle.Render(fmt.Sprintf("%s at %s", status.LastError, status.LastErrorAt.Format("15:04:05"))) + "\n"
+	}
+	return grafana
+}
+
+// renderPrunePreview renders the stored files the retention policies would delete
+func (m DiagnosticsModel) renderPrunePreview(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	if m.pruneReport == nil && m.pruneError == nil {
+		return ""
+	}
+
+	preview := titleStyle.Render("Prune Preview (dry run):") + "\n"
+	if m.pruneError != nil {
+		return preview + valueStyle.Render(fmt.Sprintf("Error: %v", m.pruneError)) + "\n\n"
+	}
+	if len(m.pruneReport.Files) == 0 {
+		return preview + valueStyle.Render("Nothing to prune") + "\n\n"
+	}
+
+	preview += labelStyle.Render("Would free:") + " " + valueStyle.Render(fmt.Sprintf("%s in %d files", formatBytes(uint64(m.pruneReport.FreedBytes)), len(m.pruneReport.Files))) + "\n"
+	for _, file := range m.pruneReport.Files {
+		preview += valueStyle.Render(fmt.Sprintf("  %s (%s, %s)", filepath.Base(file.Path), formatBytes(uint64(file.Size)), file.Reason)) + "\n"
+	}
+
+	return preview + "\n"
+}
+
+// previewPrune runs the retention policies without deleting anything
+func (m DiagnosticsModel) previewPrune() tea.Cmd {
+	return func() tea.Msg {
+		report, err := m.diagnosticsService.PreviewPrune()
+		return pruneReportMsg{Report: report, Error: err}
+	}
+}
+
+// loadDiagnostics collects the current diagnostics
+func (m DiagnosticsModel) loadDiagnostics() tea.Cmd {
+	return func() tea.Msg {
+		return diagnosticsMsg{Diagnostics: m.diagnosticsService.GetDiagnostics()}
+	}
+}
+
+// Messages
+type diagnosticsMsg struct {
+	Diagnostics *models.Diagnostics
+}
+
+type pruneReportMsg struct {
+	Report *models.PruneReport
+	Error  error
+}
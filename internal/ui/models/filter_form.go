@@ -0,0 +1,246 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Filter form fields
+const (
+	filterMinCPU = iota
+	filterMaxCPU
+	filterMinMemory
+	filterMaxMemory
+	filterStatus
+	filterUser
+	filterExcludeUsers
+	filterExcludeNames
+	filterExcludeSearch
+	filterFieldCount
+)
+
+// filterFieldLabels label the filter form's fields
+var filterFieldLabels = [filterFieldCount]string{"Min CPU %", "Max CPU %", "Min Memory %", "Max Memory %", "Status", "User",
+	"Hide Users", "Hide Names", "Hide Matching"}
+
+// filterFieldHints explain the filter form's fields
+var filterFieldHints = [filterFieldCount]string{
+	"0-100 per core, empty for no minimum",
+	"empty for no maximum",
+	"percent of RAM, empty for no minimum",
+	"empty for no maximum",
+	"running, sleep, idle, paused, zombie or empty for any",
+	"user name, empty for anyone",
+	"comma-separated user names whose processes are hidden",
+	"comma-separated process names to hide, ignoring case",
+	"hide processes whose name, command or user contains this",
+}
+
+// filterForm edits the CPU, memory, status and user criteria and the
+// exclusions of a process filter
+type filterForm struct {
+	open   bool
+	focus  int // field being edited
+	fields [filterFieldCount]textinput.Model
+	err    string
+}
+
+// Open shows the form filled in from filter, with the first field focused
+func (f *filterForm) Open(filter *models.ProcessFilter) {
+	f.open = true
+	f.focus = 0
+	f.err = ""
+	for i := range f.fields {
+		f.fields[i] = newTextInput()
+	}
+	f.fields[filterMinCPU].SetValue(formatFilterBound(filter.MinCPU))
+	f.fields[filterMaxCPU].SetValue(formatFilterBound(filter.MaxCPU))
+	f.fields[filterMinMemory].SetValue(formatFilterBound(filter.MinMemory))
+	f.fields[filterMaxMemory].SetValue(formatFilterBound(filter.MaxMemory))
+	f.fields[filterStatus].SetValue(displayStatus(filter.Status))
+	f.fields[filterUser].SetValue(filter.Username)
+	f.fields[filterExcludeUsers].SetValue(strings.Join(filter.ExcludeUsers, ", "))
+	f.fields[filterExcludeNames].SetValue(strings.Join(filter.ExcludeNames, ", "))
+	f.fields[filterExcludeSearch].SetValue(filter.ExcludeSearchTerm)
+}
+
+// formatFilterBound shows an unset bound as an empty field
+func formatFilterBound(value float64) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Update handles a key while the form is open. On Enter it returns filter
+// with the form's criteria applied, leaving the search term and the other
+// criteria as they were; it returns nil otherwise.
+func (f *filterForm) Update(msg tea.KeyMsg, filter *models.ProcessFilter) *models.ProcessFilter {
+	switch msg.String() {
+	case "esc":
+		f.open = false
+
+	case "tab", "down":
+		f.focus = (f.focus + 1) % filterFieldCount
+
+	case "shift+tab", "up":
+		f.focus = (f.focus + filterFieldCount - 1) % filterFieldCount
+
+	case "ctrl+r":
+		// Clear every field
+		for i := range f.fields {
+			f.fields[i].SetValue("")
+		}
+		f.err = ""
+
+	case "enter":
+		applied, field, err := f.apply(*filter)
+		if err != nil {
+			f.err = err.Error()
+			f.focus = field
+			return nil
+		}
+		f.open = false
+		return applied
+
+	default:
+		f.fields[f.focus], _ = f.fields[f.focus].Update(msg)
+	}
+	return nil
+}
+
+// apply sets the form's criteria on filter, or returns the first invalid
+// field and why
+func (f *filterForm) apply(filter models.ProcessFilter) (*models.ProcessFilter, int, error) {
+	bounds := []*float64{&filter.MinCPU, &filter.MaxCPU, &filter.MinMemory, &filter.MaxMemory}
+	for field, bound := range bounds {
+		text := strings.TrimSpace(f.fields[field].Value())
+		if text == "" {
+			*bound = 0
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+		if err != nil || value < 0 {
+			return nil, field, fmt.Errorf("%s must be a positive number", filterFieldLabels[field])
+		}
+		*bound = value
+	}
+	if filter.MaxCPU > 0 && filter.MinCPU > filter.MaxCPU {
+		return nil, filterMaxCPU, fmt.Errorf("Max CPU %% is below Min CPU %%")
+	}
+	if filter.MaxMemory > 0 && filter.MinMemory > filter.MaxMemory {
+		return nil, filterMaxMemory, fmt.Errorf("Max Memory %% is below Min Memory %%")
+	}
+
+	// Suspended processes are listed as paused but reported as stopped
+	filter.Status = strings.ToLower(strings.TrimSpace(f.fields[filterStatus].Value()))
+	if filter.Status == "paused" {
+		filter.Status = "stop"
+	}
+	filter.Username = strings.TrimSpace(f.fields[filterUser].Value())
+	filter.ExcludeUsers = splitFilterList(f.fields[filterExcludeUsers].Value())
+	filter.ExcludeNames = splitFilterList(f.fields[filterExcludeNames].Value())
+	filter.ExcludeSearchTerm = strings.TrimSpace(f.fields[filterExcludeSearch].Value())
+	return &filter, 0, nil
+}
+
+// splitFilterList splits a comma-separated field, dropping empty entries
+func splitFilterList(text string) []string {
+	var list []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// filterCriteria summarizes the criteria the form sets on filter, such as
+// "CPU 5-50%, user root", or returns "" if none are set
+func filterCriteria(filter *models.ProcessFilter) string {
+	var criteria []string
+	if r := filterRange(filter.MinCPU, filter.MaxCPU); r != "" {
+		criteria = append(criteria, "CPU "+r)
+	}
+	if r := filterRange(filter.MinMemory, filter.MaxMemory); r != "" {
+		criteria = append(criteria, "memory "+r)
+	}
+	if filter.Status != "" {
+		criteria = append(criteria, "status "+displayStatus(filter.Status))
+	}
+	if filter.Username != "" {
+		criteria = append(criteria, "user "+filter.Username)
+	}
+	if len(filter.ExcludeUsers) > 0 {
+		criteria = append(criteria, "not user "+strings.Join(filter.ExcludeUsers, "/"))
+	}
+	if len(filter.ExcludeNames) > 0 {
+		criteria = append(criteria, "not named "+strings.Join(filter.ExcludeNames, "/"))
+	}
+	if filter.ExcludeSearchTerm != "" {
+		criteria = append(criteria, fmt.Sprintf("not matching %q", filter.ExcludeSearchTerm))
+	}
+	switch filter.Virtualized {
+	case models.VirtualizedOnly:
+		criteria = append(criteria, "virtualized only")
+	case models.VirtualizedHide:
+		criteria = append(criteria, "not virtualized")
+	}
+	return strings.Join(criteria, ", ")
+}
+
+// filterRange describes the percentages between low and high, either of
+// which may be unset
+func filterRange(low, high float64) string {
+	switch {
+	case low > 0 && high > 0:
+		return formatFilterBound(low) + "-" + formatFilterBound(high) + "%"
+	case low > 0:
+		return "≥" + formatFilterBound(low) + "%"
+	case high > 0:
+		return "≤" + formatFilterBound(high) + "%"
+	default:
+		return ""
+	}
+}
+
+// View renders the form, one field per line
+func (f filterForm) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Width(16)
+
+	focusStyle := labelStyle.
+		Foreground(colors.text).
+		Bold(true)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(colors.err)
+
+	content := titleStyle.Render("Filter Processes") + "\n\n"
+	for i, field := range f.fields {
+		if i == f.focus {
+			content += focusStyle.Render("> "+filterFieldLabels[i]) + field.View() + "  " + dimStyle.Render(filterFieldHints[i]) + "\n"
+		} else {
+			content += labelStyle.Render("  "+filterFieldLabels[i]) + field.Value() + "\n"
+		}
+	}
+	if f.err != "" {
+		content += "\n" + errorStyle.Render(f.err) + "\n"
+	}
+	return content
+}
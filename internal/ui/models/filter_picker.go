@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/storage"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filterPicker lists the saved filters to apply one, save the current
+// filter under a name or delete one
+type filterPicker struct {
+	open    bool
+	filters []models.SavedFilter
+	index   int // into filters
+	// naming is set while the name to save the current filter under is typed
+	naming bool
+	name   textinput.Model
+}
+
+// Open shows the picker listing filters, the first one chosen
+func (p *filterPicker) Open(filters []models.SavedFilter) {
+	p.open = true
+	p.filters = filters
+	p.index = 0
+	p.naming = false
+}
+
+// Update handles a key while the picker is open. It returns the chosen
+// filter once Enter applies it, and the command saving the filters after
+// one is saved or deleted.
+func (p *filterPicker) Update(msg tea.KeyMsg, current *models.ProcessFilter, store storage.Storage) (*models.ProcessFilter, tea.Cmd) {
+	if p.naming {
+		return nil, p.updateName(msg, current, store)
+	}
+
+	switch msg.String() {
+	case "esc":
+		p.open = false
+
+	case "up", "k":
+		p.index = max(p.index-1, 0)
+
+	case "down", "j":
+		p.index = min(p.index+1, max(len(p.filters)-1, 0))
+
+	case "s":
+		p.naming = true
+		p.name = newTextInput()
+		if len(p.filters) > 0 {
+			// Offer the chosen name, so a filter is easily updated
+			p.name.SetValue(p.filters[p.index].Name)
+		}
+
+	case "d", "delete":
+		if len(p.filters) > 0 {
+			deleted := p.filters[p.index].Name
+			p.filters = slices.Delete(slices.Clone(p.filters), p.index, p.index+1)
+			p.index = min(p.index, max(len(p.filters)-1, 0))
+			return nil, saveFilters(store, p.filters, "Deleted filter "+deleted)
+		}
+
+	case "enter":
+		if len(p.filters) > 0 {
+			p.open = false
+			filter := p.filters[p.index].Filter
+			return &filter, nil
+		}
+	}
+	return nil, nil
+}
+
+// updateName edits the name being typed and saves current under it on
+// Enter, replacing any filter with the same name
+func (p *filterPicker) updateName(msg tea.KeyMsg, current *models.ProcessFilter, store storage.Storage) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		p.naming = false
+
+	case "enter":
+		name := strings.TrimSpace(p.name.Value())
+		if name == "" {
+			return nil
+		}
+		p.naming = false
+
+		saved := models.SavedFilter{Name: name, Filter: *current}
+		// A session only lasts until its leader exits
+		saved.Filter.SessionID = 0
+
+		p.filters = slices.Clone(p.filters)
+		if i := slices.IndexFunc(p.filters, func(f models.SavedFilter) bool { return f.Name == name }); i >= 0 {
+			p.filters[i] = saved
+			p.index = i
+		} else {
+			p.filters = append(p.filters, saved)
+			p.index = len(p.filters) - 1
+		}
+		return saveFilters(store, p.filters, "Saved filter "+name)
+
+	default:
+		p.name, _ = p.name.Update(msg)
+	}
+	return nil
+}
+
+// Hint describes the keys for the status bar
+func (p filterPicker) Hint() string {
+	if p.naming {
+		return fmt.Sprintf("Save current filter as: %s | Enter: save, Esc: cancel", p.name.View())
+	}
+	return "↑/↓: choose | Enter: apply | S: save current filter | D: delete | Esc: close"
+}
+
+// View renders the saved filters, one per line with what each matches
+func (p filterPicker) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render("Saved Filters") + "\n\n"
+	if len(p.filters) == 0 {
+		return content + dimStyle.Render("No saved filters yet. Press S to save the current filter.") + "\n"
+	}
+
+	width := 0
+	for _, f := range p.filters {
+		width = max(width, len([]rune(f.Name)))
+	}
+	for i, f := range p.filters {
+		name := fmt.Sprintf("%-*s", width, f.Name)
+		if i == p.index {
+			name = selectedStyle.Render(name)
+		}
+		content += "  " + name + "  " + dimStyle.Render(savedFilterSummary(&f.Filter)) + "\n"
+	}
+	return content
+}
+
+// savedFilterSummary describes everything filter matches on
+func savedFilterSummary(filter *models.ProcessFilter) string {
+	var parts []string
+	if filter.SearchTerm != "" {
+		parts = append(parts, fmt.Sprintf("search %q", filter.SearchTerm))
+	}
+	if criteria := filterCriteria(filter); criteria != "" {
+		parts = append(parts, criteria)
+	}
+	if filter.PathPrefix != "" {
+		parts = append(parts, "under "+filter.PathPrefix)
+	}
+	if filter.ShowSystem {
+		parts = append(parts, "system processes shown")
+	}
+	if len(parts) == 0 {
+		return "all processes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadFilters reads the saved filters to open the picker with
+func loadFilters(store storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		filters, err := store.LoadSavedFilters()
+		return savedFiltersMsg{Filters: filters, Open: true, Error: err}
+	}
+}
+
+// saveFilters writes filters and reports notice once they are saved
+func saveFilters(store storage.Storage, filters []models.SavedFilter, notice string) tea.Cmd {
+	return func() tea.Msg {
+		if err := store.SaveSavedFilters(filters); err != nil {
+			return savedFiltersMsg{Error: err}
+		}
+		return savedFiltersMsg{Notice: notice}
+	}
+}
+
+// Messages
+type savedFiltersMsg struct {
+	Filters []models.SavedFilter // loaded to open the picker with
+	Open    bool
+	Notice  string
+	Error   error
+}
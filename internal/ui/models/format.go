@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// formatBytes renders a byte count using binary units (KiB, MiB, GiB, ...)
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// formatStartTime renders when a process started the way ps does: the
+// time of day for today, else the date, and the year before this year
+func formatStartTime(start time.Time) string {
+	if start.IsZero() {
+		return ""
+	}
+	now := time.Now()
+	switch {
+	case start.YearDay() == now.YearDay() && start.Year() == now.Year():
+		return start.Format("15:04")
+	case start.Year() == now.Year():
+		return start.Format("Jan 02")
+	default:
+		return start.Format("2006")
+	}
+}
+
+// formatCount renders large counts in thousands, e.g. 23456 as "23.5k"
+func formatCount(n int) string {
+	if n < 10000 {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+// formatUptime renders a duration as days, hours and minutes
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// formatRetention describes a retention policy, e.g. "30 days, 100 MiB"
+func formatRetention(policy models.RetentionPolicy) string {
+	var limits []string
+	if policy.MaxAge > 0 {
+		limits = append(limits, fmt.Sprintf("%d days", policy.MaxAge))
+	}
+	if policy.MaxSize > 0 {
+		limits = append(limits, fmt.Sprintf("%d MiB", policy.MaxSize))
+	}
+	if len(limits) == 0 {
+		return "keep all"
+	}
+	return strings.Join(limits, ", ")
+}
+
+// formatActionTime renders when a scheduled action runs, with the date only
+// when it is not today
+func formatActionTime(t time.Time) string {
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("Jan 2 15:04:05")
+}
+
+// scopeTag names the container tappmanager runs in and whose processes it
+// lists, empty when it runs on the host
+func scopeTag(scope models.Scope) string {
+	if !scope.Container {
+		return ""
+	}
+	if scope.HostProcesses {
+		return fmt.Sprintf("[%s container: host processes]", scope.Runtime)
+	}
+	return fmt.Sprintf("[%s container: its processes only]", scope.Runtime)
+}
+
+// renderUnavailable renders a grayed-out explanation for an unsupported capability
+func renderUnavailable(capability models.Capability) string {
+	return lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render(fmt.Sprintf("%s unavailable: %s", capability.Name, capability.Reason))
+}
@@ -3,20 +3,35 @@ package models
 import (
 	"fmt"
 	"runtime"
+	"strings"
 
+	"tappmanager/internal/models"
+	"tappmanager/internal/version"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // HelpModel handles the help view
 type HelpModel struct {
-	width  int
-	height int
+	capabilities *models.Capabilities
+	release      *models.ReleaseInfo // latest release, nil until checked
+	width        int
+	height       int
+	// filter narrows the key bindings shown to those matching it, applied
+	// as it is typed while filtering is set
+	filter    textinput.Model
+	filtering bool
+	offset    int // first content line shown
 }
 
 // NewHelpModel creates a new help model
-func NewHelpModel() *HelpModel {
-	return &HelpModel{}
+func NewHelpModel(capabilities *models.Capabilities) *HelpModel {
+	return &HelpModel{
+		capabilities: capabilities,
+		filter:       newTextInput(),
+	}
 }
 
 // Init initializes the model
@@ -30,11 +45,35 @@ func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.filtering {
+			m.updateFilter(msg)
+			break
+		}
+
 		switch msg.String() {
+		case "/":
+			m.filtering = true
+
+		case "up", "k":
+			m.offset--
+
+		case "down", "j":
+			m.offset++
+
+		case "pgup":
+			m.offset -= m.visibleLines()
+
+		case "pgdown":
+			m.offset += m.visibleLines()
+
+		case "home":
+			m.offset = 0
+
 		case "esc":
 			// Return to processes view
 			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
 		}
+		m.clampOffset()
 
 	case SwitchViewMsg:
 		// This will be handled by the main model
@@ -43,141 +82,205 @@ func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
 	return m, cmd
 }
 
+// updateFilter edits the filter as it is typed. Enter keeps the filter and
+// Esc clears it.
+func (m *HelpModel) updateFilter(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+
+	case "esc":
+		m.filtering = false
+		m.filter.SetValue("")
+		m.offset = 0
+
+	default:
+		if editInput(&m.filter, msg) {
+			m.offset = 0
+		}
+	}
+}
+
+// InputMode reports whether the filter is being typed
+func (m HelpModel) InputMode() InputMode {
+	if m.filtering {
+		return ModeSearch
+	}
+	return ModeNormal
+}
+
 // UpdateSize updates the model with new dimensions
 func (m HelpModel) UpdateSize(width, height int) HelpModel {
 	m.width = width
 	m.height = height
+	m.clampOffset()
 	return m
 }
 
+// visibleLines returns how many content lines fit in the view
+func (m HelpModel) visibleLines() int {
+	// Borders, padding, title, filter line, controls and the footer
+	return max(m.height-16, 1)
+}
+
+// clampOffset keeps the scroll position within the content
+func (m *HelpModel) clampOffset() {
+	last := max(len(m.contentLines())-m.visibleLines(), 0)
+	m.offset = max(min(m.offset, last), 0)
+}
+
 // View renders the help view
 func (m HelpModel) View() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true)
 
 	sectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("62")).
+		Foreground(colors.accent).
 		Bold(true)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("230")).
+		Foreground(colors.text).
 		Bold(true)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(colors.dim)
 
-	// Help content
 	content := titleStyle.Render("Terminal Process Manager - Help") + "\n\n"
-	
-	// OS-specific information
-	osName := runtime.GOOS
-	content += sectionStyle.Render(fmt.Sprintf("Running on: %s", osName)) + "\n\n"
-	
-	// Navigation
-	content += sectionStyle.Render("Navigation:") + "\n"
-	content += keyStyle.Render("P") + " - " + descStyle.Render("Switch to Processes view") + "\n"
-	content += keyStyle.Render("D") + " - " + descStyle.Render("Switch to Details view") + "\n"
-	content += keyStyle.Render("Ctrl+S") + " - " + descStyle.Render("Switch to Statistics view") + "\n"
-	content += keyStyle.Render("H") + " - " + descStyle.Render("Show this help") + "\n"
-	content += keyStyle.Render("E") + " - " + descStyle.Render("Switch to Settings view") + "\n"
-	
-	// OS-specific quit shortcuts
-	switch osName {
-	case "windows":
-		content += keyStyle.Render("Ctrl+Q") + " - " + descStyle.Render("Quit application") + "\n"
-		content += keyStyle.Render("Alt+F4") + " - " + descStyle.Render("Quit application") + "\n"
-	case "darwin":
-		content += keyStyle.Render("Cmd+Q") + " - " + descStyle.Render("Quit application") + "\n"
-		content += keyStyle.Render("Cmd+W") + " - " + descStyle.Render("Close current view") + "\n"
-	case "linux":
-		content += keyStyle.Render("Ctrl+D") + " - " + descStyle.Render("Quit application") + "\n"
-	}
-	content += keyStyle.Render("Q") + " - " + descStyle.Render("Quit application") + "\n"
-	content += keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n\n"
-
-	// Processes View
-	content += sectionStyle.Render("Processes View:") + "\n"
-	content += keyStyle.Render("↑/↓ or J/K") + " - " + descStyle.Render("Navigate up/down") + "\n"
-	content += keyStyle.Render("R") + " - " + descStyle.Render("Refresh process list") + "\n"
-	content += keyStyle.Render("Ctrl+K") + " - " + descStyle.Render("Kill selected process") + "\n"
-	content += keyStyle.Render("F") + " - " + descStyle.Render("Toggle system processes filter") + "\n"
-	content += keyStyle.Render("Ctrl+F") + " - " + descStyle.Render("Search processes (cycle through terms)") + "\n"
-	content += keyStyle.Render("Ctrl+Shift+F") + " - " + descStyle.Render("Clear search filter") + "\n"
-	content += keyStyle.Render("S") + " - " + descStyle.Render("Toggle system processes display") + "\n"
-	content += keyStyle.Render("Ctrl+R") + " - " + descStyle.Render("Reset all filters and refresh") + "\n"
-	content += keyStyle.Render("Ctrl+Shift+S") + " - " + descStyle.Render("Reset sort to default (CPU desc)") + "\n"
-	content += keyStyle.Render("O") + " - " + descStyle.Render("Sort by CPU usage") + "\n"
-	content += keyStyle.Render("M") + " - " + descStyle.Render("Sort by memory usage") + "\n"
-	content += keyStyle.Render("Ctrl+P") + " - " + descStyle.Render("Sort by PID") + "\n"
-	content += keyStyle.Render("N") + " - " + descStyle.Render("Sort by name") + "\n"
-	content += keyStyle.Render("T") + " - " + descStyle.Render("Sort by status") + "\n"
-	content += keyStyle.Render("U") + " - " + descStyle.Render("Sort by user") + "\n"
-	content += keyStyle.Render("Ctrl+T") + " - " + descStyle.Render("Sort by threads") + "\n"
-	content += keyStyle.Render("Ctrl+N") + " - " + descStyle.Render("Sort by nice value") + "\n"
-	content += keyStyle.Render("Enter") + " - " + descStyle.Render("View process details") + "\n\n"
-
-	// Details View
-	content += sectionStyle.Render("Details View:") + "\n"
-	content += keyStyle.Render("↑/↓") + " - " + descStyle.Render("Select previous/next process") + "\n"
-	content += keyStyle.Render("Ctrl+R") + " - " + descStyle.Render("Refresh process details") + "\n"
-	content += keyStyle.Render("Ctrl+K") + " - " + descStyle.Render("Kill selected process") + "\n"
-	content += keyStyle.Render("Ctrl+F") + " - " + descStyle.Render("Search processes") + "\n"
-	content += keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n\n"
-
-	// Statistics View
-	content += sectionStyle.Render("Statistics View:") + "\n"
-	content += keyStyle.Render("Ctrl+R") + " - " + descStyle.Render("Refresh statistics") + "\n"
-	content += keyStyle.Render("Ctrl+E") + " - " + descStyle.Render("Export statistics") + "\n"
-	content += keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n\n"
-
-	// Settings View
-	content += sectionStyle.Render("Settings View:") + "\n"
-	content += descStyle.Render("Configure refresh rate, filters, and display options") + "\n"
-	content += keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n\n"
-
-	// General
-	content += sectionStyle.Render("General:") + "\n"
-	content += keyStyle.Render("Arrow Keys") + " - " + descStyle.Render("Navigate") + "\n"
-	content += keyStyle.Render("Enter") + " - " + descStyle.Render("Select/Activate") + "\n"
-	content += keyStyle.Render("Tab") + " - " + descStyle.Render("Next field") + "\n"
-	content += keyStyle.Render("Esc") + " - " + descStyle.Render("Cancel/Back") + "\n\n"
 
-	// Process Management
-	content += sectionStyle.Render("Process Management:") + "\n"
-	content += descStyle.Render("• Real-time process monitoring") + "\n"
-	content += descStyle.Render("• Advanced filtering and sorting") + "\n"
-	content += descStyle.Render("• Process termination") + "\n"
-	content += descStyle.Render("• Detailed process information") + "\n"
-	content += descStyle.Render("• System statistics") + "\n"
-	content += descStyle.Render("• Data export and backup") + "\n\n"
-
-	// Features
-	content += sectionStyle.Render("Features:") + "\n"
-	content += descStyle.Render("• Cross-platform (macOS, Linux, Windows)") + "\n"
-	content += descStyle.Render("• Real-time monitoring with auto-refresh") + "\n"
-	content += descStyle.Render("• Advanced filtering by CPU, memory, status, user") + "\n"
-	content += descStyle.Render("• Process tree visualization") + "\n"
-	content += descStyle.Render("• Statistics and reporting") + "\n"
-	content += descStyle.Render("• Data persistence and backup") + "\n"
-	content += descStyle.Render("• Keyboard shortcuts for efficiency") + "\n\n"
+	switch {
+	case m.filtering:
+		content += keyStyle.Render("Filter: ") + m.filter.View() + "\n\n"
+	case m.filter.Value() != "":
+		content += keyStyle.Render("Filter: ") + m.filter.Value() + "\n\n"
+	default:
+		content += descStyle.Render("Press / to filter the shortcuts") + "\n\n"
+	}
 
-	// Version
-	content += sectionStyle.Render("Version:") + " " + descStyle.Render("1.0.0") + "\n"
+	lines := m.contentLines()
+	end := min(m.offset+m.visibleLines(), len(lines))
+	content += strings.Join(lines[m.offset:end], "\n")
+	if m.offset > 0 || end < len(lines) {
+		content += "\n" + descStyle.Render(fmt.Sprintf("(lines %d-%d of %d)", m.offset+1, end, len(lines)))
+	}
 
 	// Controls
 	controls := "\n" + sectionStyle.Render("Controls:") + "\n"
-	controls += keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n"
+	if m.filtering {
+		controls += keyStyle.Render("Enter") + " - " + descStyle.Render("Keep filter") + " | " +
+			keyStyle.Render("Esc") + " - " + descStyle.Render("Clear filter") + "\n"
+	} else {
+		controls += keyStyle.Render("/") + " - " + descStyle.Render("Filter") + " | " +
+			keyStyle.Render("↑/↓, PgUp/PgDn") + " - " + descStyle.Render("Scroll") + " | " +
+			keyStyle.Render("Esc") + " - " + descStyle.Render("Return to processes view") + "\n"
+	}
 
 	// Combine content and controls
 	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, controls)
-	
+
 	// Add borders and styling
 	styledContent := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(1, 2).
 		Render(fullContent)
 
 	return styledContent
 }
+
+// contentLines renders the scrollable part of the help one line per entry:
+// the key bindings of keySections matching the filter and, with no filter
+// set, the platform, features and version
+func (m HelpModel) contentLines() []string {
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(colors.accent).
+		Bold(true)
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(colors.text).
+		Bold(true)
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	term := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	var lines []string
+
+	if term == "" {
+		// OS-specific information
+		running := fmt.Sprintf("Running on: %s", runtime.GOOS)
+		if tag := scopeTag(m.capabilities.Scope); tag != "" {
+			running += " " + tag
+		}
+		lines = append(lines, sectionStyle.Render(running), "")
+	}
+
+	for _, section := range keySections() {
+		var matched []string
+		for _, b := range section.bindings {
+			if term != "" && !strings.Contains(strings.ToLower(b.keys+" "+b.description), term) {
+				continue
+			}
+			matched = append(matched, keyStyle.Render(b.keys)+" - "+descStyle.Render(b.description))
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		lines = append(lines, sectionStyle.Render(section.title+":"))
+		lines = append(lines, matched...)
+		if section.title == "General" && term == "" {
+			lines = append(lines, descStyle.Render("While [Search] or [Form] shows in the footer, keys go to the input, not shortcuts"))
+		}
+		lines = append(lines, "")
+	}
+
+	if term != "" {
+		if len(lines) == 0 {
+			return []string{descStyle.Render(fmt.Sprintf("No shortcuts match %q", m.filter.Value()))}
+		}
+		return lines[:len(lines)-1]
+	}
+
+	// Process Management
+	lines = append(lines,
+		sectionStyle.Render("Process Management:"),
+		descStyle.Render("• Real-time process monitoring"),
+		descStyle.Render("• Advanced filtering and sorting"),
+		descStyle.Render("• Process termination"),
+		descStyle.Render("• Detailed process information"),
+		descStyle.Render("• System statistics"),
+		descStyle.Render("• Data export and backup"),
+		"",
+	)
+
+	// Features
+	lines = append(lines,
+		sectionStyle.Render("Features:"),
+		descStyle.Render("• Cross-platform (macOS, Linux, Windows)"),
+		descStyle.Render("• Real-time monitoring with auto-refresh"),
+		descStyle.Render("• Advanced filtering by CPU, memory, status, user"),
+		descStyle.Render("• Process tree visualization"),
+		descStyle.Render("• Statistics and reporting"),
+		descStyle.Render("• Data persistence and backup"),
+		descStyle.Render("• Keyboard shortcuts for efficiency"),
+		"",
+	)
+
+	// Platform Capabilities
+	lines = append(lines, sectionStyle.Render("Platform Capabilities:"))
+	for _, capability := range m.capabilities.List() {
+		if capability.Supported {
+			lines = append(lines, keyStyle.Render(capability.Name)+" - "+descStyle.Render("supported"))
+		} else {
+			lines = append(lines, renderUnavailable(capability))
+		}
+	}
+	lines = append(lines, "")
+
+	// Version
+	lines = append(lines, sectionStyle.Render("Version:")+" "+descStyle.Render(version.Get().String()))
+	if m.release != nil && m.release.UpdateAvailable {
+		lines = append(lines, keyStyle.Render("Update available:")+" "+descStyle.Render(fmt.Sprintf("%s - %s", m.release.Version, m.release.URL)))
+	}
+	return lines
+}
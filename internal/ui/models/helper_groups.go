@@ -0,0 +1,106 @@
+package models
+
+import (
+	"strings"
+
+	"tappmanager/internal/models"
+)
+
+// isHelper reports whether proc is a helper process of a Chromium based
+// browser or Electron app: renderers, the GPU process, utilities and the
+// zygote are started with --type, and on macOS are named "... Helper"
+func isHelper(proc *models.ProcessInfo) bool {
+	for _, arg := range proc.Args {
+		if strings.HasPrefix(arg, "--type=") {
+			return true
+		}
+	}
+	return strings.Contains(proc.Name, " Helper")
+}
+
+// groupHelpers folds the helpers among the listed processes into the row of
+// the app they belong to: their nearest ancestor in the scan all that is not
+// a helper itself. The app's row is replaced by a copy adding up the CPU,
+// memory, threads and disk I/O of its helpers, and how many helpers each
+// app took in is returned by PID. Helpers whose app is not listed, for
+// example because it is filtered out, stay rows of their own.
+func groupHelpers(all, processes []*models.ProcessInfo) ([]*models.ProcessInfo, map[int32]int) {
+	byPID := make(map[int32]*models.ProcessInfo, len(all))
+	for _, proc := range all {
+		byPID[proc.PID] = proc
+	}
+	listed := make(map[int32]int, len(processes))
+	for i, proc := range processes {
+		listed[proc.PID] = i
+	}
+
+	// appOf returns the PID of the app proc belongs to, or zero when it has
+	// none; the depth bound guards against parent cycles
+	appOf := func(proc *models.ProcessInfo) int32 {
+		for range len(all) {
+			parent, ok := byPID[proc.PPID]
+			if !ok || parent.PID == proc.PID {
+				return 0
+			}
+			if !isHelper(parent) {
+				return parent.PID
+			}
+			proc = parent
+		}
+		return 0
+	}
+
+	grouped := make([]*models.ProcessInfo, len(processes))
+	copy(grouped, processes)
+	folded := make(map[int]bool)
+	helpers := make(map[int32]int)
+	for i, proc := range processes {
+		if !isHelper(proc) {
+			continue
+		}
+		j, ok := listed[appOf(proc)]
+		if !ok {
+			continue
+		}
+
+		app := grouped[j]
+		if helpers[app.PID] == 0 {
+			// Copy the app's row so the scan's own stays as it was
+			app = cloneProcess(app)
+			grouped[j] = app
+		}
+		app.CPU += proc.CPU
+		app.Memory += proc.Memory
+		app.MemoryBytes += proc.MemoryBytes
+		app.NumThreads += proc.NumThreads
+		if proc.IO != nil && app.IO != nil {
+			app.IO.ReadBytes += proc.IO.ReadBytes
+			app.IO.WriteBytes += proc.IO.WriteBytes
+			app.IO.ReadCount += proc.IO.ReadCount
+			app.IO.WriteCount += proc.IO.WriteCount
+		}
+		helpers[app.PID]++
+		folded[i] = true
+	}
+
+	if len(folded) == 0 {
+		return processes, nil
+	}
+	rows := make([]*models.ProcessInfo, 0, len(processes)-len(folded))
+	for i, proc := range grouped {
+		if !folded[i] {
+			rows = append(rows, proc)
+		}
+	}
+	return rows, helpers
+}
+
+// cloneProcess copies proc deeply enough for its figures to be changed
+func cloneProcess(proc *models.ProcessInfo) *models.ProcessInfo {
+	clone := *proc
+	if proc.IO != nil {
+		io := *proc.IO
+		clone.IO = &io
+	}
+	return &clone
+}
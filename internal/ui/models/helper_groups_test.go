@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+)
+
+// helperScan is a browser with two helpers, one started by the other, next
+// to processes unrelated to it
+func helperScan() []*models.ProcessInfo {
+	return []*models.ProcessInfo{
+		{PID: 1, PPID: 0, Name: "init", Username: "root", CPU: 0.1},
+		{PID: 100, PPID: 1, Name: "chrome", Username: "alice", CPU: 10, MemoryBytes: 100},
+		{PID: 101, PPID: 100, Name: "chrome", Username: "alice", CPU: 20, MemoryBytes: 200, Args: []string{"chrome", "--type=renderer"}},
+		{PID: 102, PPID: 101, Name: "chrome", Username: "bob", CPU: 30, MemoryBytes: 300, Args: []string{"chrome", "--type=gpu-process"}},
+		{PID: 200, PPID: 1, Name: "bash", Username: "alice", CPU: 1},
+	}
+}
+
+func TestGroupHelpersWithFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *models.ProcessFilter
+		rows    []int32
+		helpers map[int32]int
+		cpu     map[int32]float64
+	}{
+		{
+			name:    "no filter",
+			filter:  &models.ProcessFilter{ShowSystem: true},
+			rows:    []int32{1, 100, 200},
+			helpers: map[int32]int{100: 2},
+			cpu:     map[int32]float64{100: 60},
+		},
+		{
+			name:    "app and helpers listed",
+			filter:  &models.ProcessFilter{SearchTerm: "chrome", ShowSystem: true},
+			rows:    []int32{100},
+			helpers: map[int32]int{100: 2},
+			cpu:     map[int32]float64{100: 60},
+		},
+		{
+			name:    "app filtered out",
+			filter:  &models.ProcessFilter{Username: "bob", ShowSystem: true},
+			rows:    []int32{102},
+			helpers: nil,
+			cpu:     map[int32]float64{102: 30},
+		},
+	}
+
+	ps := services.NewProcessService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := helperScan()
+			filtered := ps.FilterProcesses(all, tt.filter)
+			rows, helpers := groupHelpers(all, filtered)
+
+			// Filtering must leave the full scan intact for grouping
+			for i, proc := range helperScan() {
+				if all[i] == nil || all[i].PID != proc.PID || all[i].CPU != proc.CPU {
+					t.Fatalf("scan changed at %d: got %+v, want PID %d with CPU %v", i, all[i], proc.PID, proc.CPU)
+				}
+			}
+
+			if len(rows) != len(tt.rows) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.rows))
+			}
+			for i, pid := range tt.rows {
+				if rows[i].PID != pid {
+					t.Errorf("row %d is PID %d, want %d", i, rows[i].PID, pid)
+				}
+				if cpu, ok := tt.cpu[pid]; ok && rows[i].CPU != cpu {
+					t.Errorf("PID %d has CPU %v, want %v", pid, rows[i].CPU, cpu)
+				}
+			}
+			if len(helpers) != len(tt.helpers) {
+				t.Fatalf("got helpers %v, want %v", helpers, tt.helpers)
+			}
+			for pid, n := range tt.helpers {
+				if helpers[pid] != n {
+					t.Errorf("PID %d took in %d helpers, want %d", pid, helpers[pid], n)
+				}
+			}
+		})
+	}
+}
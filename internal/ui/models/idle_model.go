@@ -0,0 +1,298 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// idleReloadInterval is how often the idle processes view reloads its list
+const idleReloadInterval = 5 * time.Second
+
+// IdleModel lists idle processes and kills the ones marked for cleanup
+type IdleModel struct {
+	idleService   *services.IdleService
+	processes     []models.IdleProcess
+	marked        map[int32]bool
+	selectedIndex int
+	offset        int
+	confirming    bool
+	statusMessage string
+	width         int
+	height        int
+}
+
+// NewIdleModel creates a new idle processes model
+func NewIdleModel(idleService *services.IdleService) *IdleModel {
+	return &IdleModel{
+		idleService: idleService,
+		marked:      make(map[int32]bool),
+	}
+}
+
+// Init initializes the model
+func (m IdleModel) Init() tea.Cmd {
+	return m.loadIdle()
+}
+
+// Update handles messages and updates the model
+func (m IdleModel) Update(msg tea.Msg) (IdleModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirming {
+			return m.updateConfirm(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+
+		case "down", "j":
+			if m.selectedIndex < len(m.processes)-1 {
+				m.selectedIndex++
+			}
+
+		case " ":
+			// Mark or unmark the selected process
+			if m.selectedIndex < len(m.processes) {
+				pid := m.processes[m.selectedIndex].PID
+				if m.marked[pid] {
+					delete(m.marked, pid)
+				} else {
+					m.marked[pid] = true
+				}
+			}
+
+		case "A":
+			// Mark every process, or clear the marks if all are marked
+			if len(m.marked) == len(m.processes) {
+				m.marked = make(map[int32]bool)
+			} else {
+				for _, proc := range m.processes {
+					m.marked[proc.PID] = true
+				}
+			}
+
+		case "x":
+			if len(m.marked) > 0 {
+				m.confirming = true
+			}
+
+		case "r":
+			cmd = m.loadIdle()
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case idleMsg:
+		m.processes = msg.Processes
+		// Forget marks on processes that are no longer idle
+		listed := make(map[int32]bool, len(m.processes))
+		for _, proc := range m.processes {
+			listed[proc.PID] = true
+		}
+		for pid := range m.marked {
+			if !listed[pid] {
+				delete(m.marked, pid)
+			}
+		}
+		m.selectedIndex = max(min(m.selectedIndex, len(m.processes)-1), 0)
+
+	case refreshTimerMsg:
+		cmd = m.loadIdle()
+
+	case idleKillMsg:
+		m.statusMessage = fmt.Sprintf("Killed %d of %d processes", len(msg.Killed), msg.Requested)
+		if msg.Error != nil {
+			m.statusMessage += fmt.Sprintf(": %v", msg.Error)
+		}
+		for _, pid := range msg.Killed {
+			delete(m.marked, pid)
+		}
+		cmd = m.loadIdle()
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	m.scrollToSelection()
+	return m, cmd
+}
+
+// updateConfirm handles the answer to the bulk kill confirmation
+func (m IdleModel) updateConfirm(msg tea.KeyMsg) (IdleModel, tea.Cmd) {
+	m.confirming = false
+	switch msg.String() {
+	case "y", "Y":
+		return m, m.killMarked()
+	default:
+		m.statusMessage = "Kill cancelled"
+		return m, nil
+	}
+}
+
+// UpdateSize updates the model with new dimensions
+func (m IdleModel) UpdateSize(width, height int) IdleModel {
+	m.width = width
+	m.height = height
+	m.scrollToSelection()
+	return m
+}
+
+// visibleRows returns how many processes fit in the view
+func (m IdleModel) visibleRows() int {
+	// Borders, padding, title, header, status and controls
+	return max(m.height-12, 1)
+}
+
+// scrollToSelection keeps the selected process within the visible rows
+func (m *IdleModel) scrollToSelection() {
+	visible := m.visibleRows()
+	if m.selectedIndex < m.offset {
+		m.offset = m.selectedIndex
+	} else if m.selectedIndex >= m.offset+visible {
+		m.offset = m.selectedIndex - visible + 1
+	}
+	m.offset = max(min(m.offset, len(m.processes)-visible), 0)
+}
+
+// InputMode reports whether a kill is waiting for confirmation
+func (m IdleModel) InputMode() InputMode {
+	if m.confirming {
+		return ModeForm
+	}
+	return ModeNormal
+}
+
+// View renders the idle processes view
+func (m IdleModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	content := titleStyle.Render(fmt.Sprintf("Idle Processes (no CPU or I/O for %s):", m.idleService.Threshold())) + "\n"
+	switch {
+	case !m.idleService.Enabled():
+		content += dimStyle.Render("Idle detection is off. Set idle_threshold in the config file to enable it.") + "\n"
+	case len(m.processes) == 0:
+		content += dimStyle.Render("None yet. Processes are watched from when tappmanager starts.") + "\n"
+	default:
+		content += headerStyle.Render(fmt.Sprintf("   %-8s %-20s %-12s %-10s %-10s %s", "PID", "Name", "User", "Idle For", "CPU Time", "Command")) + "\n"
+		content += m.renderRows(valueStyle, selectedStyle)
+	}
+
+	status := fmt.Sprintf("%d idle, %d marked", len(m.processes), len(m.marked))
+	if m.statusMessage != "" {
+		status += " | " + m.statusMessage
+	}
+	content += "\n" + dimStyle.Render(status) + "\n"
+
+	help := "↑/↓: select | space: mark | A: mark all | x: kill marked | r: reload | esc: back"
+	if m.confirming {
+		help = fmt.Sprintf("Kill %d marked processes? (y/n)", len(m.marked))
+	}
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render(help)
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(1, 2).
+		Render(fullContent)
+}
+
+// renderRows renders the visible idle processes
+func (m IdleModel) renderRows(valueStyle, selectedStyle lipgloss.Style) string {
+	end := min(m.offset+m.visibleRows(), len(m.processes))
+	// Borders and padding take 8 columns, the fixed columns 69
+	commandWidth := max(m.width-77, 10)
+
+	var b strings.Builder
+	for i := m.offset; i < end; i++ {
+		proc := m.processes[i]
+		mark := "[ ]"
+		if m.marked[proc.PID] {
+			mark = "[x]"
+		}
+		command := proc.Command
+		if runes := []rune(command); len(runes) > commandWidth {
+			command = string(runes[:commandWidth-3]) + "..."
+		}
+		line := fmt.Sprintf("%s %-8d %-20.20s %-12.12s %-10s %-10s %s",
+			mark, proc.PID, proc.Name, proc.Username,
+			formatUptime(time.Since(proc.IdleSince)), fmt.Sprintf("%.1fs", proc.CPUSeconds), command)
+		if i == m.selectedIndex {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(valueStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// killMarked kills the marked processes that are still idle
+func (m IdleModel) killMarked() tea.Cmd {
+	var targets []models.IdleProcess
+	for _, proc := range m.processes {
+		if m.marked[proc.PID] {
+			targets = append(targets, proc)
+		}
+	}
+	return func() tea.Msg {
+		killed, err := m.idleService.KillIdle(targets)
+		return idleKillMsg{Requested: len(targets), Killed: killed, Error: err}
+	}
+}
+
+// loadIdle reads the processes currently considered idle
+func (m IdleModel) loadIdle() tea.Cmd {
+	return func() tea.Msg {
+		return idleMsg{Processes: m.idleService.IdleProcesses(time.Now())}
+	}
+}
+
+// Messages
+type idleMsg struct {
+	Processes []models.IdleProcess
+}
+
+type idleKillMsg struct {
+	Requested int
+	Killed    []int32
+	Error     error
+}
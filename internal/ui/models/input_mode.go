@@ -0,0 +1,59 @@
+package models
+
+import (
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InputMode decides where keys go. In normal mode single letters are
+// shortcuts, including the global ones switching views; in the other modes
+// a view is taking typed text or a choice and gets every key, so typing
+// never triggers navigation.
+type InputMode int
+
+// Input modes
+const (
+	ModeNormal InputMode = iota
+	ModeSearch           // a search term is being typed
+	ModeForm             // a form, prompt or menu is open
+)
+
+// String names the mode for the footer
+func (mode InputMode) String() string {
+	switch mode {
+	case ModeSearch:
+		return "Search"
+	case ModeForm:
+		return "Form"
+	default:
+		return "Normal"
+	}
+}
+
+// passesInput reports whether key keeps its global meaning in every mode.
+// These are keys no input takes: Ctrl+C quits and Ctrl+O captures the
+// screen, forms included.
+func passesInput(key string) bool {
+	return key == "ctrl+c" || key == "ctrl+o"
+}
+
+// newTextInput returns a focused text input for a view to show after its
+// own label. The cursor does not blink, as blinking needs its messages
+// routed back to the input, and Ctrl+V is left to the terminal's paste for
+// the same reason.
+func newTextInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.KeyMap.Paste.SetEnabled(false)
+	input.Focus()
+	return input
+}
+
+// editInput passes msg to input and reports whether its text changed
+func editInput(input *textinput.Model, msg tea.KeyMsg) bool {
+	before := input.Value()
+	*input, _ = input.Update(msg)
+	return input.Value() != before
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"runtime"
+	"slices"
+)
+
+// keyBinding documents what a key does
+type keyBinding struct {
+	keys        string // as shown, e.g. "Ctrl+K" or "↑/↓"
+	description string
+	hint        string // short label offering the key in the footer, if any
+}
+
+// footerHintCount is how many hints the footer offers at most
+const footerHintCount = 5
+
+// keySection groups the key bindings of one view, or of every view
+type keySection struct {
+	title    string
+	view     ViewType // the view the bindings apply in, unless global
+	global   bool
+	bindings []keyBinding
+}
+
+// keySections lists every key binding by view, in the order the help view
+// shows them. It is the one place documenting the keys each view's Update
+// handles, so keep it in step with them. The footer offers the bindings
+// with a hint in the order listed, so list the most used first.
+func keySections() []keySection {
+	return []keySection{
+		{title: "Navigation", global: true, bindings: navigationBindings()},
+		{title: "Processes View", view: ViewProcesses, bindings: []keyBinding{
+			{"↑/↓ or J/K", "Navigate up/down", ""},
+			{"Enter", "View process details", "details"},
+			{"Mouse", "Click a row to select it, double-click for details, wheel to scroll, click a header to sort", ""},
+			{"R", "Refresh process list, also while paused", ""},
+			{"Ctrl+P", "Pause/resume auto-refresh so the table holds still", ""},
+			{"Ctrl+K", "Terminate selected process, killing it if it does not exit in time", "kill"},
+			{"Alt+K", "Kill selected process immediately", ""},
+			{"F", "Filter by CPU, memory, status and user, or hide processes", "filter"},
+			{"Ctrl+F", "Search processes as you type (Enter: apply, Esc: cancel)", "search"},
+			{"S", "Choose the sort field and direction", "sort"},
+			{"Shift+S", "Send a signal to selected process (SIGTERM, SIGSTOP, ...)", ""},
+			{"Shift+N", "Renice marked processes, or the selected one, to a preset: background 19, low 10, interactive 0, high -5", ""},
+			{"-", "Hide processes named like the selected one", ""},
+			{"Shift+L", "Apply, save or delete saved filters", ""},
+			{"Ctrl+Shift+F", "Clear search filter", ""},
+			{".", "Toggle system processes display", ""},
+			{"C", "Choose which optional columns are shown", ""},
+			{"T", "Toggle tree view of processes under their parents", ""},
+			{"←/→", "Sort by the column to the left/right; collapse/expand in tree view", ""},
+			{"Space", "Mark/unmark the selected process (collapse/expand in tree view)", ""},
+			{"O", "Group browser and Electron helper processes into their app's row", ""},
+			{"X", "Toggle security context column", ""},
+			{"I", "Toggle TTY, session and process group columns", ""},
+			{"V", "Toggle disk I/O columns", ""},
+			{"Shift+V", "Show all, hide or show only processes under WSL, Wine or a VM", ""},
+			{"M", "Toggle memory between percent and resident size (RSS)", ""},
+			{"Shift+I", "Show only the selected process's session", ""},
+			{"W", "Show only processes running from the current directory", ""},
+			{"Shift+W", "Show only processes running from the selected process's directory", ""},
+			{"Ctrl+R", "Reset all filters and marks and refresh", ""},
+			{"Ctrl+E", "Export process list to CSV", ""},
+			{"Y", "Copy the listed processes as TSV for spreadsheets", ""},
+			{"Ctrl+B", "Back up config and process list", ""},
+			{"Ctrl+Shift+S", "Reset sort to default (CPU desc)", ""},
+			{"Shift+F", "Follow selected process as the list re-sorts", ""},
+			{"*", "Pin selected process above the others (again to unpin)", ""},
+			{"B, 0-9", "Bookmark selected process in a slot (again to clear)", ""},
+			{"0-9", "Jump to bookmarked process", ""},
+			{"Shift+K", "Kill selected process at a time or after a duration", ""},
+			{"Ctrl+Z", "Suspend selected process, or resume it if paused", ""},
+			{"A", "Show scheduled actions and export schedules", ""},
+			{"Z", "Show idle processes", ""},
+			{"Shift+M", "Show which local processes connect to each other", ""},
+			{"Shift+U", "Show the security report: unknown binaries and risky privileges", ""},
+			{"L", "Tail log files associated with the selected process", ""},
+		}},
+		{title: "Details View", view: ViewDetails, bindings: []keyBinding{
+			{"↑/↓", "Select previous/next process", ""},
+			{"Tab/Shift+Tab", "Switch between overview, resources, open files, network connections, environment, threads, child processes, runtime and, for Java, JVM heap and GC", "switch tab"},
+			{"Ctrl+K", "Terminate selected process, killing it if it does not exit in time", "kill"},
+			{"Alt+K", "Kill selected process immediately", ""},
+			{"Shift+S", "Send a signal to selected process", ""},
+			{"R", "Refresh process details", "refresh"},
+			{"< / >", "Lower/raise nice value (raise/lower priority)", ""},
+			{"N", "Enter a nice value for selected process", "nice"},
+			{"F", "Search processes", ""},
+			{"A", "Toggle raw/parsed command line", ""},
+			{"[/]", "Select previous/next argument", ""},
+			{"Y", "Copy command line or selected argument", ""},
+			{"Shift+L", "Toggle CPU limit on the selected process", ""},
+			{"+/-", "Raise/lower the CPU limit", ""},
+			{"M", "Show/mask sensitive environment values", ""},
+			{"O", "Order threads by CPU or by thread ID on the threads tab", ""},
+			{"U", "Show the parent process", ""},
+			{"C", "List the child processes; on that tab ↑/↓ choose one and Enter shows it", ""},
+			{"T", "Run the runtime's action on the runtime tab: dump stacks, or start Node's inspector", ""},
+			{"PgUp/PgDn", "Scroll open files, connections, environment, threads or children", ""},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Statistics View", view: ViewStats, bindings: []keyBinding{
+			{"R", "Refresh statistics", "refresh"},
+			{"U", "Change per-user sort column", "user sort"},
+			{"Ctrl+E", "Export process list to CSV", "export"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Schedules View", view: ViewSchedule, bindings: []keyBinding{
+			{"↑/↓", "Select pending action", "select"},
+			{"C/Delete", "Cancel selected action", "cancel"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Idle Processes View", view: ViewIdle, bindings: []keyBinding{
+			{"Space", "Mark selected process", "mark"},
+			{"X", "Kill marked processes after confirmation", "kill marked"},
+			{"Shift+A", "Mark all processes (again to clear)", "mark all"},
+			{"R", "Reload idle processes", "reload"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Dependencies View", view: ViewDependencies, bindings: []keyBinding{
+			{"↑/↓, PgUp/PgDn", "Scroll the map", "scroll"},
+			{"R", "Reload the map", "reload"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Security View", view: ViewSecurity, bindings: []keyBinding{
+			{"↑/↓", "Select flagged process", ""},
+			{"Tab", "Switch between unknown binaries and privileges", "switch list"},
+			{"A", "Add the selected binary's hash to the allowlist", "allow"},
+			{"R", "Reload the allowlist file, or audit privileges again", "reload"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Logs View", view: ViewLogs, bindings: []keyBinding{
+			{"↑/↓, PgUp/PgDn", "Scroll the log", ""},
+			{"F", "Toggle following new lines", "follow"},
+			{"Home/End", "Jump to the first line / follow the end", "first/last"},
+			{"Tab", "Show the next matching log file", "next file"},
+			{"R", "Reload the log", "reload"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Diagnostics View", view: ViewDiagnostics, bindings: []keyBinding{
+			{"R", "Refresh diagnostics", "refresh"},
+			{"X", "Preview which stored files pruning would delete", "prune preview"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Help View", view: ViewHelp, bindings: []keyBinding{
+			{"/", "Filter the shortcuts as you type (Enter: keep, Esc: clear)", "filter"},
+			{"↑/↓, PgUp/PgDn", "Scroll the help", "scroll"},
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "Settings View", view: ViewSettings, bindings: []keyBinding{
+			{"Esc", "Return to processes view", "back"},
+		}},
+		{title: "General", global: true, bindings: []keyBinding{
+			{"Arrow Keys", "Navigate", ""},
+			{"Enter", "Select/Activate", ""},
+			{"Tab", "Next field", ""},
+			{"Ctrl+O", "Capture the screen to text files in the data directory", ""},
+			{"Esc", "Cancel/Back", ""},
+		}},
+	}
+}
+
+// navigationBindings are the keys switching views and quitting, including
+// the platform's own quit keys
+func navigationBindings() []keyBinding {
+	bindings := []keyBinding{
+		{"P", "Switch to Processes view", ""},
+		{"D", "Switch to Details view", ""},
+		{"Ctrl+S", "Switch to Statistics view", ""},
+		{"H", "Show this help", "help"},
+		{"E", "Switch to Settings view", ""},
+		{"G", "Switch to Diagnostics view", ""},
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		bindings = append(bindings, keyBinding{"Ctrl+Q", "Quit application", ""}, keyBinding{"Alt+F4", "Quit application", ""})
+	case "darwin":
+		bindings = append(bindings, keyBinding{"Cmd+Q", "Quit application", ""}, keyBinding{"Cmd+W", "Close current view", ""})
+	case "linux":
+		bindings = append(bindings, keyBinding{"Ctrl+D", "Quit application", ""})
+	}
+	return append(bindings,
+		keyBinding{"Q", "Quit application", "quit"},
+		keyBinding{"Esc", "Return to processes view", ""},
+	)
+}
+
+// Hints for keys pending input, in place of the view's shortcuts
+var (
+	searchHints = []keyBinding{
+		{"Enter", "", "apply"},
+		{"Esc", "", "cancel"},
+	}
+	formHints = []keyBinding{
+		{"Enter", "", "confirm"},
+		{"Esc", "", "cancel"},
+	}
+	confirmActionHints = []keyBinding{
+		{"T", "", "confirm"},
+		{"any other key", "", "cancel"},
+	}
+	bookmarkHints = []keyBinding{
+		{"0-9", "", "bookmark slot"},
+		{"any other key", "", "cancel"},
+	}
+	treeHints = []keyBinding{
+		{"←/→", "", "collapse/expand"},
+	}
+)
+
+// viewHints returns the footer hints for view: extra first, then its own
+// bindings with a hint and then the global ones, up to footerHintCount
+func viewHints(view ViewType, extra ...keyBinding) []keyBinding {
+	var own, global []keyBinding
+	for _, section := range keySections() {
+		if !section.global && section.view != view {
+			continue
+		}
+		for _, b := range section.bindings {
+			switch {
+			case b.hint == "":
+			case section.global:
+				global = append(global, b)
+			default:
+				own = append(own, b)
+			}
+		}
+	}
+
+	hints := slices.Concat(extra, own, global)
+	return hints[:min(len(hints), footerHintCount)]
+}
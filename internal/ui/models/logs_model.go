@@ -0,0 +1,288 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logTailInterval is how often the open log file is re-read
+const logTailInterval = time.Second
+
+// logTailLines is how many lines of the log file are kept for scrolling
+const logTailLines = 1000
+
+// logHighlight is a compiled highlight rule
+type logHighlight struct {
+	pattern *regexp.Regexp
+	style   lipgloss.Style
+}
+
+// LogsModel tails the log files associated with a process name
+type LogsModel struct {
+	logService  *services.LogService
+	highlights  []logHighlight
+	ruleErrors  []string
+	processName string
+	files       []string
+	fileIndex   int
+	lines       []string
+	offset      int
+	follow      bool
+	err         error
+	width       int
+	height      int
+}
+
+// NewLogsModel creates a new logs model, compiling the highlight rules
+func NewLogsModel(logService *services.LogService, highlights []models.LogHighlight) *LogsModel {
+	m := &LogsModel{
+		logService: logService,
+		follow:     true,
+	}
+
+	for _, rule := range highlights {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			m.ruleErrors = append(m.ruleErrors, fmt.Sprintf("invalid highlight pattern %q", rule.Pattern))
+			continue
+		}
+		m.highlights = append(m.highlights, logHighlight{
+			pattern: pattern,
+			style:   lipgloss.NewStyle().Foreground(lipgloss.Color(rule.Color)),
+		})
+	}
+
+	return m
+}
+
+// Init initializes the model
+func (m LogsModel) Init() tea.Cmd {
+	if m.processName == "" {
+		return nil
+	}
+	return m.loadLogs()
+}
+
+// Update handles messages and updates the model
+func (m LogsModel) Update(msg tea.Msg) (LogsModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.follow = false
+			m.offset--
+
+		case "down", "j":
+			m.offset++
+
+		case "pgup":
+			m.follow = false
+			m.offset -= m.visibleLines()
+
+		case "pgdown":
+			m.offset += m.visibleLines()
+
+		case "home":
+			m.follow = false
+			m.offset = 0
+
+		case "end":
+			m.follow = true
+
+		case "f":
+			m.follow = !m.follow
+
+		case "tab":
+			// Cycle through the files matched for the process
+			if len(m.files) > 1 {
+				m.fileIndex = (m.fileIndex + 1) % len(m.files)
+				m.lines = nil
+				m.follow = true
+				cmd = m.loadLogs()
+			}
+
+		case "r":
+			cmd = m.loadLogs()
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case OpenLogsMsg:
+		m.processName = msg.ProcessName
+		m.files = nil
+		m.fileIndex = 0
+		m.lines = nil
+		m.follow = true
+		m.err = nil
+		cmd = m.Init()
+
+	case logsMsg:
+		// Ignore reads of a file that is no longer shown
+		if msg.ProcessName != m.processName {
+			break
+		}
+		m.files = msg.Files
+		m.fileIndex = min(m.fileIndex, max(len(m.files)-1, 0))
+		m.lines = msg.Lines
+		m.err = msg.Error
+
+	case refreshTimerMsg:
+		if m.processName != "" {
+			cmd = m.loadLogs()
+		}
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	m.clampOffset()
+	return m, cmd
+}
+
+// UpdateSize updates the model with new dimensions
+func (m LogsModel) UpdateSize(width, height int) LogsModel {
+	m.width = width
+	m.height = height
+	m.clampOffset()
+	return m
+}
+
+// visibleLines returns how many log lines fit in the view
+func (m LogsModel) visibleLines() int {
+	// Borders, title, file line and controls
+	return max(m.height-10, 1)
+}
+
+// clampOffset keeps the scroll position within the log, pinning it to the
+// end while following
+func (m *LogsModel) clampOffset() {
+	last := max(len(m.lines)-m.visibleLines(), 0)
+	if m.follow || m.offset >= last {
+		m.offset = last
+		return
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the logs view
+func (m LogsModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	content := titleStyle.Render(fmt.Sprintf("Logs: %s", m.processName)) + "\n"
+
+	switch {
+	case m.err != nil:
+		content += valueStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+	case !m.logService.HasLogs(m.processName):
+		content += valueStyle.Render(fmt.Sprintf("No log files are associated with %s. Add paths or globs under log_files in the config file.", m.processName)) + "\n"
+	case len(m.files) == 0:
+		content += valueStyle.Render("No log files match the configured paths") + "\n"
+	default:
+		follow := "off"
+		if m.follow {
+			follow = "on"
+		}
+		content += labelStyle.Render("File:") + " " + valueStyle.Render(fmt.Sprintf("%s (%d/%d)", m.files[m.fileIndex], m.fileIndex+1, len(m.files))) +
+			"  " + labelStyle.Render("Follow:") + " " + valueStyle.Render(follow) + "\n"
+		content += m.renderLines()
+	}
+
+	for _, ruleError := range m.ruleErrors {
+		content += labelStyle.Render("Warning:") + " " + valueStyle.Render(ruleError) + "\n"
+	}
+
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render("↑/↓ PgUp/PgDn: scroll | f: follow | tab: next file | r: reload | esc: back")
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(0, 1).
+		Render(fullContent)
+}
+
+// renderLines renders the visible log lines, colored by the first matching highlight rule
+func (m LogsModel) renderLines() string {
+	if len(m.lines) == 0 {
+		return lipgloss.NewStyle().Foreground(colors.dim).Render("(empty)") + "\n"
+	}
+
+	lineWidth := m.width - 8
+	end := min(m.offset+m.visibleLines(), len(m.lines))
+
+	var b strings.Builder
+	for _, line := range m.lines[m.offset:end] {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if runes := []rune(line); lineWidth > 0 && len(runes) > lineWidth {
+			line = string(runes[:lineWidth])
+		}
+		for _, rule := range m.highlights {
+			if rule.pattern.MatchString(line) {
+				line = rule.style.Render(line)
+				break
+			}
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// loadLogs resolves the process's log files and reads the end of the current one
+func (m LogsModel) loadLogs() tea.Cmd {
+	name := m.processName
+	fileIndex := m.fileIndex
+	return func() tea.Msg {
+		files, err := m.logService.LogFiles(name)
+		if err != nil || len(files) == 0 {
+			return logsMsg{ProcessName: name, Error: err}
+		}
+
+		fileIndex = min(fileIndex, len(files)-1)
+		lines, err := m.logService.TailFile(files[fileIndex], logTailLines)
+		return logsMsg{ProcessName: name, Files: files, Lines: lines, Error: err}
+	}
+}
+
+// Messages
+
+// OpenLogsMsg opens the logs view for a process name
+type OpenLogsMsg struct {
+	ProcessName string
+}
+
+type logsMsg struct {
+	ProcessName string
+	Files       []string
+	Lines       []string
+	Error       error
+}
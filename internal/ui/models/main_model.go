@@ -1,10 +1,17 @@
 package models
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"tappmanager/internal/models"
 	"tappmanager/internal/services"
 	"tappmanager/internal/storage"
+	"tappmanager/internal/version"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,53 +26,225 @@ const (
 	ViewStats
 	ViewSettings
 	ViewHelp
+	ViewDiagnostics
+	ViewLogs
+	ViewSchedule
+	ViewIdle
+	ViewDependencies
+	ViewSecurity
 )
 
+// collectorInterval is how often the shared collector checks whether the
+// current view is due for a refresh; it is also the shortest allowed interval
+const collectorInterval = time.Second
+
+// Refresh intervals used when the config leaves a view's interval unset
+const (
+	defaultProcessesRefresh = 2 * time.Second
+	defaultDetailsRefresh   = 3 * time.Second
+	defaultStatsRefresh     = 5 * time.Second
+)
+
+// headerHeight and footerHeight are the lines the bordered header and
+// footer take around the current view
+const (
+	headerHeight = 3
+	footerHeight = 3
+)
+
+// noticeDuration is how long the outcome of an operation stays in the footer
+const noticeDuration = 5 * time.Second
+
+// viewNames maps the view names accepted at startup to their views
+var viewNames = map[string]ViewType{
+	"processes":   ViewProcesses,
+	"details":     ViewDetails,
+	"stats":       ViewStats,
+	"settings":    ViewSettings,
+	"help":        ViewHelp,
+	"diagnostics": ViewDiagnostics,
+}
+
+// IsView reports whether name is a view accepted at startup
+func IsView(name string) bool {
+	_, ok := viewNames[name]
+	return ok
+}
+
+// StartupOptions adjust the initial state of the UI, typically from command-line flags
+type StartupOptions struct {
+	View   string                // processes, details, stats, settings, help or diagnostics
+	Filter *models.ProcessFilter // initial process filter, nil for none
+}
+
 // MainModel is the root model for the application
 type MainModel struct {
-	storage        storage.Storage
-	processService *services.ProcessService
-	currentView    ViewType
-	processes      *ProcessesModel
-	details        *DetailsModel
-	stats          *StatsModel
-	settings       *SettingsModel
-	help           *HelpModel
-	width          int
-	height         int
-	quitting       bool
+	storage         storage.Storage
+	processService  *services.ProcessService
+	historyService  *services.HistoryService
+	systemService   *services.SystemService
+	updateService   *services.UpdateService // nil unless update checks are enabled
+	scheduler       *services.Scheduler
+	budgetService   *services.BudgetService
+	limiter         *services.CPULimiter
+	idleService     *services.IdleService
+	listenerService *services.ListenerService
+	hashChecker     *services.HashChecker
+	exportScheduler *services.ExportScheduler
+	capabilities    *models.Capabilities
+	daemonPID       int // daemon supplying processes and history at startup, 0 if none
+	currentView     ViewType
+	processes       *ProcessesModel
+	details         *DetailsModel
+	stats           *StatsModel
+	settings        *SettingsModel
+	help            *HelpModel
+	diagnostics     *DiagnosticsModel
+	logs            *LogsModel
+	schedule        *ScheduleModel
+	idle            *IdleModel
+	dependencies    *DependenciesModel
+	security        *SecurityModel
+	lastRefresh     map[ViewType]time.Time
+	width           int
+	height          int
+	notice          string    // outcome of the last kill, signal, export or backup
+	noticeFailed    bool      // whether notice reports an error
+	noticeAt        time.Time // when notice was set, to expire it
+	quitting        bool
 }
 
 // NewMainModel creates a new main model
-func NewMainModel(storage storage.Storage, processService *services.ProcessService) *MainModel {
+func NewMainModel(storage storage.Storage, processService *services.ProcessService, historyService *services.HistoryService, systemService *services.SystemService, capabilityService *services.CapabilityService, diagnosticsService *services.DiagnosticsService) *MainModel {
+	config, err := storage.LoadConfig()
+	if err != nil {
+		config = models.NewAppConfig()
+	}
+	applyTheme(config.Theme)
+	capabilities := capabilityService.GetCapabilities()
+	historyService.SetLimits(uint64(max(config.HistoryBudget, 0))*1024, time.Duration(config.HistoryRetention)*time.Second)
+
+	var updateService *services.UpdateService
+	if config.CheckUpdates {
+		updateService = services.NewUpdateService()
+	}
+
+	scheduler := services.NewScheduler(processService)
+	budgetService := services.NewBudgetService(processService, historyService, config.Budgets)
+	limiter := services.NewCPULimiter()
+	idleService := services.NewIdleService(processService, time.Duration(max(config.IdleThreshold, 0))*time.Second)
+	listenerService := services.NewListenerService(processService, time.Duration(max(config.ListenerInterval, 0))*time.Second)
+	hashChecker := services.NewHashChecker(config.HashAllowlist)
+	exportScheduler := services.NewExportScheduler(processService, config.ExportSchedules)
+
 	return &MainModel{
-		storage:        storage,
-		processService: processService,
-		currentView:    ViewProcesses,
-		processes:      NewProcessesModel(processService),
-		details:        NewDetailsModel(processService),
-		stats:          NewStatsModel(processService),
-		settings:       NewSettingsModel(storage),
-		help:           NewHelpModel(),
-		quitting:       false,
+		storage:         storage,
+		processService:  processService,
+		historyService:  historyService,
+		systemService:   systemService,
+		updateService:   updateService,
+		scheduler:       scheduler,
+		budgetService:   budgetService,
+		limiter:         limiter,
+		idleService:     idleService,
+		listenerService: listenerService,
+		hashChecker:     hashChecker,
+		exportScheduler: exportScheduler,
+		capabilities:    capabilities,
+		currentView:     ViewProcesses,
+		processes:       NewProcessesModel(processService, scheduler, storage, capabilities, config),
+		details:         NewDetailsModel(processService, limiter, config),
+		stats:           NewStatsModel(processService, historyService, budgetService, systemService, config, capabilities),
+		settings:        NewSettingsModel(storage),
+		help:            NewHelpModel(capabilities),
+		diagnostics:     NewDiagnosticsModel(diagnosticsService),
+		logs:            NewLogsModel(services.NewLogService(config.LogFiles), config.LogHighlights),
+		schedule:        NewScheduleModel(scheduler, exportScheduler),
+		idle:            NewIdleModel(idleService),
+		dependencies:    NewDependenciesModel(processService),
+		security:        NewSecurityModel(processService, hashChecker),
+		lastRefresh:     make(map[ViewType]time.Time),
+		quitting:        false,
 	}
 }
 
+// Close removes all CPU limits so no process is left stopped.
+// Quitting calls it, and it must also be called after the program exits
+// in case it ended some other way; calling it again is harmless.
+func (m *MainModel) Close() {
+	m.limiter.StopAll()
+}
+
+// SetDaemon records that processes and history came from the daemon with
+// pid at startup, so the footer can say when it is no longer reachable
+func (m *MainModel) SetDaemon(pid int) {
+	m.daemonPID = pid
+}
+
+// ApplyStartupOptions sets the initial view and process filter.
+// It must be called before the program starts.
+func (m *MainModel) ApplyStartupOptions(options StartupOptions) error {
+	if options.View != "" {
+		view, ok := viewNames[options.View]
+		if !ok {
+			return fmt.Errorf("unknown view: %s", options.View)
+		}
+		m.currentView = view
+	}
+
+	if options.Filter != nil {
+		*m.processes.filter = *options.Filter
+	}
+
+	return nil
+}
+
 // Init initializes the model
 func (m MainModel) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.processes.Init(),
 		m.details.Init(),
 		m.stats.Init(),
 		m.settings.Init(),
 		m.help.Init(),
-	)
+		m.recordLoad(),
+		m.scheduleCollectorTick(),
+		m.checkForUpdate(),
+	}
+
+	// Background work runs on its own ticks even while attached to a
+	// daemon, each checking whether it is still attached, so the UI takes
+	// over whenever the daemon goes away
+	cmds = append(cmds, m.pruneStorage())
+	if m.budgetService.HasBudgets() {
+		cmds = append(cmds, m.checkBudgets())
+	}
+	if m.idleService.Enabled() {
+		cmds = append(cmds, m.sampleIdle())
+	}
+	if m.listenerService.Enabled() {
+		cmds = append(cmds, m.sampleListeners())
+	}
+	if m.hashChecker.Enabled() {
+		cmds = append(cmds, m.checkHashes())
+	}
+	if m.exportScheduler.HasSchedules() {
+		cmds = append(cmds, m.scheduleExports())
+	}
+
+	// Diagnostics only poll while visible, which may be from the start
+	if m.currentView == ViewDiagnostics {
+		cmds = append(cmds, m.diagnostics.Init())
+	}
+
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the model
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	previousView := m.currentView
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -77,11 +256,24 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		*m.stats = m.stats.UpdateSize(msg.Width, msg.Height)
 		*m.settings = m.settings.UpdateSize(msg.Width, msg.Height)
 		*m.help = m.help.UpdateSize(msg.Width, msg.Height)
+		*m.diagnostics = m.diagnostics.UpdateSize(msg.Width, msg.Height)
+		*m.logs = m.logs.UpdateSize(msg.Width, msg.Height)
+		*m.schedule = m.schedule.UpdateSize(msg.Width, msg.Height)
+		*m.idle = m.idle.UpdateSize(msg.Width, msg.Height)
+		*m.dependencies = m.dependencies.UpdateSize(msg.Width, msg.Height)
+		*m.security = m.security.UpdateSize(msg.Width, msg.Height)
 
 	case tea.KeyMsg:
+		// Keys go to a view taking input, apart from the few no input takes
+		if m.inputMode() != ModeNormal && !passesInput(msg.String()) {
+			break
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q", "Q", "ctrl+q", "alt+f4", "cmd+q", "ctrl+d":
+			// Continue limited processes before the program exits
 			m.quitting = true
+			m.Close()
 			return m, tea.Quit
 
 		case "esc":
@@ -98,7 +290,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 
 		case "d", "D":
+			// Show the process selected in the processes view
 			m.currentView = ViewDetails
+			if i := m.processes.selectedIndex; i < len(m.processes.processes) {
+				m.details.ShowProcess(m.processes.processes[i].PID)
+			}
 			cmd = m.details.Init()
 			cmds = append(cmds, cmd)
 
@@ -117,6 +313,27 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.settings.Init()
 			cmds = append(cmds, cmd)
 
+		case "g", "G":
+			m.currentView = ViewDiagnostics
+			cmd = m.diagnostics.Init()
+			cmds = append(cmds, cmd)
+
+		case "ctrl+e":
+			// Export the listed processes from the views showing them
+			if m.currentView == ViewProcesses || m.currentView == ViewStats {
+				cmds = append(cmds, m.exportProcesses())
+			}
+
+		case "ctrl+b":
+			if m.currentView == ViewProcesses {
+				cmds = append(cmds, m.createBackup())
+			}
+
+		case "ctrl+o":
+			// Capture the screen as shown, for documentation and bug reports
+			name := strings.ToLower(strings.ReplaceAll(viewTitles[m.currentView], " ", "_"))
+			cmds = append(cmds, captureView(m.storage, name, m.View()))
+
 		case "cmd+w":
 			// macOS specific - close current view (go back to processes)
 			if m.currentView != ViewProcesses {
@@ -126,6 +343,127 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case killProcessMsg:
+		// Report the outcome whichever view is showing when it arrives
+		cmds = append(cmds, m.notify(msg.Status(), msg.Error != nil))
+
+	case signalMsg:
+		cmds = append(cmds, m.notify(msg.Status(), msg.Error != nil))
+
+	case exportMsg:
+		if msg.Error != nil {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Export failed: %v", msg.Error), true))
+		} else {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Exported %d processes to %s", msg.Count, msg.Path), false))
+		}
+
+	case captureMsg:
+		if msg.Error != nil {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Capture failed: %v", msg.Error), true))
+		} else {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Captured view to %s (styled: %s)", msg.TextPath, filepath.Base(msg.StyledPath)), false))
+		}
+
+	case backupMsg:
+		if msg.Error != nil {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Backup failed: %v", msg.Error), true))
+		} else {
+			cmds = append(cmds, m.notify("Backup created", false))
+		}
+
+	case noticeExpiredMsg:
+		// A newer notice keeps its own time
+		if msg.At.Equal(m.noticeAt) {
+			m.notice = ""
+		}
+
+	case loadSampleMsg:
+		// Keep the load history ticking regardless of the current view
+		cmds = append(cmds, m.scheduleLoadSample())
+
+	case pruneMsg:
+		// Keep pruning in the background regardless of the current view
+		if msg.Error != nil {
+			cmds = append(cmds, m.notify(fmt.Sprintf("Pruning stored files failed: %v", msg.Error), true))
+		}
+		cmds = append(cmds, m.schedulePrune())
+
+	case OpenLogsMsg:
+		// The logs view picks up the process name below
+		m.currentView = ViewLogs
+
+	case updateCheckMsg:
+		// Failed checks are not worth interrupting the user for
+		if msg.Err == nil {
+			m.help.release = msg.Release
+		}
+
+	case budgetMsg:
+		// Report budgets exceeded today in the processes view
+		for _, status := range msg.Exceeded {
+			m.processes.statusMessage = fmt.Sprintf("Budget exceeded: %s used %.2f of %.2f CPU-hours today", status.Budget.Name, status.UsedCPUHours, status.Budget.CPUHours)
+			if len(status.Killed) > 0 {
+				m.processes.statusMessage += fmt.Sprintf(", killed %d processes", len(status.Killed))
+			}
+		}
+		cmds = append(cmds, m.scheduleBudgetCheck())
+
+	case idleSampleMsg:
+		// Keep watching for idle processes regardless of the current view
+		cmds = append(cmds, m.scheduleIdleSample())
+
+	case listenerMsg:
+		// Report services starting or stopping listening in the processes view
+		for _, change := range msg.Changes {
+			m.processes.statusMessage = listenerStatus(change)
+		}
+		cmds = append(cmds, m.scheduleListenerSample())
+
+	case hashCheckMsg:
+		// Report binaries not in the allowlist in the processes view
+		if msg.Error != nil {
+			m.processes.statusMessage = msg.Error.Error()
+		}
+		switch len(msg.Flagged) {
+		case 0:
+		case 1:
+			binary := msg.Flagged[0]
+			m.processes.statusMessage = fmt.Sprintf("Unknown binary: %s (PID %d) runs %s, see Shift+U", binary.Name, binary.PID, binary.Executable)
+		default:
+			m.processes.statusMessage = fmt.Sprintf("%d processes run binaries not in the allowlist, see Shift+U", len(msg.Flagged))
+		}
+		cmds = append(cmds, m.scheduleHashCheck())
+
+	case scheduledExportsMsg:
+		// Report failed scheduled exports; successful ones show in the schedules view
+		for _, status := range msg.Statuses {
+			if status.Error != "" {
+				cmds = append(cmds, m.notify(fmt.Sprintf("Scheduled export %s failed: %s", status.Schedule.Name, status.Error), true))
+			}
+		}
+		cmds = append(cmds, m.scheduleExports())
+
+	case scheduledActionsMsg:
+		// Report scheduled actions in the processes view and show their effect
+		for _, action := range msg.Actions {
+			m.processes.statusMessage = fmt.Sprintf("Scheduled %s of %s (%d): %s", action.Action, action.Name, action.PID, action.State)
+		}
+		if m.currentView == ViewProcesses {
+			cmds = append(cmds, m.processes.refreshProcesses())
+		}
+
+	case collectorTickMsg:
+		// Scheduled actions run regardless of the current view
+		cmds = append(cmds, m.runScheduled(msg.At))
+		m.schedule.attached = m.processService.Attached()
+
+		// Only the visible view is refreshed, at its own cadence
+		if interval := m.viewRefreshInterval(m.currentView); interval > 0 && msg.At.Sub(m.lastRefresh[m.currentView]) >= interval {
+			m.lastRefresh[m.currentView] = msg.At
+			cmds = append(cmds, func() tea.Msg { return refreshTimerMsg{} })
+		}
+		cmds = append(cmds, m.scheduleCollectorTick())
+
 	case SwitchViewMsg:
 		// Handle view switching from sub-models
 		m.currentView = msg.View
@@ -133,6 +471,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case ViewProcesses:
 			cmd = m.processes.Init()
 		case ViewDetails:
+			m.details.ShowProcess(msg.PID)
 			cmd = m.details.Init()
 		case ViewStats:
 			cmd = m.stats.Init()
@@ -140,10 +479,27 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.settings.Init()
 		case ViewHelp:
 			cmd = m.help.Init()
+		case ViewDiagnostics:
+			cmd = m.diagnostics.Init()
+		case ViewLogs:
+			cmd = m.logs.Init()
+		case ViewSchedule:
+			cmd = m.schedule.Init()
+		case ViewIdle:
+			cmd = m.idle.Init()
+		case ViewDependencies:
+			cmd = m.dependencies.Init()
+		case ViewSecurity:
+			cmd = m.security.Init()
 		}
 		cmds = append(cmds, cmd)
 	}
 
+	// Switching views reloads the new view, so restart its refresh cadence
+	if m.currentView != previousView {
+		m.lastRefresh[m.currentView] = time.Now()
+	}
+
 	// Update the current view
 	switch m.currentView {
 	case ViewProcesses:
@@ -165,6 +521,30 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewHelp:
 		*m.help, cmd = m.help.Update(msg)
 		cmds = append(cmds, cmd)
+
+	case ViewDiagnostics:
+		*m.diagnostics, cmd = m.diagnostics.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewLogs:
+		*m.logs, cmd = m.logs.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewSchedule:
+		*m.schedule, cmd = m.schedule.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewIdle:
+		*m.idle, cmd = m.idle.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewDependencies:
+		*m.dependencies, cmd = m.dependencies.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewSecurity:
+		*m.security, cmd = m.security.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -197,14 +577,24 @@ func (m MainModel) View() string {
 		content = m.settings.View()
 	case ViewHelp:
 		content = m.help.View()
+	case ViewDiagnostics:
+		content = m.diagnostics.View()
+	case ViewLogs:
+		content = m.logs.View()
+	case ViewSchedule:
+		content = m.schedule.View()
+	case ViewIdle:
+		content = m.idle.View()
+	case ViewDependencies:
+		content = m.dependencies.View()
+	case ViewSecurity:
+		content = m.security.View()
 	}
 
 	// Create footer
 	footer := m.renderFooter()
 
 	// Calculate available height for content
-	headerHeight := 3
-	footerHeight := 3
 	availableHeight := m.height - headerHeight - footerHeight
 	
 	// Ensure content fits in available height
@@ -221,58 +611,476 @@ func (m MainModel) View() string {
 // renderHeader renders the application header
 func (m MainModel) renderHeader() string {
 	title := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true).
 		Render("Terminal Process Manager")
 
 	nav := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("[P]rocesses [D]etails [S]tats [E]ettings [H]elp [Q]uit")
+		Foreground(colors.dim).
+		Render("[P]rocesses [D]etails [S]tats [E]ettings Dia[G]nostics [H]elp [Q]uit")
 
 	header := lipgloss.JoinHorizontal(lipgloss.Center, title, "  ", nav)
+	// Inside a container, say so where it fits beside the borders and padding
+	if tag := scopeTag(m.capabilities.Scope); tag != "" && lipgloss.Width(header)+2+lipgloss.Width(tag)+4 <= m.width {
+		header = lipgloss.JoinHorizontal(lipgloss.Center, header, "  ", lipgloss.NewStyle().Foreground(colors.warning).Render(tag))
+	}
 	
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(0, 1).
 		Render(header)
 }
 
+// viewTitles name the views in the footer and in view captures
+var viewTitles = map[ViewType]string{
+	ViewProcesses:    "Processes",
+	ViewDetails:      "Details",
+	ViewStats:        "Statistics",
+	ViewSettings:     "Settings",
+	ViewHelp:         "Help",
+	ViewDiagnostics:  "Diagnostics",
+	ViewLogs:         "Logs",
+	ViewSchedule:     "Schedules",
+	ViewIdle:         "Idle Processes",
+	ViewDependencies: "Dependencies",
+	ViewSecurity:     "Security",
+}
+
 // renderFooter renders the application footer
 func (m MainModel) renderFooter() string {
-	viewNames := map[ViewType]string{
-		ViewProcesses: "Processes",
-		ViewDetails:   "Details", 
-		ViewStats:     "Statistics",
-		ViewSettings:  "Settings",
-		ViewHelp:      "Help",
+	status := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Render("View: " + viewTitles[m.currentView])
+
+	// Say when keys are going to a search or form rather than shortcuts
+	if mode := m.inputMode(); mode != ModeNormal {
+		status += lipgloss.NewStyle().
+			Foreground(colors.warning).
+			Render("  [" + mode.String() + "]")
 	}
 
-	status := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("View: " + viewNames[m.currentView])
+	if m.processService.Attached() {
+		status += lipgloss.NewStyle().
+			Foreground(colors.ok).
+			Render("  ● Attached to daemon")
+	} else if m.daemonPID != 0 {
+		status += lipgloss.NewStyle().
+			Foreground(colors.warning).
+			Render("  ○ Daemon unreachable, sampling locally")
+	}
+
+	// A notice takes the place of the hints while it shows
+	if m.notice == "" {
+		status += m.renderHints(m.width - lipgloss.Width(status) - 6)
+	}
+
+	if m.notice != "" {
+		icon, color := "✓", colors.ok
+		if m.noticeFailed {
+			icon, color = "✗", colors.err
+		}
+		// Keep the footer on one line
+		notice := m.notice
+		if width := max(m.width-lipgloss.Width(status)-12, 10); len([]rune(notice)) > width {
+			notice = string([]rune(notice)[:width-3]) + "..."
+		}
+		status += lipgloss.NewStyle().
+			Foreground(color).
+			Render("  " + icon + " " + notice)
+	}
 
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(0, 1).
 		Render(status)
 }
 
+// footerHints returns the keys most useful in the current view, or those
+// finishing the input or key sequence it waits for
+func (m MainModel) footerHints() []keyBinding {
+	if m.currentView == ViewDetails && m.details.confirmingAction {
+		return confirmActionHints
+	}
+
+	switch m.inputMode() {
+	case ModeSearch:
+		return searchHints
+	case ModeForm:
+		return formHints
+	}
+
+	if m.currentView == ViewProcesses {
+		switch {
+		case m.processes.bookmarking:
+			return bookmarkHints
+		case m.processes.treeView:
+			return viewHints(ViewProcesses, treeHints...)
+		}
+	}
+	return viewHints(m.currentView)
+}
+
+// renderHints renders the footer hints that fit in width
+func (m MainModel) renderHints(width int) string {
+	keyStyle := lipgloss.NewStyle().
+		Foreground(colors.text).
+		Bold(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	hints := ""
+	for _, h := range m.footerHints() {
+		hint := "  " + keyStyle.Render(h.keys) + " " + hintStyle.Render(h.hint)
+		if lipgloss.Width(hints+hint) > width {
+			break
+		}
+		hints += hint
+	}
+	return hints
+}
+
+// notify shows text in the footer until noticeDuration passes or another
+// notice replaces it
+func (m *MainModel) notify(text string, failed bool) tea.Cmd {
+	at := time.Now()
+	m.notice = text
+	m.noticeFailed = failed
+	m.noticeAt = at
+	return tea.Tick(noticeDuration, func(time.Time) tea.Msg {
+		return noticeExpiredMsg{At: at}
+	})
+}
+
+// exportProcesses writes the processes listed in the processes view to a
+// CSV file in the data directory
+func (m MainModel) exportProcesses() tea.Cmd {
+	// The processes view reuses the buffer of its list
+	processes := slices.Clone(m.processes.processes)
+	return func() tea.Msg {
+		if err := m.storage.SaveProcessSnapshot(processes); err != nil {
+			return exportMsg{Error: err}
+		}
+		path, err := m.storage.ExportProcesses("csv")
+		return exportMsg{Path: path, Count: len(processes), Error: err}
+	}
+}
+
+// createBackup backs up the config and the processes listed in the
+// processes view
+func (m MainModel) createBackup() tea.Cmd {
+	processes := slices.Clone(m.processes.processes)
+	return func() tea.Msg {
+		if err := m.storage.SaveProcessSnapshot(processes); err != nil {
+			return backupMsg{Error: err}
+		}
+		return backupMsg{Error: m.storage.CreateBackup()}
+	}
+}
+
+// recordLoad records a load sample immediately
+func (m MainModel) recordLoad() tea.Cmd {
+	return func() tea.Msg {
+		m.historyService.SampleLoad()
+		return loadSampleMsg{}
+	}
+}
+
+// scheduleLoadSample records the next load sample after services.LoadSampleInterval
+func (m MainModel) scheduleLoadSample() tea.Cmd {
+	return tea.Tick(services.LoadSampleInterval, func(time.Time) tea.Msg {
+		m.historyService.SampleLoad()
+		return loadSampleMsg{}
+	})
+}
+
+// checkBudgets samples CPU usage against the resource budgets immediately
+func (m MainModel) checkBudgets() tea.Cmd {
+	return func() tea.Msg {
+		return m.sampleBudgets(time.Now())
+	}
+}
+
+// scheduleBudgetCheck samples CPU usage again after services.BudgetInterval
+func (m MainModel) scheduleBudgetCheck() tea.Cmd {
+	return tea.Tick(services.BudgetInterval, m.sampleBudgets)
+}
+
+// sampleBudgets samples CPU usage against the resource budgets at now,
+// unless an attached daemon enforces them
+func (m MainModel) sampleBudgets(now time.Time) tea.Msg {
+	if m.processService.Attached() {
+		return budgetMsg{}
+	}
+	exceeded, _ := m.budgetService.Sample(now)
+	return budgetMsg{Exceeded: exceeded}
+}
+
+// sampleIdle checks processes for activity immediately
+func (m MainModel) sampleIdle() tea.Cmd {
+	return func() tea.Msg {
+		return m.sampleIdleAt(time.Now())
+	}
+}
+
+// scheduleIdleSample checks processes for activity again after services.IdleSampleInterval
+func (m MainModel) scheduleIdleSample() tea.Cmd {
+	return tea.Tick(services.IdleSampleInterval, m.sampleIdleAt)
+}
+
+// sampleIdleAt checks processes for activity at now, unless an attached
+// daemon watches them
+func (m MainModel) sampleIdleAt(now time.Time) tea.Msg {
+	if !m.processService.Attached() {
+		m.idleService.Sample(now)
+	}
+	return idleSampleMsg{}
+}
+
+// sampleListeners records the listening sockets immediately
+func (m MainModel) sampleListeners() tea.Cmd {
+	return func() tea.Msg {
+		return m.sampleListenersAt(time.Now())
+	}
+}
+
+// scheduleListenerSample compares the listening sockets again after the listener interval
+func (m MainModel) scheduleListenerSample() tea.Cmd {
+	return tea.Tick(m.listenerService.Interval(), m.sampleListenersAt)
+}
+
+// sampleListenersAt compares the listening sockets at now, unless an
+// attached daemon watches them
+func (m MainModel) sampleListenersAt(now time.Time) tea.Msg {
+	if m.processService.Attached() {
+		return listenerMsg{}
+	}
+	changes, _ := m.listenerService.Sample(now)
+	return listenerMsg{Changes: changes}
+}
+
+// listenerStatus describes a service starting or stopping listening for the status bar
+func listenerStatus(change models.ListenerChange) string {
+	verb := "Stopped"
+	if change.Started {
+		verb = "Started"
+	}
+	return fmt.Sprintf("%s listening: %s on %s", verb, processLabel(change.Listener.Process, change.Listener.PID), services.ListenerAddress(change.Listener))
+}
+
+// checkHashes loads the hash allowlist and checks the running processes'
+// executables against it immediately
+func (m MainModel) checkHashes() tea.Cmd {
+	return func() tea.Msg {
+		if err := m.hashChecker.Load(); err != nil {
+			return hashCheckMsg{Error: err}
+		}
+		return hashCheckMsg{Flagged: m.checkProcessHashes(time.Now())}
+	}
+}
+
+// scheduleHashCheck checks new processes' executables again after services.HashCheckInterval
+func (m MainModel) scheduleHashCheck() tea.Cmd {
+	return tea.Tick(services.HashCheckInterval, func(t time.Time) tea.Msg {
+		return hashCheckMsg{Flagged: m.checkProcessHashes(t)}
+	})
+}
+
+// checkProcessHashes checks the processes of the latest scan, scanning if
+// there has been none yet. Nothing is checked while attached to a daemon.
+func (m MainModel) checkProcessHashes(now time.Time) []models.UnknownBinary {
+	if m.processService.Attached() {
+		return nil
+	}
+	processes := m.processService.LastProcesses()
+	if processes == nil {
+		processes, _ = m.processService.GetProcesses()
+	}
+	return m.hashChecker.Check(processes, now)
+}
+
+// scheduleExports runs the export schedules due after
+// services.ExportCheckInterval, unless an attached daemon runs them
+func (m MainModel) scheduleExports() tea.Cmd {
+	return tea.Tick(services.ExportCheckInterval, func(t time.Time) tea.Msg {
+		if m.processService.Attached() {
+			return scheduledExportsMsg{}
+		}
+		return scheduledExportsMsg{Statuses: m.exportScheduler.RunDue(t)}
+	})
+}
+
+// pruneStorage deletes stored files outside their retention policies immediately
+func (m MainModel) pruneStorage() tea.Cmd {
+	return func() tea.Msg {
+		return m.prune()
+	}
+}
+
+// schedulePrune prunes stored files again after storage.PruneInterval
+func (m MainModel) schedulePrune() tea.Cmd {
+	return tea.Tick(storage.PruneInterval, func(time.Time) tea.Msg {
+		return m.prune()
+	})
+}
+
+// prune deletes stored files outside their retention policies, unless an
+// attached daemon does
+func (m MainModel) prune() tea.Msg {
+	if m.processService.Attached() {
+		return pruneMsg{}
+	}
+	_, err := m.storage.Prune(false)
+	return pruneMsg{Error: err}
+}
+
+// inputMode returns the input mode of the current view; global shortcuts
+// only apply in normal mode
+func (m MainModel) inputMode() InputMode {
+	switch m.currentView {
+	case ViewProcesses:
+		return m.processes.InputMode()
+	case ViewDetails:
+		return m.details.InputMode()
+	case ViewIdle:
+		return m.idle.InputMode()
+	case ViewHelp:
+		return m.help.InputMode()
+	default:
+		return ModeNormal
+	}
+}
+
+// runScheduled runs the scheduled actions due at now
+func (m MainModel) runScheduled(now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if actions := m.scheduler.RunDue(now); len(actions) > 0 {
+			return scheduledActionsMsg{Actions: actions}
+		}
+		return nil
+	}
+}
+
+// checkForUpdate looks for a newer release once at startup, if enabled
+func (m MainModel) checkForUpdate() tea.Cmd {
+	if m.updateService == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		release, err := m.updateService.CheckForUpdate(context.Background(), version.Get().Version)
+		return updateCheckMsg{Release: release, Err: err}
+	}
+}
+
+// scheduleCollectorTick schedules the next shared collector tick
+func (m MainModel) scheduleCollectorTick() tea.Cmd {
+	return tea.Tick(collectorInterval, func(t time.Time) tea.Msg {
+		return collectorTickMsg{At: t}
+	})
+}
+
+// viewRefreshInterval returns how often view should be refreshed, or zero for
+// views that do not refresh. Every view refreshes from the collector tick,
+// rather than scheduling its own, so only the view shown refreshes and
+// leaving and reopening a view never leaves it two timers.
+func (m MainModel) viewRefreshInterval(view ViewType) time.Duration {
+	switch view {
+	case ViewProcesses:
+		return m.processes.refreshRate
+	case ViewDetails:
+		return m.details.refreshRate
+	case ViewStats:
+		return m.stats.refreshRate
+	case ViewDiagnostics:
+		return diagnosticsInterval
+	case ViewLogs:
+		return logTailInterval
+	case ViewSchedule:
+		return scheduleInterval
+	case ViewIdle:
+		return idleReloadInterval
+	case ViewDependencies:
+		return dependenciesReloadInterval
+	case ViewSecurity:
+		return securityReloadInterval
+	}
+	return 0
+}
+
+// refreshInterval converts a configured interval in seconds to a duration,
+// falling back to def when unset and never going below collectorInterval
+func refreshInterval(seconds int, def time.Duration) time.Duration {
+	if seconds <= 0 {
+		return def
+	}
+	return max(time.Duration(seconds)*time.Second, collectorInterval)
+}
+
 // renderSmallTerminalMessage renders a message for small terminals
 func (m MainModel) renderSmallTerminalMessage() string {
 	message := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(colors.err).
 		Bold(true).
 		Align(lipgloss.Center).
 		Render("Terminal too small!\n\nPlease resize your terminal to at least 80x20 characters.\n\nCurrent size: " + 
-			lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Render(fmt.Sprintf("%dx%d", m.width, m.height)) + 
+			lipgloss.NewStyle().Foreground(colors.text).Render(fmt.Sprintf("%dx%d", m.width, m.height)) + 
 			"\n\nPress Ctrl+C to quit.")
 
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("196")).
+		BorderForeground(colors.err).
 		Padding(2, 4).
 		Align(lipgloss.Center).
 		Render(message)
 }
+
+// Messages
+type loadSampleMsg struct{}
+
+type pruneMsg struct {
+	Error error
+}
+
+type idleSampleMsg struct{}
+
+type listenerMsg struct {
+	Changes []models.ListenerChange
+}
+
+type exportMsg struct {
+	Path  string
+	Count int
+	Error error
+}
+
+type backupMsg struct {
+	Error error
+}
+
+type noticeExpiredMsg struct {
+	At time.Time
+}
+
+type hashCheckMsg struct {
+	Flagged []models.UnknownBinary
+	Error   error
+}
+
+type scheduledExportsMsg struct {
+	Statuses []models.ExportScheduleStatus
+}
+
+type budgetMsg struct {
+	Exceeded []models.BudgetStatus
+}
+
+type updateCheckMsg struct {
+	Release *models.ReleaseInfo
+	Err     error
+}
+
+type collectorTickMsg struct {
+	At time.Time
+}
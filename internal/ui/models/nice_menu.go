@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nicePreset is a named nice value processes can be reniced to at once
+type nicePreset struct {
+	name string
+	nice int
+}
+
+// nicePresets are the presets the nice menu offers, lowest priority first
+var nicePresets = []nicePreset{
+	{"background", 19},
+	{"low", 10},
+	{"interactive", 0},
+	{"high", -5}, // raising priority needs root
+}
+
+// niceMenu lets the user renice processes to one of the nice presets
+type niceMenu struct {
+	open      bool
+	index     int // into nicePresets
+	processes []*models.ProcessInfo
+}
+
+// Open shows the menu for processes with the background preset chosen
+func (n *niceMenu) Open(processes []*models.ProcessInfo) {
+	n.open = true
+	n.index = 0
+	n.processes = processes
+}
+
+// Update handles a key while the menu is open and returns the command
+// renicing the processes once the preset is confirmed
+func (n *niceMenu) Update(msg tea.KeyMsg, processService *services.ProcessService) tea.Cmd {
+	switch key := msg.String(); key {
+	case "esc":
+		n.open = false
+	case "left", "h":
+		n.index = (n.index + len(nicePresets) - 1) % len(nicePresets)
+	case "right", "l", "tab":
+		n.index = (n.index + 1) % len(nicePresets)
+	case "enter":
+		n.open = false
+		return reniceProcesses(processService, n.processes, nicePresets[n.index])
+	default:
+		// Presets can also be chosen by their number
+		if i, err := strconv.Atoi(key); err == nil && i >= 1 && i <= len(nicePresets) {
+			n.index = i - 1
+		}
+	}
+	return nil
+}
+
+// View renders the menu on one line, the chosen preset highlighted
+func (n niceMenu) View() string {
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	choices := make([]string, len(nicePresets))
+	for i, preset := range nicePresets {
+		choice := fmt.Sprintf("%d %s (%d)", i+1, preset.name, preset.nice)
+		if i == n.index {
+			choice = selectedStyle.Render(choice)
+		}
+		choices[i] = choice
+	}
+
+	target := fmt.Sprintf("%d processes", len(n.processes))
+	if len(n.processes) == 1 {
+		target = fmt.Sprintf("%s (%d)", n.processes[0].Name, n.processes[0].PID)
+	}
+	return fmt.Sprintf("Renice %s: %s | ←/→: choose, Enter: apply, Esc: cancel",
+		target, strings.Join(choices, "  "))
+}
+
+// reniceProcesses sets the nice value of preset on each of processes. Each
+// renice is recorded in the event log by the process service.
+func reniceProcesses(processService *services.ProcessService, processes []*models.ProcessInfo, preset nicePreset) tea.Cmd {
+	return func() tea.Msg {
+		msg := reniceMsg{Preset: preset, Requested: len(processes)}
+		for _, proc := range processes {
+			if err := processService.SetNice(proc.PID, preset.nice); err != nil {
+				msg.Failed = append(msg.Failed, fmt.Sprintf("%s (%d): %v", proc.Name, proc.PID, err))
+				continue
+			}
+			msg.Reniced++
+		}
+		return msg
+	}
+}
+
+// Status describes the outcome for the status bar, naming the first failure
+func (msg reniceMsg) Status() string {
+	status := fmt.Sprintf("Set nice %d (%s) on %d of %d processes", msg.Preset.nice, msg.Preset.name, msg.Reniced, msg.Requested)
+	if len(msg.Failed) > 0 {
+		status += fmt.Sprintf("; %d failed, first %s", len(msg.Failed), msg.Failed[0])
+	}
+	return status
+}
+
+// Messages
+type reniceMsg struct {
+	Preset    nicePreset
+	Requested int
+	Reniced   int
+	Failed    []string // process and error of each failed renice
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"reflect"
+	"sync"
+
+	"tappmanager/internal/models"
+)
+
+// maxFreeLists bounds the list buffers kept for reuse; one is usually being
+// shown and one filled by a refresh
+const maxFreeLists = 2
+
+// processLists recycles the buffers the process table is filtered into and
+// remembers the last list worked out from the latest scan, so a refresh that
+// would filter and sort the same scan the same way again can copy it instead.
+// It is shared by the copies of a ProcessesModel and used by the refresh
+// commands, hence the lock.
+type processLists struct {
+	mu   sync.Mutex
+	free [][]*models.ProcessInfo
+
+	// last is the list worked out for key, in a buffer of its own
+	key     listKey
+	last    refreshProcessesMsg
+	hasLast bool
+}
+
+// listKey is everything the listed processes depend on besides the scan
+type listKey struct {
+	scan       uint64
+	filter     models.ProcessFilter
+	sort       models.ProcessSort
+	groupApps  bool
+	treeView   bool
+	processCap int
+}
+
+// matches reports whether both keys give the same list
+func (k listKey) matches(other listKey) bool {
+	// The filter holds slices, so it is compared deeply; telling an empty
+	// exclusion list from none apart only costs a miss
+	return k.scan == other.scan && reflect.DeepEqual(k.filter, other.filter) && k.sort == other.sort &&
+		k.groupApps == other.groupApps && k.treeView == other.treeView && k.processCap == other.processCap
+}
+
+// get returns an empty buffer to fill with a list
+func (l *processLists) get() []*models.ProcessInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n := len(l.free); n > 0 {
+		list := l.free[n-1]
+		l.free = l.free[:n-1]
+		return list[:0]
+	}
+	return nil
+}
+
+// put hands a list no longer referenced back for reuse
+func (l *processLists) put(list []*models.ProcessInfo) {
+	if cap(list) == 0 {
+		return
+	}
+	clear(list[:cap(list)])
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.free) < maxFreeLists {
+		l.free = append(l.free, list[:0])
+	}
+}
+
+// release hands back the list shown until next replaces it
+func (l *processLists) release(shown, next []*models.ProcessInfo) {
+	if cap(shown) == 0 || (cap(next) > 0 && &shown[:cap(shown)][0] == &next[:cap(next)][0]) {
+		return
+	}
+	l.put(shown)
+}
+
+// cached returns a copy of the list remembered for key, if there is one
+func (l *processLists) cached(key listKey) (refreshProcessesMsg, bool) {
+	list := l.get()
+	l.mu.Lock()
+	msg, ok := l.last, l.hasLast && l.key.matches(key)
+	if ok {
+		// Copy while locked, as remember reuses the buffer
+		msg.Processes = append(list, msg.Processes...)
+	}
+	l.mu.Unlock()
+
+	if !ok {
+		l.put(list)
+		return refreshProcessesMsg{}, false
+	}
+	return msg, true
+}
+
+// remember keeps a copy of msg as the list worked out for key
+func (l *processLists) remember(key listKey, msg refreshProcessesMsg) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	processes := append(l.last.Processes[:0], msg.Processes...)
+	clear(processes[len(processes):cap(processes)])
+	msg.Processes = processes
+	msg.Cached = true
+	l.key, l.last, l.hasLast = key, msg, true
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"tappmanager/internal/models"
+)
+
+func TestProcessListsCached(t *testing.T) {
+	lists := &processLists{}
+	key := listKey{scan: 1, filter: models.ProcessFilter{ExcludeNames: []string{"idle"}}, sort: models.ProcessSort{Field: "cpu"}}
+	shown := []*models.ProcessInfo{{PID: 2}, {PID: 3}}
+	lists.remember(key, refreshProcessesMsg{Processes: shown, Total: 2})
+
+	tests := []struct {
+		name string
+		key  listKey
+		hit  bool
+	}{
+		{"same key", key, true},
+		{"same filter in a copy", listKey{scan: 1, filter: models.ProcessFilter{ExcludeNames: []string{"idle"}}, sort: key.sort}, true},
+		{"newer scan", listKey{scan: 2, filter: key.filter, sort: key.sort}, false},
+		{"other filter", listKey{scan: 1, sort: key.sort}, false},
+		{"other sort", listKey{scan: 1, filter: key.filter, sort: models.ProcessSort{Field: "pid"}}, false},
+		{"tree view", listKey{scan: 1, filter: key.filter, sort: key.sort, treeView: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := lists.cached(tt.key)
+			if ok != tt.hit {
+				t.Fatalf("cached = %v, want %v", ok, tt.hit)
+			}
+			if !ok {
+				return
+			}
+			if !msg.Cached || msg.Total != 2 || len(msg.Processes) != 2 || msg.Processes[1].PID != 3 {
+				t.Fatalf("got %+v, want the remembered list", msg)
+			}
+			// Each hit gets a copy, so the one shown is never written to
+			if &msg.Processes[0] == &lists.last.Processes[0] {
+				t.Error("cached list shares the remembered buffer")
+			}
+			lists.release(msg.Processes, nil)
+		})
+	}
+}
+
+func TestProcessListsRelease(t *testing.T) {
+	lists := &processLists{}
+	shown := []*models.ProcessInfo{{PID: 1}, {PID: 2}}
+
+	// A list replaced by itself is still shown
+	lists.release(shown, shown[:1])
+	if got := lists.get(); got != nil {
+		t.Fatalf("got a %d-process buffer still being shown", cap(got))
+	}
+
+	lists.release(shown, nil)
+	got := lists.get()
+	if cap(got) != 2 || len(got) != 0 {
+		t.Fatalf("got len %d cap %d, want the released buffer emptied", len(got), cap(got))
+	}
+	if shown[0] != nil {
+		t.Error("released buffer still holds its processes")
+	}
+}
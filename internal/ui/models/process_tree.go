@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+)
+
+// treeRow places a process in the tree layout of the processes table
+type treeRow struct {
+	depth    int
+	children bool
+	hidden   int // descendants left out because the process is collapsed
+}
+
+// prefix indents the process's name by its depth and marks whether its
+// children are shown
+func (r treeRow) prefix() string {
+	marker := "  "
+	switch {
+	case r.hidden > 0:
+		marker = "▸ "
+	case r.children:
+		marker = "▾ "
+	}
+	return strings.Repeat("  ", r.depth) + marker
+}
+
+// layoutTree orders processes as a tree using the process service's parent
+// map, each process followed by its children in the order processes is in,
+// so siblings keep the active sort. Processes whose parent is not listed are
+// roots. The descendants of collapsed processes are left out.
+func layoutTree(ps *services.ProcessService, processes []*models.ProcessInfo, collapsed map[int32]bool) ([]*models.ProcessInfo, map[int32]treeRow) {
+	listed := make(map[int32]bool, len(processes))
+	for _, proc := range processes {
+		listed[proc.PID] = true
+	}
+	children := ps.GetProcessTree(processes)
+
+	ordered := make([]*models.ProcessInfo, 0, len(processes))
+	rows := make(map[int32]treeRow, len(processes))
+
+	// visit lays out proc and its descendants and returns how many
+	// descendants it has
+	var visit func(proc *models.ProcessInfo, depth int, hidden bool) int
+	visit = func(proc *models.ProcessInfo, depth int, hidden bool) int {
+		if !hidden {
+			ordered = append(ordered, proc)
+		}
+		rows[proc.PID] = treeRow{depth: depth}
+
+		descendants := 0
+		for _, child := range children[proc.PID] {
+			// Skip processes already placed, which guards against cycles
+			if _, placed := rows[child.PID]; placed {
+				continue
+			}
+			descendants += 1 + visit(child, depth+1, hidden || collapsed[proc.PID])
+		}
+
+		row := treeRow{depth: depth, children: descendants > 0}
+		if collapsed[proc.PID] {
+			row.hidden = descendants
+		}
+		rows[proc.PID] = row
+		return descendants
+	}
+
+	for _, proc := range processes {
+		if !listed[proc.PPID] || proc.PPID == proc.PID {
+			visit(proc, 0, false)
+		}
+	}
+	// Processes whose parents form a cycle have no root to hang from
+	for _, proc := range processes {
+		if _, placed := rows[proc.PID]; !placed {
+			visit(proc, 0, false)
+		}
+	}
+	return ordered, rows
+}
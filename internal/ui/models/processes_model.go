@@ -2,13 +2,18 @@ package models
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"tappmanager/internal/models"
 	"tappmanager/internal/services"
+	"tappmanager/internal/storage"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -16,23 +21,152 @@ import (
 // ProcessesModel handles the processes view
 type ProcessesModel struct {
 	processService *services.ProcessService
+	scheduler      *services.Scheduler
+	storage        storage.Storage
 	processes      []*models.ProcessInfo
+	totalProcesses int
+	// shown is the list of the last refresh, in a buffer of lists; processes
+	// is the same list unless pinning or the tree reordered it
+	lists          *processLists
+	shown          []*models.ProcessInfo
+	processCap     int
+	killTimeout    time.Duration // grace period after SIGTERM before Ctrl+K kills
 	filter         *models.ProcessFilter
 	sort           *models.ProcessSort
+	capabilities   *models.Capabilities
+	refreshRate    time.Duration
+	lastRefresh    time.Time
 	selectedIndex  int
 	width          int
 	height         int
 	showSystem     bool
+	showSecurity   bool
+	showSession    bool
+	columns        map[string]bool // optional columns shown, from models.ProcessColumns
+	memoryBytes    bool            // memory shown as resident bytes rather than percent
+	// changes is how CPU and memory changes are marked, from the config;
+	// previous are the figures of the processes in the scan before the last
+	changes  string
+	previous map[int32]usageFigures
 	refreshing     bool
+	spinnerFrame   int
+	paused         bool // auto-refresh stopped; R still refreshes
+
+	// Bookmarked processes by slot 0-9; bookmarking is set while waiting for a slot
+	bookmarks   [bookmarkSlots]*bookmark
+	bookmarking bool
+	// pinned are the PIDs listed above the other processes, in the order
+	// pinned; pinnedRows is how many of them lead the listed processes
+	pinned     []int32
+	pinnedRows int
+	// trackedPID keeps the selection on a process across refreshes, zero for none
+	trackedPID int32
+	// following keeps trackedPID set to the selection and centers it in the table
+	following bool
+	// offset is the first process row shown in the table below the sticky
+	// pinned rows
+	offset int
+	// treeView lists processes under their parents, laid out in tree; the
+	// children of collapsed processes are hidden
+	treeView  bool
+	tree      map[int32]treeRow
+	collapsed map[int32]bool
+	// groupApps folds browser and Electron helpers into their app's row
+	// outside tree view; helpers counts those folded by app PID
+	groupApps bool
+	helpers   map[int32]int
+
+	// prompting is set while a kill time is being typed for promptProcess
+	prompting     bool
+	promptInput   string
+	promptProcess *models.ProcessInfo
+	// searching is set while a search term is being typed; the list is
+	// filtered as it changes and searchBefore is restored on Esc
+	searching    bool
+	search       textinput.Model
+	searchBefore string
+	// filterForm edits the CPU, memory, status and user criteria
+	filterForm filterForm
+	// savedFilters applies, saves and deletes named filters kept in storage
+	savedFilters filterPicker
+	// signals chooses a signal to send to the selected process
+	signals signalMenu
+	// niceMenu renices the marked processes, or the selected one, to a preset
+	niceMenu niceMenu
+	// marked are the PIDs marked to act on together
+	marked map[int32]bool
+	// sortMenu chooses the field and order to sort by
+	sortMenu sortMenu
+	// columnMenu shows and hides the optional columns
+	columnMenu columnMenu
+	// lastClick and lastClickIndex tell a double click on a row from two
+	// single clicks
+	lastClick      time.Time
+	lastClickIndex int
+	statusMessage  string
 }
 
+// doubleClickInterval is the longest time between the clicks of a double
+// click
+const doubleClickInterval = 400 * time.Millisecond
+
+// mouseWheelRows is how many rows a turn of the mouse wheel moves the
+// selection
+const mouseWheelRows = 3
+
+// bookmarkSlots is the number of numbered bookmark slots
+const bookmarkSlots = 10
+
+// bookmarkSidebarWidth is the width of the bookmarks sidebar, borders included
+const bookmarkSidebarWidth = 28
+
+// bookmark remembers a process by PID; the name is kept for display once the
+// process is no longer listed
+type bookmark struct {
+	PID  int32
+	Name string
+}
+
+// spinnerFrames are cycled in the status bar while a manual refresh is running
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is how often the refresh spinner advances
+const spinnerInterval = 100 * time.Millisecond
+
 // NewProcessesModel creates a new processes model
-func NewProcessesModel(processService *services.ProcessService) *ProcessesModel {
+func NewProcessesModel(processService *services.ProcessService, scheduler *services.Scheduler, store storage.Storage, capabilities *models.Capabilities, config *models.AppConfig) *ProcessesModel {
+	// The legacy global refresh rate still applies when no per-view interval is set
+	refreshRate := refreshInterval(config.ProcessesRefresh, refreshInterval(config.RefreshRate, defaultProcessesRefresh))
+
+	sort := &models.ProcessSort{Field: "cpu", Order: "desc"}
+	if config.DefaultSort.Field != "" {
+		sort.Field = config.DefaultSort.Field
+	}
+	if config.DefaultSort.Order == "asc" {
+		sort.Order = "asc"
+	}
+
+	columns := make(map[string]bool)
+	for _, column := range config.Columns {
+		columns[column] = true
+	}
+
 	return &ProcessesModel{
 		processService: processService,
+		scheduler:      scheduler,
+		storage:        store,
 		processes:      []*models.ProcessInfo{},
+		lists:          &processLists{},
+		search:         newTextInput(),
 		filter:         &models.ProcessFilter{},
-		sort:           &models.ProcessSort{Field: "cpu", Order: "desc"},
+		sort:           sort,
+		capabilities:   capabilities,
+		refreshRate:    refreshRate,
+		processCap:     config.ProcessCap,
+		killTimeout:    time.Duration(config.KillTimeout) * time.Second,
+		columns:        columns,
+		memoryBytes:    sort.Field == "memory_bytes",
+		changes:        config.ChangeIndicators,
 		selectedIndex:  0,
 		showSystem:     false,
 		refreshing:     false,
@@ -41,10 +175,7 @@ func NewProcessesModel(processService *services.ProcessService) *ProcessesModel
 
 // Init initializes the model
 func (m ProcessesModel) Init() tea.Cmd {
-	return tea.Batch(
-		m.refreshProcesses(),
-		m.startRefreshTimer(),
-	)
+	return m.refreshProcesses()
 }
 
 // Update handles messages and updates the model
@@ -53,97 +184,355 @@ func (m ProcessesModel) Update(msg tea.Msg) (ProcessesModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.prompting {
+			cmd = m.updatePrompt(msg)
+			break
+		}
+		if m.searching {
+			cmd = m.updateSearch(msg)
+			break
+		}
+		if m.filterForm.open {
+			if filter := m.filterForm.Update(msg, m.filter); filter != nil {
+				m.filter = filter
+				m.selectedIndex = 0
+				cmd = m.filterLastScan()
+			}
+			break
+		}
+		if m.savedFilters.open {
+			filter, saveCmd := m.savedFilters.Update(msg, m.filter, m.storage)
+			cmd = saveCmd
+			if filter != nil {
+				m.filter = filter
+				m.showSystem = filter.ShowSystem
+				m.selectedIndex = 0
+				cmd = m.filterLastScan()
+			}
+			break
+		}
+		if m.signals.open {
+			cmd = m.signals.Update(msg, m.processService)
+			break
+		}
+		if m.niceMenu.open {
+			cmd = m.niceMenu.Update(msg, m.processService)
+			break
+		}
+		if m.columnMenu.open {
+			if columns := m.columnMenu.Update(msg); columns != nil {
+				m.columns = make(map[string]bool)
+				for _, column := range columns {
+					m.columns[column] = true
+				}
+				cmd = saveColumns(m.storage, columns)
+			}
+			break
+		}
+		if m.sortMenu.open {
+			if sort := m.sortMenu.Update(msg, m.sort); sort != nil {
+				m.sort = sort
+				// Show the column sorted by
+				if column, ok := sortColumns[sort.Field]; ok {
+					m.columns[column] = true
+				}
+				if sort.Field == "memory_bytes" {
+					m.memoryBytes = true
+				}
+				cmd = m.filterLastScan()
+			}
+			break
+		}
+		m.statusMessage = ""
+
+		// Any key other than a slot number cancels bookmarking
+		assigning := m.bookmarking
+		m.bookmarking = false
+
 		switch msg.String() {
 		case "up", "k":
 			if m.selectedIndex > 0 {
 				m.selectedIndex--
 			}
+			m.trackSelection()
 
 		case "down", "j":
 			if m.selectedIndex < len(m.processes)-1 {
 				m.selectedIndex++
 			}
+			m.trackSelection()
+
+		case "F":
+			// Toggle following the selected process as the list re-sorts
+			m.following = !m.following && len(m.processes) > 0
+			m.trackSelection()
+
+		case "*":
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				cmd = m.togglePinned()
+			}
+
+		case "b":
+			m.bookmarking = !assigning && len(m.processes) > 0
+
+		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			slot := int(msg.String()[0] - '0')
+			if assigning {
+				m.toggleBookmark(slot)
+			} else {
+				m.jumpToBookmark(slot)
+			}
 
 		case "r":
-			cmd = m.refreshProcesses()
+			if !m.refreshing {
+				m.refreshing = true
+				m.spinnerFrame = 0
+				cmd = tea.Batch(m.refreshProcesses(), m.tickSpinner())
+			}
+
+		case "ctrl+p":
+			m.paused = !m.paused
 
 		case "ctrl+k":
+			// Ask the process to exit, killing it once the timeout passes
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				proc := m.processes[m.selectedIndex]
+				m.statusMessage = fmt.Sprintf("Terminating %s (PID %d)...", proc.Name, proc.PID)
+				cmd = terminateProcess(m.processService, proc, m.killTimeout)
+			}
+
+		case "alt+k":
 			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
-				cmd = m.killProcess(m.processes[m.selectedIndex].PID)
+				cmd = m.killProcess(m.processes[m.selectedIndex])
 			}
 
 		case "f":
-			cmd = m.showFilterDialog()
+			m.filterForm.Open(m.filter)
+
+		case "L":
+			cmd = loadFilters(m.storage)
 
 		case "ctrl+f":
-			cmd = m.showSearchDialog()
+			m.showSearchDialog()
 
-		case "s":
+		case ".":
 			m.showSystem = !m.showSystem
-			m.filter.ShowSystem = m.showSystem
-			cmd = m.refreshProcesses()
+			filter := *m.filter
+			filter.ShowSystem = m.showSystem
+			m.filter = &filter
+			cmd = m.filterLastScan()
 
-		case "o":
-			m.sortByField("cpu")
-			cmd = m.refreshProcesses()
+		case "s":
+			m.sortMenu.Open(m.sort)
 
-		case "m":
-			m.sortByField("memory")
-			cmd = m.refreshProcesses()
+		case "c":
+			m.columnMenu.Open(m.columns)
 
-		case "ctrl+p":
-			m.sortByField("pid")
-			cmd = m.refreshProcesses()
+		case "t":
+			// Toggle listing processes under their parents
+			m.treeView = !m.treeView
+			cmd = m.filterLastScan()
 
-		case "n":
-			m.sortByField("name")
-			cmd = m.refreshProcesses()
+		case "o":
+			m.groupApps = !m.groupApps
+			if m.groupApps && m.treeView {
+				m.statusMessage = "Helpers are grouped into their apps outside tree view"
+			}
+			cmd = m.filterLastScan()
+
+		case "left", "right", " ":
+			if m.treeView && len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				cmd = m.toggleCollapsed(msg.String())
+			} else if msg.String() == " " {
+				m.toggleMarked()
+			} else {
+				// Outside the tree, the arrows move the sort between columns
+				step := 1
+				if msg.String() == "left" {
+					step = -1
+				}
+				if m.moveSortColumn(step) {
+					cmd = m.filterLastScan()
+				}
+			}
 
-		case "t":
-			m.sortByField("status")
-			cmd = m.refreshProcesses()
+		case "v":
+			m.columns[models.ColumnIO] = !m.columns[models.ColumnIO]
+
+		case "V":
+			// Cycle between all processes, none virtualized and only virtualized
+			filter := *m.filter
+			switch filter.Virtualized {
+			case "":
+				filter.Virtualized = models.VirtualizedHide
+			case models.VirtualizedHide:
+				filter.Virtualized = models.VirtualizedOnly
+			default:
+				filter.Virtualized = ""
+			}
+			m.filter = &filter
+			cmd = m.filterLastScan()
 
-		case "u":
-			m.sortByField("user")
-			cmd = m.refreshProcesses()
+		case "m":
+			m.memoryBytes = !m.memoryBytes
 
-		case "ctrl+t":
-			m.sortByField("threads")
-			cmd = m.refreshProcesses()
+		case "x":
+			m.showSecurity = !m.showSecurity
 
-		case "ctrl+n":
-			m.sortByField("nice")
-			cmd = m.refreshProcesses()
+		case "i":
+			m.showSession = !m.showSession
+
+		case "I":
+			// Toggle filtering to the selected process's session
+			filter := *m.filter
+			if filter.SessionID != 0 {
+				filter.SessionID = 0
+			} else if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				filter.SessionID = m.processes[m.selectedIndex].SessionID
+			}
+			m.filter = &filter
+			cmd = m.filterLastScan()
+
+		case "w":
+			// Toggle filtering to processes running from the directory we were launched in
+			filter := *m.filter
+			if filter.PathPrefix != "" {
+				filter.PathPrefix = ""
+			} else if cwd, err := os.Getwd(); err == nil {
+				filter.PathPrefix = cwd
+			}
+			m.filter = &filter
+			cmd = m.filterLastScan()
+
+		case "W":
+			// Filter to processes running from the selected process's working directory
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) && m.processes[m.selectedIndex].WorkingDir != "" {
+				filter := *m.filter
+				filter.PathPrefix = m.processes[m.selectedIndex].WorkingDir
+				m.filter = &filter
+				cmd = m.filterLastScan()
+			}
+
+		case "-":
+			// Hide every process named like the selected one
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				name := m.processes[m.selectedIndex].Name
+				if !slices.Contains(m.filter.ExcludeNames, name) {
+					filter := *m.filter
+					filter.ExcludeNames = append(slices.Clone(filter.ExcludeNames), name)
+					m.filter = &filter
+				}
+				m.statusMessage = fmt.Sprintf("Hiding processes named %s (f to edit)", name)
+				cmd = m.filterLastScan()
+			}
 
 		case "ctrl+r":
-			// Reset filters and refresh
+			// Reset filters and marks and refresh
+			m.marked = nil
 			m.filter = &models.ProcessFilter{}
 			m.sort = &models.ProcessSort{Field: "cpu", Order: "desc"}
 			cmd = m.refreshProcesses()
 
 		case "ctrl+shift+f":
 			// Clear search filter
-			m.filter.SearchTerm = ""
-			cmd = m.refreshProcesses()
+			m.setSearchTerm("")
+			cmd = m.filterLastScan()
 
 		case "ctrl+shift+s":
 			// Reset sort to default
 			m.sort = &models.ProcessSort{Field: "cpu", Order: "desc"}
 			cmd = m.refreshProcesses()
 
+		case "K":
+			// Ask when to kill the selected process
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				m.prompting = true
+				m.promptInput = ""
+				m.promptProcess = m.processes[m.selectedIndex]
+			}
+
+		case "ctrl+z":
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				cmd = m.toggleSuspended(m.processes[m.selectedIndex])
+			}
+
+		case "S":
+			// Choose a signal to send to the selected process
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				m.signals.Open(m.processes[m.selectedIndex])
+			}
+
+		case "N":
+			// Renice the marked processes, or else the selected one, to a preset
+			if targets := m.markedProcesses(); len(targets) > 0 {
+				m.niceMenu.Open(targets)
+			} else if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				m.niceMenu.Open([]*models.ProcessInfo{m.processes[m.selectedIndex]})
+			}
+
+		case "y":
+			// Copy the filtered, sorted list with the columns shown
+			if len(m.processes) > 0 {
+				cmd = copyToClipboard(fmt.Sprintf("%d rows", len(m.processes)), m.tableTSV())
+			}
+
+		case "a":
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewSchedule} }
+
+		case "z":
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewIdle} }
+
+		case "M":
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewDependencies} }
+
+		case "U":
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewSecurity} }
+
+		case "l":
+			// Tail the log files associated with the selected process's name
+			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
+				name := m.processes[m.selectedIndex].Name
+				cmd = func() tea.Msg { return OpenLogsMsg{ProcessName: name} }
+			}
+
 		case "enter":
 			if len(m.processes) > 0 && m.selectedIndex < len(m.processes) {
 				// Switch to details view
+				pid := m.processes[m.selectedIndex].PID
 				cmd = tea.Sequence(
-					tea.Printf("Switching to details view for process %d", m.processes[m.selectedIndex].PID),
-					func() tea.Msg { return SwitchViewMsg{View: ViewDetails} },
+					tea.Printf("Switching to details view for process %d", pid),
+					func() tea.Msg { return SwitchViewMsg{View: ViewDetails, PID: pid} },
 				)
 			}
 		}
 
 	case refreshProcessesMsg:
-		m.processes = msg.Processes
 		m.refreshing = false
+		if msg.Error != nil {
+			// Keep showing the last good snapshot; the status bar will turn stale
+			break
+		}
+		if !msg.Cached {
+			m.previous = figuresOf(m.processes)
+		}
+		m.lists.release(m.shown, msg.Processes)
+		m.shown = msg.Processes
+		m.processes = msg.Processes
+		m.helpers = msg.Helpers
+		m.pinnedRows = 0
+		if m.treeView {
+			m.processes, m.tree = layoutTree(m.processService, msg.Processes, m.collapsed)
+		} else if len(m.pinned) > 0 {
+			m.processes, m.pinnedRows = pinFirst(msg.Processes, m.pinned)
+		}
+		m.totalProcesses = msg.Total
+		if !msg.Cached {
+			m.lastRefresh = time.Now()
+		}
+		// Follow a tracked process to its new position
+		if i := m.indexOfPID(m.trackedPID); m.trackedPID != 0 && i >= 0 {
+			m.selectedIndex = i
+		}
 		// Keep selected index within bounds
 		if m.selectedIndex >= len(m.processes) {
 			m.selectedIndex = len(m.processes) - 1
@@ -153,12 +542,61 @@ func (m ProcessesModel) Update(msg tea.Msg) (ProcessesModel, tea.Cmd) {
 		}
 
 	case refreshTimerMsg:
+		if !m.paused {
+			cmd = m.refreshProcesses()
+		}
+
+	case signalMsg:
+		m.statusMessage = ""
+		cmd = m.refreshProcesses()
+
+	case killProcessMsg:
+		// The main model reports the outcome
+		m.statusMessage = ""
 		cmd = m.refreshProcesses()
 
+	case reniceMsg:
+		m.statusMessage = msg.Status()
+		m.marked = nil
+		cmd = m.refreshProcesses()
+
+	case columnsSavedMsg:
+		if msg.Error != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save columns: %v", msg.Error)
+		} else {
+			m.statusMessage = "Columns saved"
+		}
+
+	case clipboardMsg:
+		m.statusMessage = "Copied " + msg.Label + " to clipboard"
+
+	case savedFiltersMsg:
+		switch {
+		case msg.Error != nil:
+			m.statusMessage = msg.Error.Error()
+		case msg.Open:
+			m.savedFilters.Open(msg.Filters)
+		default:
+			m.statusMessage = msg.Notice
+		}
+
+	case tea.MouseMsg:
+		// Forms and menus take the keyboard only
+		if m.InputMode() == ModeNormal && len(m.processes) > 0 {
+			cmd = m.updateMouse(msg)
+		}
+
+	case spinnerTickMsg:
+		if m.refreshing {
+			m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+			cmd = m.tickSpinner()
+		}
+
 	case SwitchViewMsg:
 		// This will be handled by the main model
 	}
 
+	m.scrollToSelection()
 	return m, cmd
 }
 
@@ -166,16 +604,158 @@ func (m ProcessesModel) Update(msg tea.Msg) (ProcessesModel, tea.Cmd) {
 func (m ProcessesModel) UpdateSize(width, height int) ProcessesModel {
 	m.width = width
 	m.height = height
+	m.scrollToSelection()
 	return m
 }
 
-// View renders the processes view
-func (m ProcessesModel) View() string {
-	if m.refreshing {
-		return "Refreshing processes...\n"
+// updatePrompt edits the kill time prompt and schedules the kill on Enter
+func (m *ProcessesModel) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.prompting = false
+
+	case tea.KeyEnter:
+		m.prompting = false
+		at, err := services.ParseScheduleTime(m.promptInput, time.Now())
+		if err != nil {
+			m.statusMessage = err.Error()
+			break
+		}
+		action := m.scheduler.ScheduleKill(m.promptProcess, at)
+		m.statusMessage = fmt.Sprintf("%s (%d) will be killed at %s", action.Name, action.PID, formatActionTime(action.At))
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.promptInput); len(runes) > 0 {
+			m.promptInput = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.promptInput += string(msg.Runes)
 	}
+	return nil
+}
+
+// updateMouse selects the row clicked, opens its details on a double click,
+// moves the selection with the wheel and sorts by the column whose header is
+// clicked, again to reverse the order
+func (m *ProcessesModel) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.selectedIndex = max(m.selectedIndex-mouseWheelRows, 0)
+		m.trackSelection()
+
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.selectedIndex = min(m.selectedIndex+mouseWheelRows, len(m.processes)-1)
+		m.trackSelection()
+
+	case msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress:
+
+	// The table starts below the main header, with its top border, column
+	// headers, summary row and separator above the rows
+	case msg.Y == headerHeight+1:
+		if field := m.sortFieldAt(msg.X); field != "" {
+			order := "desc"
+			if field == m.sort.Field && m.sort.Order == "desc" {
+				order = "asc"
+			}
+			m.sort = &models.ProcessSort{Field: field, Order: order}
+			return m.filterLastScan()
+		}
+
+	case msg.Y >= headerHeight+4 && msg.Y < headerHeight+4+m.visibleRows():
+		index := msg.Y - headerHeight - 4
+		if sticky := m.stickyRows(); index >= sticky {
+			index += m.offset - sticky
+		}
+		if index >= len(m.processes) || msg.X >= m.tableWidth() {
+			break
+		}
 
-	if len(m.processes) == 0 {
+		now := time.Now()
+		double := index == m.lastClickIndex && now.Sub(m.lastClick) <= doubleClickInterval
+		m.lastClick, m.lastClickIndex = now, index
+		m.selectedIndex = index
+		m.trackSelection()
+		if double {
+			m.lastClick = time.Time{}
+			pid := m.processes[index].PID
+			return func() tea.Msg { return SwitchViewMsg{View: ViewDetails, PID: pid} }
+		}
+	}
+	return nil
+}
+
+// sortFieldAt returns the sort field of the column shown at screen column x,
+// empty if it is not sortable or there is none
+func (m ProcessesModel) sortFieldAt(x int) string {
+	// Past the table's left border and padding
+	left := 2
+	widths := m.calculateColumnWidths()
+	for i, col := range m.tableColumns() {
+		if x >= left && x < left+widths[i] {
+			return col.sort
+		}
+		// Columns are two spaces apart
+		left += widths[i] + 2
+	}
+	return ""
+}
+
+// trackSelection follows the selected process across refreshes while
+// following, and stops tracking otherwise
+func (m *ProcessesModel) trackSelection() {
+	m.trackedPID = 0
+	if m.following && m.selectedIndex < len(m.processes) {
+		m.trackedPID = m.processes[m.selectedIndex].PID
+	}
+}
+
+// visibleRows returns how many process rows fit in the table
+func (m ProcessesModel) visibleRows() int {
+	// Table height less its top border, header, summary row and separator
+	return max(m.height-10, 1)
+}
+
+// scrollToSelection moves the table window so the selection is visible, or
+// centered while following
+func (m *ProcessesModel) scrollToSelection() {
+	// Sticky pinned rows are always shown, above the scrolled rows
+	sticky := m.stickyRows()
+	visible := m.visibleRows() - sticky
+	switch {
+	case m.selectedIndex < sticky:
+	case m.following:
+		m.offset = m.selectedIndex - visible/2
+	case m.selectedIndex < m.offset:
+		m.offset = m.selectedIndex
+	case m.selectedIndex >= m.offset+visible:
+		m.offset = m.selectedIndex - visible + 1
+	}
+	m.offset = max(min(m.offset, len(m.processes)-visible), sticky)
+}
+
+// InputMode reports whether a search term is being typed or a form, prompt
+// or menu is open
+func (m ProcessesModel) InputMode() InputMode {
+	switch {
+	case m.searching:
+		return ModeSearch
+	case m.prompting || m.filterForm.open || m.savedFilters.open || m.signals.open || m.niceMenu.open || m.sortMenu.open || m.columnMenu.open:
+		return ModeForm
+	default:
+		return ModeNormal
+	}
+}
+
+// View renders the processes view
+func (m ProcessesModel) View() string {
+	// Keep serving the previous snapshot while a refresh is in flight. An
+	// empty table is still shown while searching or filtering, so the input
+	// stays visible.
+	if len(m.processes) == 0 && !m.searching && !m.filterForm.open && !m.savedFilters.open && !m.sortMenu.open && !m.columnMenu.open {
+		if m.refreshing {
+			return "Refreshing processes...\n"
+		}
 		return "No processes found.\n"
 	}
 
@@ -192,22 +772,39 @@ func (m ProcessesModel) View() string {
 	// Create status bar
 	statusBar := m.renderStatusBar()
 	
-	// Create table
-	table := lipgloss.JoinVertical(lipgloss.Left, header, separator, rows)
+	// Create table, or the filter form, saved filters or a menu in its place
+	// while open
+	table := lipgloss.JoinVertical(lipgloss.Left, header, m.renderSummaryRow(colWidths), separator, rows)
+	if m.filterForm.open {
+		table = m.filterForm.View()
+	}
+	if m.savedFilters.open {
+		table = m.savedFilters.View()
+	}
+	if m.sortMenu.open {
+		table = m.sortMenu.View(m.sort)
+	}
+	if m.columnMenu.open {
+		table = m.columnMenu.View()
+	}
 	
 	// Ensure table fits in available height and width
 	tableStyle := lipgloss.NewStyle().
 		Height(m.height - 6). // Account for borders, padding, and status bar
 		MaxHeight(m.height - 6).
-		Width(m.width - 4). // Account for borders and padding
-		MaxWidth(m.width - 4)
+		Width(m.tableWidth() - 4). // Account for borders and padding
+		MaxWidth(m.tableWidth() - 4)
 
 	styledTable := tableStyle.
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(0, 1).
 		Render(table)
 
+	if m.showBookmarkSidebar() {
+		styledTable = lipgloss.JoinHorizontal(lipgloss.Top, styledTable, m.renderBookmarks())
+	}
+
 	// Combine table and status bar
 	return lipgloss.JoinVertical(lipgloss.Left, styledTable, statusBar)
 }
@@ -215,19 +812,20 @@ func (m ProcessesModel) View() string {
 // renderTableHeader renders the table header
 func (m ProcessesModel) renderTableHeader() string {
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true).
 		Align(lipgloss.Center)
 
 	// Calculate column widths based on terminal width
 	colWidths := m.calculateColumnWidths()
 	
-	headers := []string{"PID", "Name", "Status", "CPU%", "Memory%", "User", "Threads", "Nice"}
-	
 	var headerCells []string
-	for i, header := range headers {
-		width := colWidths[i]
-		cell := headerStyle.Width(width).Align(lipgloss.Center).Render(header)
+	for i, col := range m.tableColumns() {
+		title := col.title
+		if col.sort == m.sort.Field {
+			title += " " + sortMarker(m.sort.Order)
+		}
+		cell := headerStyle.Width(colWidths[i]).Align(lipgloss.Center).Render(title)
 		headerCells = append(headerCells, cell)
 	}
 
@@ -242,40 +840,80 @@ func (m ProcessesModel) renderTableHeader() string {
 	return lipgloss.JoinHorizontal(lipgloss.Left, spacedCells...)
 }
 
+// renderSummaryRow renders the totals of the listed processes, which are
+// the ones the filters let through, under the column headers: how many
+// there are and the CPU, memory and threads they use together
+func (m ProcessesModel) renderSummaryRow(colWidths []int) string {
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(colors.info).
+		Bold(true)
+
+	var cpu float64
+	var memory uint64
+	var threads int
+	for _, proc := range m.processes {
+		cpu += proc.CPU
+		memory += proc.MemoryBytes
+		threads += int(proc.NumThreads)
+	}
+
+	var cells []string
+	for i, col := range m.tableColumns() {
+		value := ""
+		switch col.id {
+		case "name":
+			value = fmt.Sprintf("Σ %d processes", len(m.processes))
+		case "cpu":
+			value = fmt.Sprintf("%.1f", cpu)
+		case "memory":
+			// In bytes, whichever way the column shows memory
+			value = formatBytes(memory)
+		case models.ColumnThreads:
+			value = strconv.Itoa(threads)
+		}
+
+		// Line the totals up with the figures below, left of their marks
+		width, markWidth := colWidths[i], m.columnMarkWidth(col.id)
+		cells = append(cells, summaryStyle.Width(width-markWidth).Align(col.align).Render(m.truncateString(value, width-markWidth-2))+
+			strings.Repeat(" ", markWidth))
+	}
+	return strings.Join(cells, "  ")
+}
+
 // renderTableRows renders the table rows
 func (m ProcessesModel) renderTableRows() string {
 	var rows []string
 	
 	// Calculate column widths
+	columns := m.tableColumns()
 	colWidths := m.calculateColumnWidths()
 	
-	for i, proc := range m.processes {
+	sticky := m.stickyRows()
+	end := min(m.offset+m.visibleRows()-sticky, len(m.processes))
+	shown := make([]int, 0, m.visibleRows())
+	for i := range sticky {
+		shown = append(shown, i)
+	}
+	for i := m.offset; i < end; i++ {
+		shown = append(shown, i)
+	}
+
+	for _, i := range shown {
+		proc := m.processes[i]
 		rowStyle := lipgloss.NewStyle()
 		if i == m.selectedIndex {
 			rowStyle = rowStyle.
-				Background(lipgloss.Color("62")).
-				Foreground(lipgloss.Color("230"))
-		}
-
-		// Color coding for CPU usage
-		cpuColor := "white"
-		if proc.CPU > 50 {
-			cpuColor = "red"
-		} else if proc.CPU > 20 {
-			cpuColor = "yellow"
-		} else if proc.CPU > 5 {
-			cpuColor = "green"
+				Background(colors.accent).
+				Foreground(colors.text)
 		}
 
-		// Color coding for memory usage
-		memColor := "white"
-		if proc.Memory > 50 {
-			memColor = "red"
-		} else if proc.Memory > 20 {
-			memColor = "yellow"
-		} else if proc.Memory > 5 {
-			memColor = "green"
-		}
+		// Color coding for CPU and memory usage, of the cgroup's limits
+		// where the process has them
+		cpuShare, cpuLimited := cpuShare(proc)
+		cpuColor := usageColor(cpuShare, cpuLimited)
+		memShare, memLimited := memoryShare(proc)
+		memColor := usageColor(memShare, memLimited)
+		previous, changed := m.previous[proc.PID]
 
 		// Color coding for status
 		statusColor := "white"
@@ -286,29 +924,79 @@ func (m ProcessesModel) renderTableRows() string {
 			statusColor = "blue"
 		case "zombie", "Z":
 			statusColor = "red"
-		case "stopped", "T":
+		case "stopped", "stop", "T":
 			statusColor = "yellow"
 		}
 
-		// Truncate and format data based on column widths
-		pidStr := strconv.Itoa(int(proc.PID))
-		name := m.truncateString(proc.Name, colWidths[1]-2)
-		status := m.truncateString(proc.Status, colWidths[2]-2)
-		cpuStr := fmt.Sprintf("%.2f", proc.CPU)
-		memStr := fmt.Sprintf("%.2f", proc.Memory)
-		user := m.truncateString(proc.Username, colWidths[5]-2)
-		threadsStr := strconv.Itoa(int(proc.NumThreads))
-		niceStr := strconv.Itoa(int(proc.Nice))
-
-		cells := []string{
-			rowStyle.Width(colWidths[0]).Align(lipgloss.Right).Render(pidStr),
-			rowStyle.Width(colWidths[1]).Align(lipgloss.Left).Render(name),
-			rowStyle.Width(colWidths[2]).Align(lipgloss.Center).Foreground(lipgloss.Color(statusColor)).Render(status),
-			rowStyle.Width(colWidths[3]).Align(lipgloss.Right).Foreground(lipgloss.Color(cpuColor)).Render(cpuStr),
-			rowStyle.Width(colWidths[4]).Align(lipgloss.Right).Foreground(lipgloss.Color(memColor)).Render(memStr),
-			rowStyle.Width(colWidths[5]).Align(lipgloss.Center).Render(user),
-			rowStyle.Width(colWidths[6]).Align(lipgloss.Right).Render(threadsStr),
-			rowStyle.Width(colWidths[7]).Align(lipgloss.Right).Render(niceStr),
+		var cells []string
+		for j, col := range columns {
+			width := colWidths[j]
+			value := col.value(proc)
+			style := rowStyle.Width(width).Align(col.align)
+			// The change marks following CPU and memory get cells of their
+			// own, so the figures stay aligned
+			mark, markWidth := "", m.columnMarkWidth(col.id)
+			switch col.id {
+			case "name":
+				if m.treeView {
+					row := m.tree[proc.PID]
+					value = row.prefix() + value
+					if row.hidden > 0 {
+						value += fmt.Sprintf(" (+%d)", row.hidden)
+					}
+				}
+				if proc.Virtualization != "" {
+					value += " [" + proc.Virtualization + "]"
+				}
+				if n := m.helpers[proc.PID]; n > 0 {
+					value += fmt.Sprintf(" (+%d helpers)", n)
+				}
+				if i < m.pinnedRows {
+					value = "★ " + value
+				}
+				if m.marked[proc.PID] {
+					value = "✓ " + value
+				}
+			case "status":
+				style = style.Foreground(lipgloss.Color(statusColor)).Bold(isSuspended(proc.Status))
+			case "cpu":
+				if cpuLimited {
+					value = fmt.Sprintf("%.2f*", cpuShare)
+				}
+				mark = m.changeMark(changed, cpuShare-previous.cpu, cpuChangeMin, func(delta float64) string {
+					return fmt.Sprintf("%.1f", delta)
+				})
+				style = style.Foreground(lipgloss.Color(cpuColor))
+			case "memory":
+				if memLimited && !m.memoryBytes {
+					value = fmt.Sprintf("%.2f*", memShare)
+				}
+				if m.memoryBytes {
+					mark = m.changeMark(changed, float64(proc.MemoryBytes)-float64(previous.memoryBytes), memoryBytesChangeMin, func(delta float64) string {
+						return formatBytes(uint64(delta))
+					})
+				} else {
+					mark = m.changeMark(changed, memShare-previous.memory, memoryChangeMin, func(delta float64) string {
+						return fmt.Sprintf("%.2f", delta)
+					})
+				}
+				style = style.Foreground(lipgloss.Color(memColor))
+			case models.ColumnCPUBar:
+				value = renderBar(cpuShare, width-2)
+				style = style.Foreground(lipgloss.Color(cpuColor))
+			case models.ColumnMemoryBar:
+				value = renderBar(memShare, width-2)
+				style = style.Foreground(lipgloss.Color(memColor))
+			}
+			if value == "" {
+				value = "-"
+			}
+			if markWidth > 0 {
+				cells = append(cells, style.Width(width-markWidth).Render(m.truncateString(value, width-markWidth-2))+
+					style.Width(markWidth).Align(lipgloss.Left).Render(mark))
+				continue
+			}
+			cells = append(cells, style.Render(m.truncateString(value, width-2)))
 		}
 
 		// Add spacing between columns
@@ -332,91 +1020,209 @@ func (m ProcessesModel) refreshProcesses() tea.Cmd {
 		if err != nil {
 			return refreshProcessesMsg{Processes: []*models.ProcessInfo{}, Error: err}
 		}
+		return m.listProcesses(processes)
+	}
+}
 
-		// Apply filters
-		filteredProcesses := m.processService.FilterProcesses(processes, m.filter)
-		
-		// Apply sorting
-		m.processService.SortProcesses(filteredProcesses, m.sort)
+// listProcesses filters, groups and sorts processes into a list buffer
+func (m ProcessesModel) listProcesses(processes []*models.ProcessInfo) refreshProcessesMsg {
+	// Apply filters
+	filteredProcesses := m.processService.FilterProcessesInto(m.lists.get(), processes, m.filter)
+	var helpers map[int32]int
+	if m.groupApps && !m.treeView {
+		var grouped []*models.ProcessInfo
+		grouped, helpers = groupHelpers(processes, filteredProcesses)
+		// Grouping copies the list, so its buffer is free again
+		m.lists.put(filteredProcesses)
+		filteredProcesses = grouped
+	}
+
+	// Apply sorting
+	m.processService.SortProcesses(filteredProcesses, m.sort)
 
-		return refreshProcessesMsg{Processes: filteredProcesses}
+	// Keep only the top processes by the active sort on very busy hosts
+	total := len(filteredProcesses)
+	if m.processCap > 0 && total > m.processCap {
+		clear(filteredProcesses[m.processCap:])
+		filteredProcesses = filteredProcesses[:m.processCap]
 	}
+
+	return refreshProcessesMsg{Processes: filteredProcesses, Total: total, Helpers: helpers}
 }
 
-// startRefreshTimer starts the refresh timer
-func (m ProcessesModel) startRefreshTimer() tea.Cmd {
-	return func() tea.Msg {
-		time.Sleep(2 * time.Second)
-		return refreshTimerMsg{}
+// listKey returns what the list worked out from the given scan depends on
+func (m ProcessesModel) listKey(scan uint64) listKey {
+	return listKey{
+		scan:       scan,
+		filter:     *m.filter,
+		sort:       *m.sort,
+		groupApps:  m.groupApps,
+		treeView:   m.treeView,
+		processCap: m.processCap,
 	}
 }
 
+// tickSpinner schedules the next refresh spinner frame
+func (m ProcessesModel) tickSpinner() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
 // killProcess kills the selected process
-func (m ProcessesModel) killProcess(pid int32) tea.Cmd {
+func (m ProcessesModel) killProcess(proc *models.ProcessInfo) tea.Cmd {
 	return func() tea.Msg {
-		err := m.processService.KillProcess(pid)
+		err := m.processService.KillProcess(proc.PID)
 		if err != nil {
-			return killProcessMsg{Error: err}
+			return killProcessMsg{Error: err, PID: proc.PID, Name: proc.Name, Signal: services.SignalKill}
 		}
-		return killProcessMsg{Success: true}
+		return killProcessMsg{Success: true, PID: proc.PID, Name: proc.Name, Signal: services.SignalKill}
 	}
 }
 
-// showFilterDialog shows the filter dialog
-func (m ProcessesModel) showFilterDialog() tea.Cmd {
+// toggleSuspended suspends proc, or resumes it if it is suspended
+func (m ProcessesModel) toggleSuspended(proc *models.ProcessInfo) tea.Cmd {
 	return func() tea.Msg {
-		// Toggle system processes filter
-		m.filter.ShowSystem = !m.filter.ShowSystem
-		// Also toggle the showSystem field for consistency
-		m.showSystem = m.filter.ShowSystem
-		return filterProcessesMsg{Filter: m.filter}
+		if isSuspended(proc.Status) {
+			err := m.processService.ResumeProcess(proc.PID)
+			return signalMsg{PID: proc.PID, Name: proc.Name, Signal: services.SignalCont, Error: err}
+		}
+		err := m.processService.SuspendProcess(proc.PID)
+		return signalMsg{PID: proc.PID, Name: proc.Name, Signal: services.SignalStop, Error: err}
+	}
+}
+
+// isSuspended reports whether status is that of a stopped process
+func isSuspended(status string) bool {
+	return status == "stop" || status == "stopped" || status == "T"
+}
+
+// displayStatus names suspended processes paused, which stands out more
+// than the stop status reported for them
+func displayStatus(status string) string {
+	if isSuspended(status) {
+		return "paused"
+	}
+	return status
+}
+
+// showSearchDialog opens the search input with the current search term
+func (m *ProcessesModel) showSearchDialog() {
+	m.searching = true
+	m.searchBefore = m.filter.SearchTerm
+	m.search.SetValue(m.filter.SearchTerm)
+}
+
+// updateSearch edits the search term, filtering the list as it changes.
+// Enter keeps the term and Esc restores the one from before the search.
+func (m *ProcessesModel) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		return m.refreshProcesses()
+
+	case tea.KeyEsc:
+		m.searching = false
+		if m.filter.SearchTerm == m.searchBefore {
+			return nil
+		}
+		m.setSearchTerm(m.searchBefore)
+		return m.filterLastScan()
 	}
+
+	if !editInput(&m.search, msg) {
+		return nil
+	}
+	m.setSearchTerm(m.search.Value())
+	m.selectedIndex = 0
+	return m.filterLastScan()
+}
+
+// setSearchTerm replaces the filter rather than changing it, as refreshes
+// already running still read the old one
+func (m *ProcessesModel) setSearchTerm(term string) {
+	filter := *m.filter
+	filter.SearchTerm = strings.TrimSpace(term)
+	m.filter = &filter
 }
 
-// showSearchDialog shows the search dialog
-func (m ProcessesModel) showSearchDialog() tea.Cmd {
+// filterLastScan filters and sorts the processes of the latest scan again,
+// so the list follows the search term without waiting for a new scan. When
+// neither the scan nor how it is listed changed since, the list worked out
+// then is reused.
+func (m ProcessesModel) filterLastScan() tea.Cmd {
 	return func() tea.Msg {
-		// Cycle through different search terms for demonstration
-		switch m.filter.SearchTerm {
-		case "":
-			m.filter.SearchTerm = "system"
-		case "system":
-			m.filter.SearchTerm = "chrome"
-		case "chrome":
-			m.filter.SearchTerm = "python"
-		case "python":
-			m.filter.SearchTerm = ""
-		default:
-			m.filter.SearchTerm = ""
+		// Read before the scan, so a scan in between can only cause a miss
+		key := m.listKey(m.processService.LastScanID())
+		if msg, ok := m.lists.cached(key); ok {
+			return msg
+		}
+
+		processes := m.processService.LastProcesses()
+		if processes == nil {
+			return m.refreshProcesses()()
 		}
-		return filterProcessesMsg{Filter: m.filter}
+
+		msg := m.listProcesses(processes)
+		msg.Cached = true
+		m.lists.remember(key, msg)
+		return msg
 	}
 }
 
-// sortByField sorts processes by the specified field
-func (m ProcessesModel) sortByField(field string) {
-	if m.sort.Field == field {
-		// Toggle sort order
-		if m.sort.Order == "asc" {
-			m.sort.Order = "desc"
-		} else {
-			m.sort.Order = "asc"
+// moveSortColumn sorts by the sortable column step columns away from the
+// one sorted by, keeping the order, and reports whether the sort changed.
+// With the sort column hidden, it starts from the first or last column.
+func (m *ProcessesModel) moveSortColumn(step int) bool {
+	var fields []string
+	for _, col := range m.tableColumns() {
+		// The bar columns sort like the figures beside them
+		if col.sort != "" && !slices.Contains(fields, col.sort) {
+			fields = append(fields, col.sort)
 		}
-	} else {
-		m.sort.Field = field
-		m.sort.Order = "desc"
 	}
+	if len(fields) == 0 {
+		return false
+	}
+
+	i := slices.Index(fields, m.sort.Field)
+	switch {
+	case i < 0 && step > 0:
+		i = 0
+	case i < 0:
+		i = len(fields) - 1
+	default:
+		i = (i + step + len(fields)) % len(fields)
+	}
+	if fields[i] == m.sort.Field {
+		return false
+	}
+	m.sort = &models.ProcessSort{Field: fields[i], Order: m.sort.Order}
+	return true
+}
+
+// minColumnWidths returns the minimum width of each visible column
+func (m ProcessesModel) minColumnWidths() []int {
+	var minWidths []int
+	for _, col := range m.tableColumns() {
+		width := col.minWidth
+		if col.sort != "" {
+			// Leave room for the sort marker
+			width = max(width, lipgloss.Width(col.title)+2)
+		}
+		minWidths = append(minWidths, width)
+	}
+	return minWidths
 }
 
 // calculateColumnWidths calculates appropriate column widths based on terminal width
 func (m ProcessesModel) calculateColumnWidths() []int {
-	// Minimum column widths
-	minWidths := []int{8, 20, 10, 8, 8, 12, 8, 6} // PID, Name, Status, CPU%, Memory%, User, Threads, Nice
+	minWidths := m.minColumnWidths()
 	
 	// Available width (account for borders, padding, and spacing between columns)
-	// We have 7 spaces between 8 columns (2 spaces each)
-	spacingWidth := 7 * 2 // 14 spaces total
-	availableWidth := m.width - 4 - spacingWidth // Account for borders and spacing
+	// Columns are separated by 2 spaces each
+	spacingWidth := (len(minWidths) - 1) * 2
+	availableWidth := m.tableWidth() - 4 - spacingWidth // Account for borders and spacing
 	
 	// Calculate total minimum width
 	totalMinWidth := 0
@@ -436,18 +1242,22 @@ func (m ProcessesModel) calculateColumnWidths() []int {
 	colWidths := make([]int, len(minWidths))
 	copy(colWidths, minWidths)
 	
-	// Give extra space to Name column (index 1) and User column (index 5)
-	nameExtra := extraWidth * 3 / 5  // 60% of extra width
-	userExtra := extraWidth * 1 / 5  // 20% of extra width
+	// Give 80% of the extra space to the columns that grow, by their share:
+	// Name, User and Command
+	columns := m.tableColumns()
+	totalGrow := 0
+	for _, col := range columns {
+		totalGrow += col.grow
+	}
+	growExtra := extraWidth * 4 / 5
 	otherExtra := extraWidth * 1 / 5 // 20% of extra width
 	
-	colWidths[1] += nameExtra  // Name
-	colWidths[5] += userExtra  // User
-	
 	// Distribute remaining extra width to other columns
 	remainingExtra := otherExtra
-	for i := range colWidths {
-		if i != 1 && i != 5 && remainingExtra > 0 {
+	for i, col := range columns {
+		if col.grow > 0 {
+			colWidths[i] += growExtra * col.grow / totalGrow
+		} else if remainingExtra > 0 {
 			colWidths[i] += 1
 			remainingExtra--
 		}
@@ -456,13 +1266,219 @@ func (m ProcessesModel) calculateColumnWidths() []int {
 	return colWidths
 }
 
-// truncateString truncates a string to fit within the specified width
+// tableColumn describes a column in the processes table
+type tableColumn struct {
+	id       string // distinguishes the columns styled per process
+	title    string
+	minWidth int
+	grow     int    // share of the spare width the column takes
+	sort     string // sort field ordering by the column, if any
+	align    lipgloss.Position
+	value    func(proc *models.ProcessInfo) string
+}
+
+// cpuShare returns proc's CPU use as a percentage of its cgroup's CPU limit
+// and true when it has one, or else as it is, in percent of one CPU
+func cpuShare(proc *models.ProcessInfo) (float64, bool) {
+	if proc.CgroupCPULimit > 0 {
+		return proc.CPU / proc.CgroupCPULimit, true
+	}
+	return proc.CPU, false
+}
+
+// memoryShare returns proc's resident memory as a percentage of its cgroup's
+// memory limit and true when it has one, or else of physical memory
+func memoryShare(proc *models.ProcessInfo) (float64, bool) {
+	if proc.CgroupMemoryLimit > 0 {
+		return float64(proc.MemoryBytes) / float64(proc.CgroupMemoryLimit) * 100, true
+	}
+	return proc.Memory, false
+}
+
+// usageColor colors a CPU or memory percentage. Use of a cgroup limit turns
+// red as it nears the limit, where CPU is throttled and memory reclaimed or
+// the process killed, while use of the host turns red past half of it.
+func usageColor(percent float64, limited bool) string {
+	switch {
+	case limited && percent >= 90, !limited && percent > 50:
+		return "red"
+	case limited && percent >= 70, !limited && percent > 20:
+		return "yellow"
+	case percent > 5:
+		return "green"
+	}
+	return "white"
+}
+
+// memoryColumn shows memory use as a percentage of physical memory or, with
+// memoryBytes set, as the resident set size in binary units
+func (m ProcessesModel) memoryColumn() tableColumn {
+	if m.memoryBytes {
+		return tableColumn{id: "memory", title: "Memory(RSS)", sort: "memory_bytes", minWidth: 11 + m.columnMarkWidth("memory"), align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return formatBytes(proc.MemoryBytes)
+		}}
+	}
+	// Wide enough for the total in bytes on the summary row too
+	return tableColumn{id: "memory", title: "Memory%", sort: "memory", minWidth: 11 + m.columnMarkWidth("memory"), align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+		return fmt.Sprintf("%.2f", proc.Memory)
+	}}
+}
+
+// sortColumns are the optional columns showing the values of sort fields
+var sortColumns = map[string]string{
+	"pid":      models.ColumnPID,
+	"threads":  models.ColumnThreads,
+	"nice":     models.ColumnNice,
+	"io_read":  models.ColumnIO,
+	"io_write": models.ColumnIO,
+}
+
+// tableTSV returns the listed processes as tab-separated values with a
+// header row, in the columns shown but with nothing truncated, so the table
+// pastes into a spreadsheet one cell per value
+func (m ProcessesModel) tableTSV() string {
+	columns := m.tableColumns()
+	var headers []string
+	for _, col := range columns {
+		headers = append(headers, col.title)
+	}
+
+	// Tabs and line breaks inside a value would split it across cells
+	clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	var b strings.Builder
+	b.WriteString(strings.Join(headers, "\t") + "\n")
+	for _, proc := range m.processes {
+		var cells []string
+		for _, col := range columns {
+			cells = append(cells, clean.Replace(col.value(proc)))
+		}
+		b.WriteString(strings.Join(cells, "\t") + "\n")
+	}
+	return b.String()
+}
+
+// tableColumns returns the columns shown in the processes table: the name,
+// status, CPU, memory and user columns and the optional ones turned on
+func (m ProcessesModel) tableColumns() []tableColumn {
+	var columns []tableColumn
+
+	if m.columns[models.ColumnPID] {
+		columns = append(columns, tableColumn{id: models.ColumnPID, title: "PID", sort: "pid", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return strconv.Itoa(int(proc.PID))
+		}})
+	}
+	if m.columns[models.ColumnPPID] {
+		columns = append(columns, tableColumn{id: models.ColumnPPID, title: "PPID", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return strconv.Itoa(int(proc.PPID))
+		}})
+	}
+
+	columns = append(columns,
+		tableColumn{id: "name", title: "Name", sort: "name", minWidth: 20, grow: 3, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+			return proc.Name
+		}},
+		tableColumn{id: "status", title: "Status", sort: "status", minWidth: 10, align: lipgloss.Center, value: func(proc *models.ProcessInfo) string {
+			return displayStatus(proc.Status)
+		}},
+		tableColumn{id: "cpu", title: "CPU%", sort: "cpu", minWidth: 8 + m.columnMarkWidth("cpu"), align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return fmt.Sprintf("%.2f", proc.CPU)
+		}},
+		m.memoryColumn(),
+	)
+
+	// The bars are drawn as wide as their cells when rendered; their
+	// values here are for copying
+	if m.columns[models.ColumnCPUBar] {
+		columns = append(columns, tableColumn{id: models.ColumnCPUBar, title: "CPU", sort: "cpu", minWidth: 12, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+			share, _ := cpuShare(proc)
+			return renderBar(share, 10)
+		}})
+	}
+	if m.columns[models.ColumnMemoryBar] {
+		columns = append(columns, tableColumn{id: models.ColumnMemoryBar, title: "Memory", sort: "memory", minWidth: 12, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+			share, _ := memoryShare(proc)
+			return renderBar(share, 10)
+		}})
+	}
+
+	columns = append(columns,
+		tableColumn{id: "user", title: "User", sort: "user", minWidth: 12, grow: 1, align: lipgloss.Center, value: func(proc *models.ProcessInfo) string {
+			return proc.Username
+		}},
+	)
+
+	if m.columns[models.ColumnThreads] {
+		columns = append(columns, tableColumn{id: models.ColumnThreads, title: "Threads", sort: "threads", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return strconv.Itoa(int(proc.NumThreads))
+		}})
+	}
+	if m.columns[models.ColumnNice] {
+		columns = append(columns, tableColumn{id: models.ColumnNice, title: "Nice", sort: "nice", minWidth: 6, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return strconv.Itoa(int(proc.Nice))
+		}})
+	}
+	if m.columns[models.ColumnStart] {
+		columns = append(columns, tableColumn{id: models.ColumnStart, title: "Start", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+			return formatStartTime(proc.CreateTime)
+		}})
+	}
+
+	if m.showSession {
+		columns = append(columns,
+			tableColumn{title: "TTY", minWidth: 8, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+				return proc.Terminal
+			}},
+			tableColumn{title: "SID", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+				return strconv.Itoa(int(proc.SessionID))
+			}},
+			tableColumn{title: "PGID", minWidth: 8, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+				return strconv.Itoa(int(proc.ProcessGroupID))
+			}},
+		)
+	}
+
+	if m.columns[models.ColumnIO] {
+		columns = append(columns,
+			tableColumn{title: "IO Read", sort: "io_read", minWidth: 10, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+				if proc.IO == nil {
+					return "-"
+				}
+				return formatBytes(proc.IO.ReadBytes)
+			}},
+			tableColumn{title: "IO Write", sort: "io_write", minWidth: 10, align: lipgloss.Right, value: func(proc *models.ProcessInfo) string {
+				if proc.IO == nil {
+					return "-"
+				}
+				return formatBytes(proc.IO.WriteBytes)
+			}},
+		)
+	}
+
+	if m.showSecurity && m.capabilities.Security.Supported {
+		columns = append(columns, tableColumn{title: "Security", minWidth: 20, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+			return proc.SecurityContext
+		}})
+	}
+
+	if m.columns[models.ColumnCommand] {
+		columns = append(columns, tableColumn{id: models.ColumnCommand, title: "Command", minWidth: 20, grow: 3, align: lipgloss.Left, value: func(proc *models.ProcessInfo) string {
+			return proc.Command
+		}})
+	}
+
+	return columns
+}
+
+// truncateString truncates a string to fit within the specified width,
+// counting runes so tree markers and non-ASCII names are not cut in half
 func (m ProcessesModel) truncateString(s string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return ""
 	}
 	
-	if len(s) <= maxWidth {
+	runes := []rune(s)
+	if len(runes) <= maxWidth {
 		return s
 	}
 	
@@ -470,7 +1486,7 @@ func (m ProcessesModel) truncateString(s string, maxWidth int) string {
 		return "..."
 	}
 	
-	return s[:maxWidth-3] + "..."
+	return string(runes[:maxWidth-3]) + "..."
 }
 
 // renderSeparator renders a separator line between header and rows
@@ -479,7 +1495,7 @@ func (m ProcessesModel) renderSeparator(colWidths []int) string {
 	
 	for _, width := range colWidths {
 		separator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
+			Foreground(colors.dim).
 			Width(width).
 			Render(strings.Repeat("─", width))
 		separatorCells = append(separatorCells, separator)
@@ -496,29 +1512,378 @@ func (m ProcessesModel) renderSeparator(colWidths []int) string {
 	return lipgloss.JoinHorizontal(lipgloss.Left, spacedCells...)
 }
 
+// toggleBookmark bookmarks the selected process in slot, or clears the slot
+// if it already holds that process
+func (m *ProcessesModel) toggleBookmark(slot int) {
+	if len(m.processes) == 0 || m.selectedIndex >= len(m.processes) {
+		return
+	}
+
+	proc := m.processes[m.selectedIndex]
+	if current := m.bookmarks[slot]; current != nil && current.PID == proc.PID {
+		m.bookmarks[slot] = nil
+		return
+	}
+
+	// A process only occupies one slot
+	for i, b := range m.bookmarks {
+		if b != nil && b.PID == proc.PID {
+			m.bookmarks[i] = nil
+		}
+	}
+	m.bookmarks[slot] = &bookmark{PID: proc.PID, Name: proc.Name}
+}
+
+// jumpToBookmark selects the process bookmarked in slot and keeps it selected
+// across refreshes
+func (m *ProcessesModel) jumpToBookmark(slot int) {
+	b := m.bookmarks[slot]
+	if b == nil {
+		return
+	}
+
+	m.trackedPID = b.PID
+	if i := m.indexOfPID(b.PID); i >= 0 {
+		m.selectedIndex = i
+	}
+}
+
+// togglePinned pins the selected process above the others, or unpins it,
+// keeping it selected as it moves
+func (m *ProcessesModel) togglePinned() tea.Cmd {
+	proc := m.processes[m.selectedIndex]
+	if i := slices.Index(m.pinned, proc.PID); i >= 0 {
+		m.pinned = slices.Delete(slices.Clone(m.pinned), i, i+1)
+		m.statusMessage = fmt.Sprintf("Unpinned %s (%d)", proc.Name, proc.PID)
+	} else {
+		m.pinned = append(slices.Clone(m.pinned), proc.PID)
+		m.statusMessage = fmt.Sprintf("Pinned %s (%d)", proc.Name, proc.PID)
+		if m.treeView {
+			m.statusMessage += ", shown on top outside tree view"
+		}
+	}
+	m.trackedPID = proc.PID
+	return m.filterLastScan()
+}
+
+// toggleMarked marks the selected process, or unmarks it, and selects the
+// next one so several can be marked in a row
+func (m *ProcessesModel) toggleMarked() {
+	if len(m.processes) == 0 || m.selectedIndex >= len(m.processes) {
+		return
+	}
+	pid := m.processes[m.selectedIndex].PID
+	marked := maps.Clone(m.marked)
+	if marked == nil {
+		marked = make(map[int32]bool)
+	}
+	if marked[pid] {
+		delete(marked, pid)
+	} else {
+		marked[pid] = true
+	}
+	m.marked = marked
+	if m.selectedIndex < len(m.processes)-1 {
+		m.selectedIndex++
+		m.trackSelection()
+	}
+}
+
+// markedProcesses returns the listed processes that are marked
+func (m ProcessesModel) markedProcesses() []*models.ProcessInfo {
+	var marked []*models.ProcessInfo
+	for _, proc := range m.processes {
+		if m.marked[proc.PID] {
+			marked = append(marked, proc)
+		}
+	}
+	return marked
+}
+
+// pinFirst moves the listed processes whose PIDs are pinned to the front, in
+// pin order, and returns how many it moved
+func pinFirst(processes []*models.ProcessInfo, pinned []int32) ([]*models.ProcessInfo, int) {
+	ordered := make([]*models.ProcessInfo, 0, len(processes))
+	for _, pid := range pinned {
+		if i := slices.IndexFunc(processes, func(p *models.ProcessInfo) bool { return p.PID == pid }); i >= 0 {
+			ordered = append(ordered, processes[i])
+		}
+	}
+	count := len(ordered)
+	for _, proc := range processes {
+		if !slices.Contains(pinned, proc.PID) {
+			ordered = append(ordered, proc)
+		}
+	}
+	return ordered, count
+}
+
+// stickyRows returns how many pinned rows stay at the top of the table as
+// the rest scrolls, leaving at least half the table to scroll
+func (m ProcessesModel) stickyRows() int {
+	return min(m.pinnedRows, m.visibleRows()/2)
+}
+
+// indexOfPID returns the position of pid in the listed processes, or -1
+func (m ProcessesModel) indexOfPID(pid int32) int {
+	for i, proc := range m.processes {
+		if proc.PID == pid {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleCollapsed collapses or expands the selected process in the tree:
+// left collapses it, or selects its parent once collapsed or childless,
+// right expands it and space toggles it
+func (m *ProcessesModel) toggleCollapsed(key string) tea.Cmd {
+	proc := m.processes[m.selectedIndex]
+	row := m.tree[proc.PID]
+	if m.collapsed == nil {
+		m.collapsed = make(map[int32]bool)
+	}
+
+	switch {
+	case key == "right" || (key == " " && m.collapsed[proc.PID]):
+		delete(m.collapsed, proc.PID)
+	case row.children && !m.collapsed[proc.PID]:
+		m.collapsed[proc.PID] = true
+	case key == "left":
+		if i := m.indexOfPID(proc.PPID); i >= 0 {
+			m.selectedIndex = i
+			m.trackSelection()
+		}
+		return nil
+	default:
+		return nil
+	}
+	return m.filterLastScan()
+}
+
+// hasBookmarks reports whether any bookmark slot is in use
+func (m ProcessesModel) hasBookmarks() bool {
+	for _, b := range m.bookmarks {
+		if b != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// showBookmarkSidebar reports whether the bookmarks sidebar is shown. It is
+// left out when the table would no longer fit beside it.
+func (m ProcessesModel) showBookmarkSidebar() bool {
+	if !m.hasBookmarks() {
+		return false
+	}
+
+	minWidths := m.minColumnWidths()
+	tableWidth := 6 + (len(minWidths)-1)*2 // borders, padding and column spacing
+	for _, w := range minWidths {
+		tableWidth += w
+	}
+	return m.width-bookmarkSidebarWidth >= tableWidth
+}
+
+// bookmarkSummary lists the bookmarked slots and PIDs for the status bar
+func (m ProcessesModel) bookmarkSummary() string {
+	var slots []string
+	for i := 1; i <= bookmarkSlots; i++ {
+		if b := m.bookmarks[i%bookmarkSlots]; b != nil {
+			slots = append(slots, fmt.Sprintf("%d:%d", i%bookmarkSlots, b.PID))
+		}
+	}
+	return strings.Join(slots, " ")
+}
+
+// tableWidth returns the width left for the process table beside the bookmarks sidebar
+func (m ProcessesModel) tableWidth() int {
+	if m.showBookmarkSidebar() {
+		return m.width - bookmarkSidebarWidth
+	}
+	return m.width
+}
+
+// renderBookmarks renders the bookmarks sidebar in slot order 1-9, then 0
+func (m ProcessesModel) renderBookmarks() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(colors.text).
+		Bold(true)
+
+	missingStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	// Border and padding take 4 columns, the slot and PID prefix another 10
+	nameWidth := bookmarkSidebarWidth - 4 - 10
+
+	lines := []string{titleStyle.Render("Bookmarks"), ""}
+	for i := 1; i <= bookmarkSlots; i++ {
+		slot := i % bookmarkSlots
+		b := m.bookmarks[slot]
+		if b == nil {
+			continue
+		}
+
+		line := fmt.Sprintf("%7d %s", b.PID, m.truncateString(b.Name, nameWidth))
+		if m.indexOfPID(b.PID) < 0 {
+			// Exited, filtered out or beyond the process cap
+			line = missingStyle.Render(line)
+		}
+		lines = append(lines, keyStyle.Render(strconv.Itoa(slot))+" "+line)
+	}
+
+	return lipgloss.NewStyle().
+		Width(bookmarkSidebarWidth - 2).
+		Height(m.height - 6).
+		MaxHeight(m.height - 6).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
 // renderStatusBar renders the status bar with sort and filter information
 func (m ProcessesModel) renderStatusBar() string {
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Align(lipgloss.Left)
 
 	// Build status text
 	statusText := fmt.Sprintf("Sort: %s (%s)", m.sort.Field, m.sort.Order)
+
+	if m.treeView {
+		statusText += " | Tree"
+	} else if m.groupApps {
+		statusText += " | Helpers grouped"
+	}
 	
 	if m.filter.SearchTerm != "" {
 		statusText += fmt.Sprintf(" | Search: %s", m.filter.SearchTerm)
 	}
 	
+	if criteria := filterCriteria(m.filter); criteria != "" {
+		statusText += " | Filter: " + criteria
+	}
+
+	if m.filter.SessionID != 0 {
+		statusText += fmt.Sprintf(" | Session: %d", m.filter.SessionID)
+	}
+
+	if m.filter.PathPrefix != "" {
+		statusText += fmt.Sprintf(" | Under: %s", m.filter.PathPrefix)
+	}
+
 	if !m.filter.ShowSystem {
 		statusText += " | System processes hidden"
 	}
+
+	if m.following {
+		if m.indexOfPID(m.trackedPID) >= 0 {
+			statusText += fmt.Sprintf(" | Following %d", m.trackedPID)
+		} else {
+			statusText += fmt.Sprintf(" | Following %d (not listed)", m.trackedPID)
+		}
+	}
+
+	if m.pinnedRows > 0 {
+		statusText += fmt.Sprintf(" | Pinned: %d", m.pinnedRows)
+	}
+
+	if len(m.marked) > 0 {
+		statusText += fmt.Sprintf(" | Marked: %d", len(m.marked))
+	}
+
+	if slices.ContainsFunc(m.processes, func(proc *models.ProcessInfo) bool {
+		return proc.CgroupCPULimit > 0 || proc.CgroupMemoryLimit > 0
+	}) {
+		statusText += " | *: % of cgroup limit"
+	}
+
+	// Without room for the sidebar, bookmarks are listed here instead
+	if m.hasBookmarks() && !m.showBookmarkSidebar() {
+		statusText += " | Bookmarks: " + m.bookmarkSummary()
+	}
 	
-	statusText += fmt.Sprintf(" | Processes: %d", len(m.processes))
+	if m.totalProcesses > len(m.processes) {
+		statusText += fmt.Sprintf(" | Showing %d of %s processes", len(m.processes), formatCount(m.totalProcesses))
+	} else {
+		statusText += fmt.Sprintf(" | Processes: %d", len(m.processes))
+	}
+
+	// Data is considered stale once two refresh intervals pass without an update
+	if !m.lastRefresh.IsZero() {
+		age := time.Since(m.lastRefresh)
+		statusText += fmt.Sprintf(" | Updated %s ago", age.Truncate(time.Second))
+		if age > 2*m.refreshRate && !m.paused {
+			statusText += " (stale)"
+			statusStyle = statusStyle.Foreground(colors.warning)
+		}
+	}
+
+	if m.showSecurity && !m.capabilities.Security.Supported {
+		statusText += " | " + renderUnavailable(m.capabilities.Security)
+	}
+
+	if m.paused {
+		statusText = "Paused (Ctrl+P: resume, R: refresh) | " + statusText
+		statusStyle = statusStyle.Foreground(colors.warning)
+	}
+
+	if m.refreshing {
+		statusText = spinnerFrames[m.spinnerFrame] + " Refreshing | " + statusText
+	}
+
+	if m.bookmarking {
+		statusText = "Bookmark: press 0-9 to choose a slot | " + statusText
+	}
+
+	if m.statusMessage != "" {
+		statusText = m.statusMessage + " | " + statusText
+	}
+
+	if m.prompting {
+		statusText = fmt.Sprintf("Kill %s (%d) at or after (e.g. 18:00, 30m): %s█ | Enter: schedule, Esc: cancel",
+			m.promptProcess.Name, m.promptProcess.PID, m.promptInput)
+	}
+
+	if m.signals.open {
+		statusText = m.signals.View()
+	}
+
+	if m.niceMenu.open {
+		statusText = m.niceMenu.View()
+	}
+
+	if m.filterForm.open {
+		statusText = "↑/↓, Tab: choose field | Ctrl+R: clear all | Enter: apply, Esc: cancel"
+	}
+
+	if m.savedFilters.open {
+		statusText = m.savedFilters.Hint()
+	}
+
+	if m.sortMenu.open {
+		statusText = m.sortMenu.Hint()
+	}
+
+	if m.columnMenu.open {
+		statusText = m.columnMenu.Hint()
+	}
+
+	if m.searching {
+		statusText = fmt.Sprintf("Search: %s | %d matching | Enter: apply, Esc: cancel",
+			m.search.View(), m.totalProcesses)
+	}
 
 	return statusStyle.
 		Width(m.width - 4).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(0, 1).
 		Render(statusText)
 }
@@ -526,20 +1891,27 @@ func (m ProcessesModel) renderStatusBar() string {
 // Messages
 type refreshProcessesMsg struct {
 	Processes []*models.ProcessInfo
+	Total     int           // matching processes before the process cap was applied
+	Cached    bool          // filtered from the latest scan rather than a new one
+	Helpers   map[int32]int // helpers folded into each app's row, by app PID
 	Error     error
 }
 
 type refreshTimerMsg struct{}
 
+type spinnerTickMsg struct{}
+
 type killProcessMsg struct {
 	Success bool
 	Error   error
-}
-
-type filterProcessesMsg struct {
-	Filter *models.ProcessFilter
+	PID     int32
+	Name    string
+	Signal  string // SIGTERM, or SIGKILL for a hard kill
+	// Escalated is set when the process ignored SIGTERM and was killed
+	Escalated bool
 }
 
 type SwitchViewMsg struct {
 	View ViewType
+	PID  int32 // process the details view shows, zero to keep its own
 }
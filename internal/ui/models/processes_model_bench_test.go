@@ -0,0 +1,24 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/testutil"
+)
+
+func BenchmarkRenderTable(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		m := *NewProcessesModel(nil, nil, nil, &models.Capabilities{}, models.NewAppConfig())
+		m = m.UpdateSize(200, 60)
+		m.processes = testutil.SyntheticProcesses(n)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m.View()
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// scheduleInterval is how often the scheduled actions view updates its countdowns
+const scheduleInterval = time.Second
+
+// ScheduleModel lists pending and recently finished scheduled actions and
+// how the export schedules last ran
+type ScheduleModel struct {
+	scheduler       *services.Scheduler
+	exportScheduler *services.ExportScheduler
+	attached        bool // a daemon runs the export schedules
+	pending         []models.ScheduledAction
+	finished        []models.ScheduledAction
+	exports         []models.ExportScheduleStatus
+	selectedIndex   int
+	width           int
+	height          int
+}
+
+// NewScheduleModel creates a new schedules model
+func NewScheduleModel(scheduler *services.Scheduler, exportScheduler *services.ExportScheduler) *ScheduleModel {
+	return &ScheduleModel{
+		scheduler:       scheduler,
+		exportScheduler: exportScheduler,
+	}
+}
+
+// Init initializes the model
+func (m ScheduleModel) Init() tea.Cmd {
+	return m.loadActions()
+}
+
+// Update handles messages and updates the model
+func (m ScheduleModel) Update(msg tea.Msg) (ScheduleModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+
+		case "down", "j":
+			if m.selectedIndex < len(m.pending)-1 {
+				m.selectedIndex++
+			}
+
+		case "c", "delete":
+			// Cancel the selected pending action
+			if m.selectedIndex < len(m.pending) {
+				m.scheduler.Cancel(m.pending[m.selectedIndex].ID)
+				cmd = m.loadActions()
+			}
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case scheduleMsg:
+		m.pending = msg.Pending
+		m.finished = msg.Finished
+		m.exports = msg.Exports
+		m.selectedIndex = max(min(m.selectedIndex, len(m.pending)-1), 0)
+
+	case refreshTimerMsg:
+		cmd = m.loadActions()
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	return m, cmd
+}
+
+// UpdateSize updates the model with new dimensions
+func (m ScheduleModel) UpdateSize(width, height int) ScheduleModel {
+	m.width = width
+	m.height = height
+	return m
+}
+
+// View renders the schedules view
+func (m ScheduleModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(colors.err)
+
+	content := titleStyle.Render("Pending Actions:") + "\n"
+	if len(m.pending) == 0 {
+		content += dimStyle.Render("None. Press K on a process to schedule a kill.") + "\n"
+	}
+	for i, action := range m.pending {
+		line := fmt.Sprintf("%-6s %-24s PID %-8d at %s (in %s)",
+			action.Action, action.Name, action.PID, formatActionTime(action.At), time.Until(action.At).Truncate(time.Second))
+		if i == m.selectedIndex {
+			content += selectedStyle.Render(line) + "\n"
+		} else {
+			content += valueStyle.Render(line) + "\n"
+		}
+	}
+
+	content += "\n" + titleStyle.Render("Recent Actions:") + "\n"
+	if len(m.finished) == 0 {
+		content += dimStyle.Render("None") + "\n"
+	}
+	for _, action := range m.finished {
+		outcome := action.State
+		if action.Error != "" {
+			outcome += ": " + action.Error
+		}
+		when := action.RanAt
+		if when.IsZero() {
+			when = action.At
+		}
+		content += dimStyle.Render(fmt.Sprintf("%-6s %-24s PID %-8d %s %s", action.Action, action.Name, action.PID, formatActionTime(when), outcome)) + "\n"
+	}
+
+	content += "\n" + titleStyle.Render("Export Schedules:") + "\n"
+	if len(m.exports) == 0 {
+		content += dimStyle.Render("None. Add export_schedules to the config file.") + "\n"
+	} else if m.attached {
+		content += dimStyle.Render("Run by the daemon; see its log for results") + "\n"
+	}
+	for _, status := range m.exports {
+		content += m.renderExport(status, valueStyle, dimStyle, errorStyle)
+	}
+
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render("Actions run only while tappmanager is open | ↑/↓: select | c: cancel | esc: back")
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(1, 2).
+		Render(fullContent)
+}
+
+// renderExport renders an export schedule and the outcome of its last run
+func (m ScheduleModel) renderExport(status models.ExportScheduleStatus, valueStyle, dimStyle, errorStyle lipgloss.Style) string {
+	schedule := status.Schedule
+	line := valueStyle.Render(fmt.Sprintf("%-20s every %-6s %-4s to %s", schedule.Name, schedule.Every, schedule.Format, exportDestination(schedule.Destination)))
+	if schedule.Filter != "" {
+		line += dimStyle.Render(" filter: " + schedule.Filter)
+	}
+	line += "\n"
+
+	var outcome string
+	switch {
+	case status.NextRun.IsZero():
+		return line + "  " + errorStyle.Render("Invalid: "+status.Error) + "\n"
+	case m.attached:
+		// Only the daemon knows how its runs went
+		return line
+	case status.LastRun.IsZero():
+		outcome = "Not run yet"
+	case status.Error != "":
+		outcome = errorStyle.Render(fmt.Sprintf("Failed at %s: %s", formatActionTime(status.LastRun), status.Error))
+	default:
+		outcome = fmt.Sprintf("Exported %d processes at %s to %s", status.LastCount, formatActionTime(status.LastRun), status.LastTarget)
+	}
+	outcome += fmt.Sprintf(" | next at %s", formatActionTime(status.NextRun))
+	if status.Failures > 0 {
+		outcome += fmt.Sprintf(" | %d of %d runs failed", status.Failures, status.Runs)
+	}
+	return line + "  " + dimStyle.Render(outcome) + "\n"
+}
+
+// exportDestination describes where a scheduled export is sent
+func exportDestination(dest models.ExportDestination) string {
+	switch dest.Type {
+	case models.ExportToDir:
+		return dest.Path
+	case models.ExportToS3:
+		return "s3://" + dest.Bucket + "/" + dest.Prefix
+	case models.ExportToHTTP:
+		return "POST " + dest.URL
+	default:
+		return dest.Type
+	}
+}
+
+// loadActions reads the scheduler's pending and finished actions and the
+// export schedules' statuses
+func (m ScheduleModel) loadActions() tea.Cmd {
+	return func() tea.Msg {
+		return scheduleMsg{
+			Pending:  m.scheduler.Pending(),
+			Finished: m.scheduler.Finished(),
+			Exports:  m.exportScheduler.Statuses(),
+		}
+	}
+}
+
+// Messages
+type scheduleMsg struct {
+	Pending  []models.ScheduledAction
+	Finished []models.ScheduledAction
+	Exports  []models.ExportScheduleStatus
+}
+
+type scheduledActionsMsg struct {
+	Actions []models.ScheduledAction
+}
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// securityReloadInterval is how often the security view reloads its list
+const securityReloadInterval = 5 * time.Second
+
+// Security view tabs
+const (
+	securityTabBinaries = iota
+	securityTabPrivileges
+)
+
+// SecurityModel lists processes running binaries that are not in the hash
+// allowlist, so they can be investigated or allowed, and reports the
+// processes with the riskiest privileges
+type SecurityModel struct {
+	processService *services.ProcessService
+	hashChecker    *services.HashChecker
+	tab            int
+	binaries       []models.UnknownBinary
+	unreadable     int
+	audits         []models.PrivilegeAudit
+	auditErr       error
+	selectedIndex  int
+	offset         int
+	statusMessage  string
+	width          int
+	height         int
+}
+
+// NewSecurityModel creates a new security model
+func NewSecurityModel(processService *services.ProcessService, hashChecker *services.HashChecker) *SecurityModel {
+	return &SecurityModel{
+		processService: processService,
+		hashChecker:    hashChecker,
+	}
+}
+
+// Init initializes the model
+func (m SecurityModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.loadBinaries(),
+		m.loadAudit(),
+	)
+}
+
+// Update handles messages and updates the model
+func (m SecurityModel) Update(msg tea.Msg) (SecurityModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+
+		case "down", "j":
+			if m.selectedIndex < m.rowCount()-1 {
+				m.selectedIndex++
+			}
+
+		case "tab":
+			m.tab = (m.tab + 1) % 2
+			m.selectedIndex = 0
+			m.statusMessage = ""
+
+		case "a":
+			// Trust the selected binary from now on
+			if m.tab == securityTabBinaries && m.selectedIndex < len(m.binaries) {
+				cmd = m.allowBinary(m.binaries[m.selectedIndex])
+			}
+
+		case "r":
+			// Pick up edits to the allowlist file, or audit again
+			if m.tab == securityTabBinaries {
+				cmd = m.reloadAllowlist()
+			} else {
+				cmd = m.loadAudit()
+			}
+
+		case "esc":
+			// Return to processes view
+			cmd = func() tea.Msg { return SwitchViewMsg{View: ViewProcesses} }
+		}
+
+	case securityMsg:
+		m.binaries = msg.Binaries
+		m.unreadable = msg.Unreadable
+		m.selectedIndex = max(min(m.selectedIndex, m.rowCount()-1), 0)
+
+	case privilegeAuditMsg:
+		m.audits = msg.Audits
+		m.auditErr = msg.Error
+		m.selectedIndex = max(min(m.selectedIndex, m.rowCount()-1), 0)
+
+	case refreshTimerMsg:
+		cmd = tea.Batch(m.loadBinaries(), m.loadAudit())
+
+	case allowlistMsg:
+		m.statusMessage = msg.Status
+		if msg.Error != nil {
+			m.statusMessage = msg.Error.Error()
+		}
+		cmd = m.loadBinaries()
+
+	case SwitchViewMsg:
+		// This will be handled by the main model
+	}
+
+	m.scrollToSelection()
+	return m, cmd
+}
+
+// UpdateSize updates the model with new dimensions
+func (m SecurityModel) UpdateSize(width, height int) SecurityModel {
+	m.width = width
+	m.height = height
+	m.scrollToSelection()
+	return m
+}
+
+// rowCount returns how many rows the current tab lists
+func (m SecurityModel) rowCount() int {
+	if m.tab == securityTabPrivileges {
+		return len(m.audits)
+	}
+	return len(m.binaries)
+}
+
+// visibleRows returns how many rows fit in the view
+func (m SecurityModel) visibleRows() int {
+	// Borders, padding, title, header, status and controls
+	return max(m.height-12, 1)
+}
+
+// scrollToSelection keeps the selected row within the visible rows
+func (m *SecurityModel) scrollToSelection() {
+	visible := m.visibleRows()
+	if m.selectedIndex < m.offset {
+		m.offset = m.selectedIndex
+	} else if m.selectedIndex >= m.offset+visible {
+		m.offset = m.selectedIndex - visible + 1
+	}
+	m.offset = max(min(m.offset, m.rowCount()-visible), 0)
+}
+
+// View renders the security view
+func (m SecurityModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	content := titleStyle.Render("Security:")
+	for tab, name := range []string{"Unknown Binaries", "Privileges"} {
+		if tab == m.tab {
+			content += " " + selectedStyle.Render(" "+name+" ")
+		} else {
+			content += " " + dimStyle.Render(" "+name+" ")
+		}
+	}
+	content += "\n"
+
+	var status, help string
+	if m.tab == securityTabPrivileges {
+		switch {
+		case m.auditErr != nil:
+			content += dimStyle.Render(m.auditErr.Error()) + "\n"
+		case len(m.audits) == 0:
+			content += dimStyle.Render("No process runs as root or holds dangerous capabilities.") + "\n"
+		default:
+			content += headerStyle.Render(fmt.Sprintf("%-5s %-8s %-20s %-12s %s", "Risk", "PID", "Name", "User", "Findings")) + "\n"
+			content += m.renderAudits(valueStyle, selectedStyle)
+		}
+		status = fmt.Sprintf("%d processes, riskiest first", len(m.audits))
+		help = "↑/↓: select | tab: unknown binaries | r: audit again | esc: back"
+	} else {
+		switch {
+		case !m.hashChecker.Enabled():
+			content += dimStyle.Render("Hash checks are off. Set hash_allowlist in the config file to enable them.") + "\n"
+		case len(m.binaries) == 0:
+			content += dimStyle.Render("Every running binary that could be read is in the allowlist.") + "\n"
+		default:
+			content += headerStyle.Render(fmt.Sprintf("%-8s %-20s %-12s %-16s %s", "PID", "Name", "User", "SHA-256", "Executable")) + "\n"
+			content += m.renderRows(valueStyle, selectedStyle)
+		}
+		status = fmt.Sprintf("%d not in the allowlist", len(m.binaries))
+		if m.unreadable > 0 {
+			status += fmt.Sprintf(", %d unreadable", m.unreadable)
+		}
+		if m.hashChecker.Enabled() {
+			status += " | Allowlist: " + m.hashChecker.Path()
+		}
+		help = "↑/↓: select | tab: privileges | a: allow binary | r: reload allowlist | esc: back"
+	}
+
+	if m.statusMessage != "" {
+		status += " | " + m.statusMessage
+	}
+	content += "\n" + dimStyle.Render(status) + "\n"
+
+	nav := lipgloss.NewStyle().
+		Foreground(colors.dim).
+		Italic(true).
+		Render(help)
+
+	fullContent := lipgloss.JoinVertical(lipgloss.Left, content, nav)
+
+	return lipgloss.NewStyle().
+		Height(m.height-4).
+		MaxHeight(m.height-4).
+		Width(m.width-4).
+		MaxWidth(m.width-4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.accent).
+		Padding(1, 2).
+		Render(fullContent)
+}
+
+// renderRows renders the visible binaries
+func (m SecurityModel) renderRows(valueStyle, selectedStyle lipgloss.Style) string {
+	end := min(m.offset+m.visibleRows(), len(m.binaries))
+	// Borders and padding take 8 columns, the fixed columns 60
+	executableWidth := max(m.width-68, 10)
+
+	var b strings.Builder
+	for i := m.offset; i < end; i++ {
+		binary := m.binaries[i]
+		executable := binary.Executable
+		if runes := []rune(executable); len(runes) > executableWidth {
+			executable = "..." + string(runes[len(runes)-executableWidth+3:])
+		}
+		line := fmt.Sprintf("%-8d %-20.20s %-12.12s %-16.16s %s",
+			binary.PID, binary.Name, binary.Username, binary.SHA256, executable)
+		if i == m.selectedIndex {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(valueStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderAudits renders the visible privilege findings
+func (m SecurityModel) renderAudits(valueStyle, selectedStyle lipgloss.Style) string {
+	end := min(m.offset+m.visibleRows(), len(m.audits))
+	// Borders and padding take 8 columns, the fixed columns 49
+	findingsWidth := max(m.width-57, 10)
+
+	var b strings.Builder
+	for i := m.offset; i < end; i++ {
+		audit := m.audits[i]
+		findings := strings.Join(audit.Reasons, "; ")
+		if runes := []rune(findings); len(runes) > findingsWidth {
+			findings = string(runes[:findingsWidth-3]) + "..."
+		}
+		line := fmt.Sprintf("%-5d %-8d %-20.20s %-12.12s %s",
+			audit.Risk, audit.PID, audit.Name, audit.Username, findings)
+		if i == m.selectedIndex {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(valueStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// allowBinary adds the hash of binary to the allowlist
+func (m SecurityModel) allowBinary(binary models.UnknownBinary) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.hashChecker.Allow(binary); err != nil {
+			return allowlistMsg{Error: err}
+		}
+		return allowlistMsg{Status: fmt.Sprintf("Allowed %s", binary.Executable)}
+	}
+}
+
+// reloadAllowlist reads the allowlist file again
+func (m SecurityModel) reloadAllowlist() tea.Cmd {
+	return func() tea.Msg {
+		if err := m.hashChecker.Load(); err != nil {
+			return allowlistMsg{Error: err}
+		}
+		return allowlistMsg{Status: "Allowlist reloaded"}
+	}
+}
+
+// loadBinaries reads the binaries currently flagged
+func (m SecurityModel) loadBinaries() tea.Cmd {
+	return func() tea.Msg {
+		return securityMsg{Binaries: m.hashChecker.Unknown(), Unreadable: m.hashChecker.Unreadable()}
+	}
+}
+
+// loadAudit audits the privileges of the processes of the latest scan
+func (m SecurityModel) loadAudit() tea.Cmd {
+	return func() tea.Msg {
+		processes := m.processService.LastProcesses()
+		if processes == nil {
+			var err error
+			if processes, err = m.processService.GetProcesses(); err != nil {
+				return privilegeAuditMsg{Error: err}
+			}
+		}
+		audits, err := services.AuditPrivileges(processes)
+		return privilegeAuditMsg{Audits: audits, Error: err}
+	}
+}
+
+// Messages
+type securityMsg struct {
+	Binaries   []models.UnknownBinary
+	Unreadable int
+}
+
+type privilegeAuditMsg struct {
+	Audits []models.PrivilegeAudit
+	Error  error
+}
+
+type allowlistMsg struct {
+	Status string
+	Error  error
+}
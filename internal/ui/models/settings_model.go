@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"tappmanager/internal/models"
 	"tappmanager/internal/storage"
@@ -54,6 +55,8 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 		if msg.Error == nil {
 			// Convert from internal models to UI models
 			m.config = &AppConfig{
+				SchemaVersion: msg.Config.SchemaVersion,
+
 				RefreshRate: msg.Config.RefreshRate,
 				ShowSystem:  msg.Config.ShowSystem,
 				DefaultSort: ProcessSort{
@@ -71,11 +74,67 @@ func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 					ShowSystem: msg.Config.DefaultFilter.ShowSystem,
 				},
 				AutoRefresh: msg.Config.AutoRefresh,
+				AutoBackup:  msg.Config.AutoBackup,
+				BackupCount: msg.Config.BackupCount,
 				Theme:       msg.Config.Theme,
 				DataDir:     msg.Config.DataDir,
 				Version:     msg.Config.Version,
 				CreatedAt:   msg.Config.CreatedAt,
 				UpdatedAt:   msg.Config.UpdatedAt,
+
+				DStateThreshold: msg.Config.DStateThreshold,
+
+				DuplicateThreshold: msg.Config.DuplicateThreshold,
+				DuplicateExclude:   msg.Config.DuplicateExclude,
+
+				ProcessesRefresh: msg.Config.ProcessesRefresh,
+				DetailsRefresh:   msg.Config.DetailsRefresh,
+				StatsRefresh:     msg.Config.StatsRefresh,
+
+				ProcessCap: msg.Config.ProcessCap,
+
+				HistoryBudget:    msg.Config.HistoryBudget,
+				HistoryRetention: msg.Config.HistoryRetention,
+
+				SnapshotCompression: msg.Config.SnapshotCompression,
+
+				ExportRetention:   msg.Config.ExportRetention,
+				SnapshotRetention: msg.Config.SnapshotRetention,
+				BackupRetention:   msg.Config.BackupRetention,
+				EventRetention:    msg.Config.EventRetention,
+
+				CheckUpdates: msg.Config.CheckUpdates,
+
+				LogFiles:      msg.Config.LogFiles,
+				LogHighlights: msg.Config.LogHighlights,
+
+				Budgets: msg.Config.Budgets,
+
+				ExportSchedules: msg.Config.ExportSchedules,
+
+				CPULimitPercent: msg.Config.CPULimitPercent,
+
+				KillTimeout: msg.Config.KillTimeout,
+
+				IdleThreshold: msg.Config.IdleThreshold,
+
+				ListenerInterval: msg.Config.ListenerInterval,
+
+				HashAllowlist: msg.Config.HashAllowlist,
+
+				ControlSocket: msg.Config.ControlSocket,
+
+				EventLog: msg.Config.EventLog,
+
+				MQTT: msg.Config.MQTT,
+
+				Grafana: msg.Config.Grafana,
+
+				Watch: msg.Config.Watch,
+
+				Columns: msg.Config.Columns,
+
+				ChangeIndicators: msg.Config.ChangeIndicators,
 			}
 		}
 
@@ -96,15 +155,15 @@ func (m SettingsModel) UpdateSize(width, height int) SettingsModel {
 // View renders the settings view
 func (m SettingsModel) View() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Bold(true)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("230"))
+		Foreground(colors.text)
 
 	// Settings content
 	content := titleStyle.Render("Process Manager Settings") + "\n\n"
@@ -112,6 +171,101 @@ func (m SettingsModel) View() string {
 	// Refresh Rate
 	content += labelStyle.Render("Refresh Rate (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.RefreshRate)) + "\n"
 	
+	// Per-view Refresh Intervals
+	content += labelStyle.Render("Processes Refresh (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.ProcessesRefresh)) + "\n"
+	content += labelStyle.Render("Details Refresh (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.DetailsRefresh)) + "\n"
+	content += labelStyle.Render("Stats Refresh (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.StatsRefresh)) + "\n"
+
+	// Process Cap
+	content += labelStyle.Render("Process Cap (0 = all):") + " " + valueStyle.Render(strconv.Itoa(m.config.ProcessCap)) + "\n"
+
+	// History Limits
+	content += labelStyle.Render("History Budget (KiB):") + " " + valueStyle.Render(strconv.Itoa(m.config.HistoryBudget)) + "\n"
+	content += labelStyle.Render("History Retention (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.HistoryRetention)) + "\n"
+
+	// Snapshot Compression
+	content += labelStyle.Render("Snapshot Compression:") + " " + valueStyle.Render(m.config.SnapshotCompression) + "\n"
+
+	// Retention Policies
+	content += labelStyle.Render("Export Retention:") + " " + valueStyle.Render(formatRetention(m.config.ExportRetention)) + "\n"
+	content += labelStyle.Render("Snapshot Retention:") + " " + valueStyle.Render(formatRetention(m.config.SnapshotRetention)) + "\n"
+	content += labelStyle.Render("Backup Retention:") + " " + valueStyle.Render(formatRetention(m.config.BackupRetention)) + "\n"
+	content += labelStyle.Render("Event Retention:") + " " + valueStyle.Render(formatRetention(m.config.EventRetention)) + "\n"
+
+	// Update Check
+	content += labelStyle.Render("Check for Updates:") + " " + valueStyle.Render(fmt.Sprintf("%t", m.config.CheckUpdates)) + "\n"
+
+	// Log Files
+	content += labelStyle.Render("Log Files:") + " " + valueStyle.Render(fmt.Sprintf("%d processes, %d highlight rules", len(m.config.LogFiles), len(m.config.LogHighlights))) + "\n"
+
+	// Resource Budgets
+	content += labelStyle.Render("Resource Budgets:") + " " + valueStyle.Render(strconv.Itoa(len(m.config.Budgets))) + "\n"
+
+	// Export Schedules
+	content += labelStyle.Render("Export Schedules:") + " " + valueStyle.Render(strconv.Itoa(len(m.config.ExportSchedules))) + "\n"
+
+	// CPU Limiter
+	content += labelStyle.Render("CPU Limit:") + " " + valueStyle.Render(fmt.Sprintf("%d%%", m.config.CPULimitPercent)) + "\n"
+
+	// Kill Timeout
+	content += labelStyle.Render("Kill Timeout:") + " " + valueStyle.Render(fmt.Sprintf("%d seconds", m.config.KillTimeout)) + "\n"
+
+	// Idle Detection
+	content += labelStyle.Render("Idle Threshold:") + " " + valueStyle.Render(fmt.Sprintf("%d seconds", m.config.IdleThreshold)) + "\n"
+
+	// Listener Checks
+	listenerChecks := "off"
+	if m.config.ListenerInterval > 0 {
+		listenerChecks = fmt.Sprintf("every %d seconds", m.config.ListenerInterval)
+	}
+	content += labelStyle.Render("Listener Checks:") + " " + valueStyle.Render(listenerChecks) + "\n"
+
+	// Hash Allowlist
+	hashAllowlist := m.config.HashAllowlist
+	if hashAllowlist == "" {
+		hashAllowlist = "off"
+	}
+	content += labelStyle.Render("Hash Allowlist:") + " " + valueStyle.Render(hashAllowlist) + "\n"
+
+	// Control Socket
+	content += labelStyle.Render("Control Socket:") + " " + valueStyle.Render(fmt.Sprintf("%t", m.config.ControlSocket)) + "\n"
+
+	// Event Log
+	eventLog := m.config.EventLog
+	if eventLog == "" {
+		eventLog = "off"
+	}
+	content += labelStyle.Render("Event Log:") + " " + valueStyle.Render(eventLog) + "\n"
+
+	// MQTT
+	broker := m.config.MQTT.Broker
+	if broker == "" {
+		broker = "off"
+	}
+	content += labelStyle.Render("MQTT Broker:") + " " + valueStyle.Render(broker) + "\n"
+
+	// Grafana Annotations
+	grafana := m.config.Grafana.URL
+	if grafana == "" {
+		grafana = "off"
+	}
+	content += labelStyle.Render("Grafana Annotations:") + " " + valueStyle.Render(grafana) + "\n"
+
+	// Watched Processes
+	watched := strings.Join(m.config.Watch, ", ")
+	if watched == "" {
+		watched = "none"
+	}
+	content += labelStyle.Render("Watched Processes:") + " " + valueStyle.Render(watched) + "\n"
+
+	// Processes Table Columns
+	columns := strings.Join(m.config.Columns, ", ")
+	if columns == "" {
+		columns = "none"
+	}
+	content += labelStyle.Render("Optional Columns:") + " " + valueStyle.Render(columns) + "\n"
+	content += labelStyle.Render("Change Indicators:") + " " + valueStyle.Render(m.config.ChangeIndicators) + "\n"
+
 	// Show System Processes
 	content += labelStyle.Render("Show System Processes:") + " " + valueStyle.Render(fmt.Sprintf("%t", m.config.ShowSystem)) + "\n"
 	
@@ -136,6 +290,15 @@ func (m SettingsModel) View() string {
 	// Auto Refresh
 	content += labelStyle.Render("Auto Refresh:") + " " + valueStyle.Render(fmt.Sprintf("%t", m.config.AutoRefresh)) + "\n"
 	
+	// Backups
+	content += labelStyle.Render("Auto Backup:") + " " + valueStyle.Render(fmt.Sprintf("%t (keep %d)", m.config.AutoBackup, m.config.BackupCount)) + "\n"
+
+	// D State Threshold
+	content += labelStyle.Render("D State Threshold (seconds):") + " " + valueStyle.Render(strconv.Itoa(m.config.DStateThreshold)) + "\n"
+
+	// Duplicate Processes
+	content += labelStyle.Render("Duplicate Threshold:") + " " + valueStyle.Render(fmt.Sprintf("more than %d instances, %d names excluded", m.config.DuplicateThreshold, len(m.config.DuplicateExclude))) + "\n"
+
 	// Theme
 	content += labelStyle.Render("Theme:") + " " + valueStyle.Render(m.config.Theme) + "\n"
 	
@@ -153,7 +316,7 @@ func (m SettingsModel) View() string {
 	// Add borders and styling
 	styledContent := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(1, 2).
 		Render(fullContent)
 
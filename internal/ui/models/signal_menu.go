@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// signalMenu lets the user choose a signal to send to a process
+type signalMenu struct {
+	open    bool
+	index   int // into services.Signals
+	process *models.ProcessInfo
+}
+
+// Open shows the menu for proc with SIGTERM chosen
+func (s *signalMenu) Open(proc *models.ProcessInfo) {
+	s.open = true
+	s.index = 0
+	s.process = proc
+}
+
+// Update handles a key while the menu is open and returns the command
+// sending the chosen signal once it is confirmed
+func (s *signalMenu) Update(msg tea.KeyMsg, processService *services.ProcessService) tea.Cmd {
+	switch key := msg.String(); key {
+	case "esc":
+		s.open = false
+	case "left", "h":
+		s.index = (s.index + len(services.Signals) - 1) % len(services.Signals)
+	case "right", "l", "tab":
+		s.index = (s.index + 1) % len(services.Signals)
+	case "enter":
+		s.open = false
+		return sendSignal(processService, s.process, services.Signals[s.index])
+	default:
+		// Signals can also be chosen by their number
+		if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(services.Signals) {
+			s.index = n - 1
+		}
+	}
+	return nil
+}
+
+// View renders the menu on one line, the chosen signal highlighted
+func (s signalMenu) View() string {
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	choices := make([]string, len(services.Signals))
+	for i, signal := range services.Signals {
+		choice := fmt.Sprintf("%d %s", i+1, signal)
+		if i == s.index {
+			choice = selectedStyle.Render(choice)
+		}
+		choices[i] = choice
+	}
+	return fmt.Sprintf("Send to %s (%d): %s | ←/→: choose, Enter: send, Esc: cancel",
+		s.process.Name, s.process.PID, strings.Join(choices, "  "))
+}
+
+// sendSignal sends signal to proc
+func sendSignal(processService *services.ProcessService, proc *models.ProcessInfo, signal string) tea.Cmd {
+	return func() tea.Msg {
+		err := processService.SendSignal(proc.PID, signal)
+		return signalMsg{PID: proc.PID, Name: proc.Name, Signal: signal, Error: err}
+	}
+}
+
+// terminateProcess asks proc to exit with SIGTERM and kills it if it is
+// still running after timeout
+func terminateProcess(processService *services.ProcessService, proc *models.ProcessInfo, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		escalated, err := processService.TerminateGracefully(proc.PID, timeout)
+		return killProcessMsg{Success: err == nil, Error: err, PID: proc.PID, Name: proc.Name, Signal: services.SignalTerm, Escalated: escalated}
+	}
+}
+
+// Status describes the outcome for the status bar
+func (msg killProcessMsg) Status() string {
+	switch {
+	case msg.Error != nil:
+		return fmt.Sprintf("Failed to stop %s: %v", msg.Name, msg.Error)
+	case msg.Escalated:
+		return fmt.Sprintf("Killed %s (PID %d) after it ignored SIGTERM", msg.Name, msg.PID)
+	default:
+		return fmt.Sprintf("Stopped %s (PID %d) with %s", msg.Name, msg.PID, msg.Signal)
+	}
+}
+
+// Status describes the outcome for the status bar
+func (msg signalMsg) Status() string {
+	if msg.Error != nil {
+		return fmt.Sprintf("Failed to send %s to %s: %v", msg.Signal, msg.Name, msg.Error)
+	}
+	switch msg.Signal {
+	case services.SignalStop:
+		return fmt.Sprintf("Suspended %s (PID %d)", msg.Name, msg.PID)
+	case services.SignalCont:
+		return fmt.Sprintf("Resumed %s (PID %d)", msg.Name, msg.PID)
+	default:
+		return fmt.Sprintf("Sent %s to %s (PID %d)", msg.Signal, msg.Name, msg.PID)
+	}
+}
+
+// Messages
+type signalMsg struct {
+	PID    int32
+	Name   string
+	Signal string
+	Error  error
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"fmt"
+
+	"tappmanager/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sortFields are the fields processes can be sorted by, in menu order
+var sortFields = []struct {
+	field string
+	label string
+}{
+	{"cpu", "CPU %"},
+	{"memory", "Memory %"},
+	{"memory_bytes", "Memory (RSS)"},
+	{"pid", "PID"},
+	{"name", "Name"},
+	{"status", "Status"},
+	{"user", "User"},
+	{"threads", "Threads"},
+	{"nice", "Nice"},
+	{"io_read", "Disk read"},
+	{"io_write", "Disk write"},
+}
+
+// sortMenu lists the sortable fields to choose the sort from
+type sortMenu struct {
+	open  bool
+	index int // into sortFields
+}
+
+// Open shows the menu with the current sort field chosen
+func (s *sortMenu) Open(current *models.ProcessSort) {
+	s.open = true
+	s.index = 0
+	for i, f := range sortFields {
+		if f.field == current.Field {
+			s.index = i
+		}
+	}
+}
+
+// Update handles a key while the menu is open. It returns the new sort once
+// one is chosen: Enter sorts by the chosen field, descending, or reverses
+// the order if it is already the sort field, and A and D pick the order.
+func (s *sortMenu) Update(msg tea.KeyMsg, current *models.ProcessSort) *models.ProcessSort {
+	field := sortFields[s.index].field
+	order := ""
+
+	switch msg.String() {
+	case "esc":
+		s.open = false
+
+	case "up", "k":
+		s.index = (s.index + len(sortFields) - 1) % len(sortFields)
+
+	case "down", "j":
+		s.index = (s.index + 1) % len(sortFields)
+
+	case "enter":
+		order = "desc"
+		if field == current.Field && current.Order == "desc" {
+			order = "asc"
+		}
+
+	case "a":
+		order = "asc"
+
+	case "d":
+		order = "desc"
+	}
+
+	if order == "" {
+		return nil
+	}
+	s.open = false
+	return &models.ProcessSort{Field: field, Order: order}
+}
+
+// Hint describes the keys for the status bar
+func (s sortMenu) Hint() string {
+	return "↑/↓: choose field | Enter: sort (again to reverse) | A: ascending | D: descending | Esc: cancel"
+}
+
+// View renders the fields one per line, the current sort marked with its
+// direction
+func (s sortMenu) View(current *models.ProcessSort) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(colors.title).
+		Bold(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(colors.accent).
+		Foreground(colors.text)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(colors.dim)
+
+	content := titleStyle.Render("Sort Processes") + "\n\n"
+	for i, f := range sortFields {
+		line := fmt.Sprintf("%-12s", f.label)
+		if i == s.index {
+			line = selectedStyle.Render(line)
+		}
+		switch {
+		case f.field != current.Field:
+		case current.Order == "asc":
+			line += "  " + dimStyle.Render(sortMarker(current.Order)+" ascending")
+		default:
+			line += "  " + dimStyle.Render(sortMarker(current.Order)+" descending")
+		}
+		content += "  " + line + "\n"
+	}
+	return content
+}
+
+// sortMarker points up for an ascending sort and down for a descending one
+func sortMarker(order string) string {
+	if order == "asc" {
+		return "▲"
+	}
+	return "▼"
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"tappmanager/internal/models"
@@ -11,21 +14,48 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// defaultDStateThreshold is used when the configured D state threshold is unset
+const defaultDStateThreshold = 10 * time.Second
+
 // StatsModel handles the statistics view
 type StatsModel struct {
-	processService *services.ProcessService
-	processes      []*models.ProcessInfo
-	width          int
-	height         int
-	refreshing     bool
+	processService  *services.ProcessService
+	historyService  *services.HistoryService
+	budgetService   *services.BudgetService
+	systemService   *services.SystemService
+	processes       []*models.ProcessInfo
+	systemInfo      *models.SystemInfo
+	capabilities    *models.Capabilities
+	userSortField   string
+	dStateThreshold time.Duration
+	dupThreshold    int
+	dupExclude      []string
+	refreshRate     time.Duration
+	width           int
+	height          int
+	refreshing      bool
 }
 
 // NewStatsModel creates a new stats model
-func NewStatsModel(processService *services.ProcessService) *StatsModel {
+func NewStatsModel(processService *services.ProcessService, historyService *services.HistoryService, budgetService *services.BudgetService, systemService *services.SystemService, config *models.AppConfig, capabilities *models.Capabilities) *StatsModel {
+	dStateThreshold := time.Duration(config.DStateThreshold) * time.Second
+	if dStateThreshold <= 0 {
+		dStateThreshold = defaultDStateThreshold
+	}
+
 	return &StatsModel{
-		processService: processService,
-		processes:      []*models.ProcessInfo{},
-		refreshing:     false,
+		processService:  processService,
+		historyService:  historyService,
+		budgetService:   budgetService,
+		systemService:   systemService,
+		processes:       []*models.ProcessInfo{},
+		capabilities:    capabilities,
+		userSortField:   "cpu",
+		dStateThreshold: dStateThreshold,
+		dupThreshold:    max(config.DuplicateThreshold, 1),
+		dupExclude:      config.DuplicateExclude,
+		refreshRate:     refreshInterval(config.StatsRefresh, defaultStatsRefresh),
+		refreshing:      false,
 	}
 }
 
@@ -33,7 +63,7 @@ func NewStatsModel(processService *services.ProcessService) *StatsModel {
 func (m StatsModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.refreshProcesses(),
-		m.startRefreshTimer(),
+		m.loadSystemInfo(),
 	)
 }
 
@@ -47,8 +77,8 @@ func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
 		case "r":
 			cmd = m.refreshProcesses()
 
-		case "e":
-			cmd = m.exportStats()
+		case "u", "U":
+			m.userSortField = nextUserSortField(m.userSortField)
 
 		case "esc":
 			// Return to processes view
@@ -62,9 +92,10 @@ func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
 	case refreshTimerMsg:
 		cmd = m.refreshProcesses()
 
-	case exportStatsMsg:
-		// Export completed
-		cmd = tea.Printf("Statistics exported: %s", msg.Filename)
+	case systemInfoMsg:
+		if msg.Error == nil {
+			m.systemInfo = msg.Info
+		}
 
 	case SwitchViewMsg:
 		// This will be handled by the main model
@@ -111,7 +142,7 @@ func (m StatsModel) View() string {
 
 	styledContent := contentStyle.
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.accent).
 		Padding(1, 2).
 		Render(fullContent)
 
@@ -121,23 +152,24 @@ func (m StatsModel) View() string {
 // renderStatistics renders the process statistics
 func (m StatsModel) renderStatistics(stats map[string]interface{}) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.title).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Bold(true)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("230"))
+		Foreground(colors.text)
 
 	// Extract statistics
 	totalProcesses := stats["total_processes"].(int)
 	runningProcesses := stats["running_processes"].(int)
 	totalCPU := stats["total_cpu"].(float64)
 	totalMemory := stats["total_memory"].(float64)
+	totalMemoryBytes := stats["total_memory_bytes"].(uint64)
 	statusCounts := stats["status_counts"].(map[string]int)
-	userCounts := stats["user_counts"].(map[string]int)
+	userStats := stats["user_stats"].([]*models.UserStats)
 
 	// Overview
 	overview := titleStyle.Render("Overview:") + "\n"
@@ -146,6 +178,10 @@ func (m StatsModel) renderStatistics(stats map[string]interface{}) string {
 	overview += labelStyle.Render("Stopped Processes:") + " " + valueStyle.Render(fmt.Sprintf("%d", totalProcesses-runningProcesses)) + "\n"
 	overview += labelStyle.Render("Total CPU Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", totalCPU)) + "\n"
 	overview += labelStyle.Render("Total Memory Usage:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", totalMemory)) + "\n"
+	overview += labelStyle.Render("Total Resident Memory:") + " " + valueStyle.Render(formatBytes(totalMemoryBytes)) + "\n"
+	if limit := m.capabilities.Scope.MemoryLimit; limit > 0 {
+		overview += labelStyle.Render("Of Container Memory Limit:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%% of %s", float64(totalMemoryBytes)/float64(limit)*100, formatBytes(limit))) + "\n"
+	}
 
 	// Process Status Distribution
 	statusInfo := "\n" + titleStyle.Render("Process Status Distribution:") + "\n"
@@ -154,21 +190,13 @@ func (m StatsModel) renderStatistics(stats map[string]interface{}) string {
 		statusInfo += labelStyle.Render(status) + ": " + valueStyle.Render(fmt.Sprintf("%d (%.1f%%)", count, percentage)) + "\n"
 	}
 
-	// Top Users by Process Count
-	userInfo := "\n" + titleStyle.Render("Top Users by Process Count:") + "\n"
-	userCount := 0
-	for user, count := range userCounts {
-		if userCount >= 10 {
-			break
-		}
-		percentage := float64(count) / float64(totalProcesses) * 100
-		userInfo += labelStyle.Render(user) + ": " + valueStyle.Render(fmt.Sprintf("%d (%.1f%%)", count, percentage)) + "\n"
-		userCount++
-	}
+	// Per-User Resource Usage
+	userInfo := m.renderUserStats(userStats, titleStyle, labelStyle, valueStyle)
 
 	// Top Processes by CPU and Memory
 	topCPUProcesses := m.getTopProcesses("cpu", 5)
 	topMemoryProcesses := m.getTopProcesses("memory", 5)
+	topMemoryBytesProcesses := m.getTopProcesses("memory_bytes", 5)
 
 	cpuInfo := "\n" + titleStyle.Render("Top 5 Processes by CPU Usage:") + "\n"
 	for i, proc := range topCPUProcesses {
@@ -180,26 +208,318 @@ func (m StatsModel) renderStatistics(stats map[string]interface{}) string {
 		memInfo += fmt.Sprintf("%d. %s (PID: %d) - %.2f%%\n", i+1, proc.Name, proc.PID, proc.Memory)
 	}
 
+	memBytesInfo := "\n" + titleStyle.Render("Top 5 Processes by Resident Memory:") + "\n"
+	for i, proc := range topMemoryBytesProcesses {
+		memBytesInfo += fmt.Sprintf("%d. %s (PID: %d) - %s (%.2f%%)\n", i+1, proc.Name, proc.PID, formatBytes(proc.MemoryBytes), proc.Memory)
+	}
+
+	// Uninterruptible Sleep
+	blockedInfo := m.renderBlockedProcesses(titleStyle, labelStyle, valueStyle)
+
+	// Duplicate Processes
+	duplicateInfo := m.renderDuplicateProcesses(titleStyle, labelStyle, valueStyle)
+
+	// Containers
+	containerInfo := m.renderContainerStats(titleStyle, labelStyle, valueStyle)
+
+	// Resource Budgets
+	budgetInfo := m.renderBudgets(titleStyle, labelStyle, valueStyle)
+
+	// Load Average History
+	loadInfo := m.renderLoadHistory(titleStyle, labelStyle, valueStyle)
+
 	// System Information
-	systemInfo := "\n" + titleStyle.Render("System Information:") + "\n"
-	systemInfo += labelStyle.Render("Current Time:") + " " + valueStyle.Render(time.Now().Format("2006-01-02 15:04:05")) + "\n"
+	systemInfo := m.renderSystemInfo(titleStyle, labelStyle, valueStyle)
 	systemInfo += labelStyle.Render("Process Count:") + " " + valueStyle.Render(fmt.Sprintf("%d", totalProcesses)) + "\n"
 	systemInfo += labelStyle.Render("Average CPU per Process:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", totalCPU/float64(totalProcesses))) + "\n"
 	systemInfo += labelStyle.Render("Average Memory per Process:") + " " + valueStyle.Render(fmt.Sprintf("%.2f%%", totalMemory/float64(totalProcesses))) + "\n"
 
 	// Controls
 	controls := "\n" + titleStyle.Render("Controls:") + "\n"
-	controls += "Ctrl+R - Refresh statistics\n"
-	controls += "Ctrl+E - Export statistics\n"
+	controls += "R - Refresh statistics\n"
+	controls += "Ctrl+E - Export process list\n"
+	controls += "U - Change per-user sort column\n"
 	controls += "Esc - Return to processes view\n"
 
-	return overview + statusInfo + userInfo + cpuInfo + memInfo + systemInfo + controls
+	return overview + statusInfo + userInfo + cpuInfo + memInfo + memBytesInfo + blockedInfo + duplicateInfo + containerInfo + budgetInfo + loadInfo + systemInfo + controls
+}
+
+// userSortFields lists the per-user table columns in the order U cycles through them
+var userSortFields = []string{"cpu", "memory", "processes", "threads", "user"}
+
+// nextUserSortField returns the per-user sort column following field
+func nextUserSortField(field string) string {
+	for i, f := range userSortFields {
+		if f == field {
+			return userSortFields[(i+1)%len(userSortFields)]
+		}
+	}
+	return userSortFields[0]
+}
+
+// renderUserStats renders the per-user resource usage table
+func (m StatsModel) renderUserStats(userStats []*models.UserStats, titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	m.processService.SortUserStats(userStats, m.userSortField)
+
+	userInfo := "\n" + titleStyle.Render(fmt.Sprintf("Per-User Resource Usage (sorted by %s):", m.userSortField)) + "\n"
+	userInfo += labelStyle.Render(fmt.Sprintf("%-16s %8s %8s %8s %8s %12s", "User", "Procs", "Threads", "CPU%", "Memory%", "Resident")) + "\n"
+	for i, us := range userStats {
+		if i >= 10 {
+			break
+		}
+		username := us.Username
+		if len(username) > 16 {
+			username = username[:13] + "..."
+		}
+		userInfo += valueStyle.Render(fmt.Sprintf("%-16s %8d %8d %8.2f %8.2f %12s", username, us.ProcessCount, us.ThreadCount, us.CPU, us.Memory, formatBytes(us.MemoryBytes))) + "\n"
+	}
+
+	return userInfo
+}
+
+// renderBlockedProcesses renders processes stuck in uninterruptible sleep longer than the threshold
+func (m StatsModel) renderBlockedProcesses(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	blocked := m.processService.GetBlockedProcesses(m.processes, m.dStateThreshold)
+
+	blockedInfo := "\n" + titleStyle.Render(fmt.Sprintf("Uninterruptible Sleep (D state > %s):", m.dStateThreshold)) + "\n"
+	if len(blocked) == 0 {
+		return blockedInfo + valueStyle.Render("No stuck processes") + "\n"
+	}
+
+	warnStyle := lipgloss.NewStyle().Foreground(colors.err)
+	showWaitChannel := m.capabilities.WaitChannel.Supported
+	if showWaitChannel {
+		blockedInfo += labelStyle.Render(fmt.Sprintf("%-8s %-20s %10s  %s", "PID", "Name", "Blocked", "Wait Channel")) + "\n"
+	} else {
+		blockedInfo += labelStyle.Render(fmt.Sprintf("%-8s %-20s %10s", "PID", "Name", "Blocked")) + "\n"
+	}
+	for _, bp := range blocked {
+		name := bp.Process.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+		duration := time.Since(bp.BlockedSince).Truncate(time.Second)
+		if !showWaitChannel {
+			blockedInfo += warnStyle.Render(fmt.Sprintf("%-8d %-20s %10s", bp.Process.PID, name, duration)) + "\n"
+			continue
+		}
+		wchan := bp.WaitChannel
+		if wchan == "" {
+			wchan = "-"
+		}
+		blockedInfo += warnStyle.Render(fmt.Sprintf("%-8d %-20s %10s  %s", bp.Process.PID, name, duration, wchan)) + "\n"
+	}
+	if !showWaitChannel {
+		blockedInfo += renderUnavailable(m.capabilities.WaitChannel) + "\n"
+	}
+
+	return blockedInfo
+}
+
+// renderDuplicateProcesses renders command lines running more instances than the threshold
+func (m StatsModel) renderDuplicateProcesses(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	groups := m.processService.GetDuplicateProcesses(m.processes, m.dupThreshold, m.dupExclude)
+
+	duplicateInfo := "\n" + titleStyle.Render(fmt.Sprintf("Duplicate Processes (more than %d instances):", m.dupThreshold)) + "\n"
+	if len(groups) == 0 {
+		return duplicateInfo + valueStyle.Render("No duplicate command lines") + "\n"
+	}
+
+	warnStyle := lipgloss.NewStyle().Foreground(colors.warning)
+	commandWidth := max(m.width-40, 20)
+	for _, group := range groups {
+		var pids []string
+		for _, proc := range group.Processes[:min(len(group.Processes), 10)] {
+			pids = append(pids, strconv.Itoa(int(proc.PID)))
+		}
+		if extra := len(group.Processes) - len(pids); extra > 0 {
+			pids = append(pids, fmt.Sprintf("+%d more", extra))
+		}
+		command := group.Command
+		if len(command) > commandWidth {
+			command = command[:commandWidth-3] + "..."
+		}
+		duplicateInfo += warnStyle.Render(fmt.Sprintf("%dx %s", len(group.Processes), group.Processes[0].Name)) + " " +
+			labelStyle.Render("PIDs:") + " " + valueStyle.Render(strings.Join(pids, ", ")) + "\n"
+		duplicateInfo += "   " + valueStyle.Render(command) + "\n"
+	}
+
+	return duplicateInfo
+}
+
+// renderContainerStats renders the top containers by CPU usage, followed by
+// any other container flagged for weakening isolation from the host.
+// Nothing is rendered when no containerized processes were detected.
+func (m StatsModel) renderContainerStats(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	if !m.capabilities.Containers.Supported {
+		return "\n" + titleStyle.Render("Top Containers by CPU Usage:") + "\n" + renderUnavailable(m.capabilities.Containers) + "\n"
+	}
+
+	containers := m.processService.GetContainerStats(m.processes)
+	if len(containers) == 0 {
+		return ""
+	}
+
+	flagStyle := lipgloss.NewStyle().
+		Foreground(colors.err).
+		Bold(true)
+
+	flagged := 0
+	for _, cs := range containers {
+		if cs.Flagged() {
+			flagged++
+		}
+	}
+	title := fmt.Sprintf("Top Containers by CPU Usage (%d detected):", len(containers))
+	if flagged > 0 {
+		title = fmt.Sprintf("Top Containers by CPU Usage (%d detected, %d flagged):", len(containers), flagged)
+	}
+
+	containerInfo := "\n" + titleStyle.Render(title) + "\n"
+	containerInfo += labelStyle.Render(fmt.Sprintf("%-14s %-12s %8s %8s %8s %12s  %s", "Container", "Runtime", "Procs", "CPU%", "Memory%", "Resident", "Isolation")) + "\n"
+	for i, cs := range containers {
+		if i >= 5 && !cs.Flagged() {
+			continue
+		}
+		containerInfo += valueStyle.Render(fmt.Sprintf("%-14s %-12s %8d %8.2f %8.2f %12s  ", cs.ShortID(), cs.Runtime, cs.ProcessCount, cs.CPU, cs.Memory, formatBytes(cs.MemoryBytes)))
+		containerInfo += flagStyle.Render(containerFlags(cs)) + "\n"
+	}
+
+	return containerInfo
+}
+
+// containerFlags describes how a container weakens isolation from the host
+func containerFlags(cs *models.ContainerStats) string {
+	var flags []string
+	if cs.Privileged {
+		flags = append(flags, "PRIVILEGED")
+	}
+	if len(cs.HostNamespaces) > 0 {
+		processes := "process"
+		if cs.HostNamespaceProcesses != 1 {
+			processes += "es"
+		}
+		flags = append(flags, fmt.Sprintf("host %s (%d %s)", strings.Join(cs.HostNamespaces, ","), cs.HostNamespaceProcesses, processes))
+	}
+	return strings.Join(flags, ", ")
+}
+
+// renderSystemInfo renders host, kernel and CPU information
+func (m StatsModel) renderSystemInfo(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	systemInfo := "\n" + titleStyle.Render("System Information:") + "\n"
+	if m.systemInfo == nil {
+		return systemInfo + valueStyle.Render("Host information unavailable") + "\n"
+	}
+
+	info := m.systemInfo
+	platform := info.Platform
+	if info.PlatformVersion != "" {
+		platform += " " + info.PlatformVersion
+	}
+
+	systemInfo += labelStyle.Render("Hostname:") + " " + valueStyle.Render(info.Hostname) + "\n"
+	systemInfo += labelStyle.Render("Platform:") + " " + valueStyle.Render(fmt.Sprintf("%s (%s)", platform, info.OS)) + "\n"
+	systemInfo += labelStyle.Render("Kernel:") + " " + valueStyle.Render(fmt.Sprintf("%s %s", info.KernelVersion, info.KernelArch)) + "\n"
+	systemInfo += labelStyle.Render("CPU Model:") + " " + valueStyle.Render(fmt.Sprintf("%s (%d cores)", info.CPUModel, info.CPUCores)) + "\n"
+	systemInfo += labelStyle.Render("Boot Time:") + " " + valueStyle.Render(info.BootTime.Format("2006-01-02 15:04:05")) + "\n"
+	systemInfo += labelStyle.Render("Uptime:") + " " + valueStyle.Render(formatUptime(info.Uptime())) + "\n"
+
+	// Inside a container, say what the figures above and the process list cover
+	if scope := m.capabilities.Scope; scope.Container {
+		processes := "the container's only"
+		if scope.HostProcesses {
+			processes = "the host's"
+		}
+		systemInfo += labelStyle.Render("Scope:") + " " + valueStyle.Render(fmt.Sprintf("%s container; processes: %s; CPU, memory and uptime: the host's", scope.Runtime, processes)) + "\n"
+		limits := "none"
+		switch {
+		case scope.CPULimit > 0 && scope.MemoryLimit > 0:
+			limits = fmt.Sprintf("%s CPU, %s", strconv.FormatFloat(scope.CPULimit, 'f', -1, 64), formatBytes(scope.MemoryLimit))
+		case scope.CPULimit > 0:
+			limits = strconv.FormatFloat(scope.CPULimit, 'f', -1, 64) + " CPU"
+		case scope.MemoryLimit > 0:
+			limits = formatBytes(scope.MemoryLimit)
+		}
+		systemInfo += labelStyle.Render("Container Limits:") + " " + valueStyle.Render(limits) + "\n"
+	}
+
+	return systemInfo
+}
+
+// renderBudgets renders today's CPU usage against each resource budget
+func (m StatsModel) renderBudgets(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	if !m.budgetService.HasBudgets() {
+		return ""
+	}
+
+	exceededStyle := lipgloss.NewStyle().
+		Foreground(colors.err).
+		Bold(true)
+
+	budgetInfo := "\n" + titleStyle.Render("CPU Budgets (today):") + "\n"
+	for _, status := range m.budgetService.Statuses(time.Now()) {
+		usage := fmt.Sprintf("%.2f of %.2f CPU-hours", status.UsedCPUHours, status.Budget.CPUHours)
+		if status.Budget.CPUHours > 0 {
+			usage += fmt.Sprintf(" (%.0f%%)", status.UsedCPUHours/status.Budget.CPUHours*100)
+		}
+		budgetInfo += labelStyle.Render(status.Budget.Name+":") + " " + valueStyle.Render(usage)
+		if status.Exceeded {
+			budgetInfo += " " + exceededStyle.Render("exceeded, "+status.Budget.Action)
+		}
+		budgetInfo += "\n"
+	}
+	return budgetInfo
+}
+
+// renderLoadHistory renders the load average and run-queue history charts
+func (m StatsModel) renderLoadHistory(titleStyle, labelStyle, valueStyle lipgloss.Style) string {
+	samples := m.historyService.GetLoadHistory()
+	cores := runtime.NumCPU()
+
+	loadInfo := "\n" + titleStyle.Render(fmt.Sprintf("Load Average History (%d CPU cores):", cores)) + "\n"
+	if len(samples) == 0 {
+		return loadInfo + valueStyle.Render("No load samples recorded yet") + "\n"
+	}
+
+	load1 := make([]float64, len(samples))
+	load5 := make([]float64, len(samples))
+	load15 := make([]float64, len(samples))
+	runQueue := make([]float64, len(samples))
+	for i, sample := range samples {
+		load1[i] = sample.Load1
+		load5[i] = sample.Load5
+		load15[i] = sample.Load15
+		runQueue[i] = float64(sample.ProcsRunning)
+	}
+
+	// Scale to the core count so a full bar means every core is busy,
+	// growing the scale only when the host is overloaded
+	scale := float64(cores)
+	for _, v := range load1 {
+		if v > scale {
+			scale = v
+		}
+	}
+
+	chartWidth := m.width - 40
+	if chartWidth < 10 {
+		chartWidth = 10
+	}
+
+	latest := samples[len(samples)-1]
+	loadInfo += labelStyle.Render(" 1 min:") + " " + valueStyle.Render(renderSparkline(load1, scale, chartWidth)) + " " + valueStyle.Render(fmt.Sprintf("%.2f", latest.Load1)) + "\n"
+	loadInfo += labelStyle.Render(" 5 min:") + " " + valueStyle.Render(renderSparkline(load5, scale, chartWidth)) + " " + valueStyle.Render(fmt.Sprintf("%.2f", latest.Load5)) + "\n"
+	loadInfo += labelStyle.Render("15 min:") + " " + valueStyle.Render(renderSparkline(load15, scale, chartWidth)) + " " + valueStyle.Render(fmt.Sprintf("%.2f", latest.Load15)) + "\n"
+	loadInfo += labelStyle.Render("Run queue:") + " " + valueStyle.Render(renderSparkline(runQueue, scale, chartWidth)) + " " + valueStyle.Render(fmt.Sprintf("%d running, %d blocked", latest.ProcsRunning, latest.ProcsBlocked)) + "\n"
+	loadInfo += labelStyle.Render("Scale:") + " " + valueStyle.Render(fmt.Sprintf("0 - %.2f (%.0f%% of cores)", scale, scale/float64(cores)*100)) + "\n"
+
+	return loadInfo
 }
 
 // renderNavigation renders navigation information
 func (m StatsModel) renderNavigation() string {
 	navStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(colors.dim).
 		Italic(true)
 
 	return navStyle.Render("Statistics updated every 5 seconds")
@@ -229,6 +549,14 @@ func (m StatsModel) getTopProcesses(field string, n int) []*models.ProcessInfo {
 				}
 			}
 		}
+	case "memory_bytes":
+		for i := 0; i < len(processes)-1; i++ {
+			for j := i + 1; j < len(processes); j++ {
+				if processes[i].MemoryBytes < processes[j].MemoryBytes {
+					processes[i], processes[j] = processes[j], processes[i]
+				}
+			}
+		}
 	}
 
 	// Return top N
@@ -250,24 +578,19 @@ func (m StatsModel) refreshProcesses() tea.Cmd {
 	}
 }
 
-// startRefreshTimer starts the refresh timer
-func (m StatsModel) startRefreshTimer() tea.Cmd {
+// loadSystemInfo loads host information for the System Information panel
+func (m StatsModel) loadSystemInfo() tea.Cmd {
 	return func() tea.Msg {
-		time.Sleep(5 * time.Second)
-		return refreshTimerMsg{}
-	}
-}
-
-// exportStats exports the current statistics
-func (m StatsModel) exportStats() tea.Cmd {
-	return func() tea.Msg {
-		// This would integrate with the storage service to export statistics
-		filename := fmt.Sprintf("process_stats_%s.txt", time.Now().Format("20060102_150405"))
-		return exportStatsMsg{Filename: filename}
+		info, err := m.systemService.GetSystemInfo()
+		if err != nil {
+			return systemInfoMsg{Error: err}
+		}
+		return systemInfoMsg{Info: info}
 	}
 }
 
 // Messages
-type exportStatsMsg struct {
-	Filename string
+type systemInfoMsg struct {
+	Info  *models.SystemInfo
+	Error error
 }
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTheme is the theme used when none or an unknown one is configured
+const DefaultTheme = "default"
+
+// palette holds the colors the views are drawn with
+type palette struct {
+	dim     lipgloss.Color // labels, hints and secondary text
+	text    lipgloss.Color // values, and text on the accent
+	accent  lipgloss.Color // borders and the selected row
+	title   lipgloss.Color // titles and headers
+	err     lipgloss.Color
+	warning lipgloss.Color
+	ok      lipgloss.Color
+	info    lipgloss.Color
+}
+
+// themes are the palettes the theme setting and --theme choose from
+var themes = map[string]palette{
+	DefaultTheme: {
+		dim: "240", text: "230", accent: "62", title: "205",
+		err: "196", warning: "220", ok: "42", info: "117",
+	},
+	"nord": {
+		dim: "#4C566A", text: "#ECEFF4", accent: "#5E81AC", title: "#88C0D0",
+		err: "#BF616A", warning: "#EBCB8B", ok: "#A3BE8C", info: "#81A1C1",
+	},
+	"dracula": {
+		dim: "#6272A4", text: "#F8F8F2", accent: "#BD93F9", title: "#FF79C6",
+		err: "#FF5555", warning: "#F1FA8C", ok: "#50FA7B", info: "#8BE9FD",
+	},
+	"gruvbox": {
+		dim: "#928374", text: "#EBDBB2", accent: "#458588", title: "#FE8019",
+		err: "#FB4934", warning: "#FABD2F", ok: "#B8BB26", info: "#83A598",
+	},
+}
+
+// colors is the palette of the current theme. Views build their styles
+// from it when rendering, so a theme applies from the next frame.
+var colors = themes[DefaultTheme]
+
+// Themes returns the names of the available themes, sorted
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsTheme reports whether name is an available theme
+func IsTheme(name string) bool {
+	_, ok := themes[name]
+	return ok
+}
+
+// applyTheme switches to the named theme, or to the default one if there
+// is no such theme
+func applyTheme(name string) {
+	if p, ok := themes[name]; ok {
+		colors = p
+		return
+	}
+	colors = themes[DefaultTheme]
+}
@@ -12,6 +12,7 @@ import (
 type UIApp struct {
 	app           *app.App
 	processService *services.ProcessService
+	model         *models.MainModel
 	program       *tea.Program
 }
 
@@ -22,22 +23,30 @@ func NewUIApp(app *app.App) *UIApp {
 	
 	// Create process service
 	processService := services.NewProcessService(storage)
+	historyService := services.NewHistoryService()
+	systemService := services.NewSystemService()
+	capabilityService := services.NewCapabilityService()
+	diagnosticsService := services.NewDiagnosticsService(processService, historyService, storage)
 	
 	// Create main model
-	model := models.NewMainModel(storage, processService)
+	model := models.NewMainModel(storage, processService, historyService, systemService, capabilityService, diagnosticsService)
 	
 	// Create Bubble Tea program
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	
 	return &UIApp{
 		app:           app,
 		processService: processService,
+		model:         model,
 		program:       program,
 	}
 }
 
 // Run starts the UI application
 func (u *UIApp) Run() error {
+	// Continue any processes the CPU limiter stopped, however the program ends
+	defer u.model.Close()
+
 	// Run the Bubble Tea program
 	if _, err := u.program.Run(); err != nil {
 		return err
@@ -0,0 +1,65 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information, set at build time with
+//
+//	go build -ldflags "-X tappmanager/internal/version.Version=v1.2.0 -X tappmanager/internal/version.Commit=abc1234 -X tappmanager/internal/version.BuildDate=2024-01-01T00:00:00Z"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information, falling back to the VCS details the Go
+// toolchain embeds when the ldflags were not set
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	if len(info.Commit) > 7 {
+		info.Commit = info.Commit[:7]
+	}
+	return info
+}
+
+// String returns the version with its commit, e.g. "v1.2.0 (abc1234)"
+func (i Info) String() string {
+	if i.Commit == "" {
+		return i.Version
+	}
+	return fmt.Sprintf("%s (%s)", i.Version, i.Commit)
+}
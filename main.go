@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 
@@ -12,25 +13,123 @@ import (
 )
 
 func main() {
+	// Flags are applied before the config is loaded so they take precedence
+	flags := parseFlags()
+	if err := flags.applyConfigOverrides(); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+	options, err := flags.startupOptions()
+	if err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+
 	// Create application
 	application, err := app.NewApp()
 	if err != nil {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
+	// Subcommands run without starting the UI
+	if flag.NArg() > 0 {
+		if err := runCommand(application, flag.Args()); err != nil {
+			log.Fatalf("Command failed: %v", err)
+		}
+		return
+	}
+
 	// Create storage and process service
 	storage := application.GetStorage()
 	processService := services.NewProcessService(storage)
+	historyService := services.NewHistoryService()
+	systemService := services.NewSystemService()
+	capabilityService := services.NewCapabilityService()
+	diagnosticsService := services.NewDiagnosticsService(processService, historyService, storage)
+	eventLog := openEventLog(application, processService)
+	recordEventHistory(application, processService)
+	publisher := startMQTTPublisher(application, processService)
+	if publisher != nil {
+		diagnosticsService.SetMQTTPublisher(publisher)
+	}
+	annotator := startGrafanaAnnotator(application, processService)
+	if annotator != nil {
+		diagnosticsService.SetGrafanaAnnotator(annotator)
+	}
+
+	// Use the processes and history of a running daemon, or else let other
+	// invocations send commands to this instance
+	link, daemon := linkToDaemon(application, processService, historyService)
 
 	// Create main model
-	model := models.NewMainModel(storage, processService)
+	model := models.NewMainModel(storage, processService, historyService, systemService, capabilityService, diagnosticsService)
+	if daemon != nil {
+		model.SetDaemon(daemon.PID)
+	}
+
+	if err := model.ApplyStartupOptions(options); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
 
 	// Create Bubble Tea program
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
-	// Run the program
-	if _, err := program.Run(); err != nil {
+	// Run the program, then continue any processes the CPU limiter stopped
+	_, err = program.Run()
+	model.Close()
+	link.Close()
+	if publisher != nil {
+		publisher.Stop()
+	}
+	if annotator != nil {
+		annotator.Stop()
+	}
+	if eventLog != nil {
+		eventLog.Close()
+	}
+	if err != nil {
 		log.Fatalf("Application error: %v", err)
 		os.Exit(1)
 	}
 }
+
+// openEventLog connects processService to the system log named in the
+// config. A log that cannot be opened is reported and left off.
+func openEventLog(application *app.App, processService *services.ProcessService) *services.EventLog {
+	eventLog, err := services.NewEventLog(application.GetConfig().EventLog)
+	if err != nil {
+		log.Printf("Event log disabled: %v", err)
+		return nil
+	}
+	if eventLog != nil {
+		processService.AddEventSink(eventLog)
+	}
+	return eventLog
+}
+
+// recordEventHistory keeps the events of processService in the data
+// directory, where reports read them from
+func recordEventHistory(application *app.App, processService *services.ProcessService) {
+	config := application.GetConfig()
+	processService.AddEventSink(services.NewEventHistory(config.DataDir, config.SnapshotCompression))
+}
+
+// startMQTTPublisher starts publishing summaries and the events of
+// processService to the MQTT broker in the config, if one is set
+func startMQTTPublisher(application *app.App, processService *services.ProcessService) *services.MQTTPublisher {
+	publisher := services.NewMQTTPublisher(processService, application.GetConfig().MQTT)
+	if publisher != nil {
+		processService.AddEventSink(publisher)
+		publisher.Start()
+	}
+	return publisher
+}
+
+// startGrafanaAnnotator starts pushing the events of processService to
+// Grafana as annotations, if a Grafana URL is set in the config
+func startGrafanaAnnotator(application *app.App, processService *services.ProcessService) *services.GrafanaAnnotator {
+	annotator := services.NewGrafanaAnnotator(processService, application.GetConfig().Grafana)
+	if annotator != nil {
+		processService.AddEventSink(annotator)
+		annotator.Start()
+	}
+	return annotator
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"tappmanager/internal/app"
+	"tappmanager/internal/control"
+	"tappmanager/internal/models"
+	"tappmanager/internal/services"
+)
+
+// reportFormats maps output file extensions to report formats
+var reportFormats = map[string]string{
+	".md":   services.ReportMarkdown,
+	".html": services.ReportHTML,
+	".htm":  services.ReportHTML,
+	".pdf":  services.ReportPDF,
+}
+
+// runReport runs the report subcommand: it builds a report of the chosen
+// sections and writes it as Markdown, HTML or PDF to a file or stdout
+func runReport(application *app.App, args []string) error {
+	config := application.GetConfig()
+
+	var sections, watch, format, output string
+	opts := services.ReportOptions{
+		DataDir:     config.DataDir,
+		LoadHistory: control.NewClient(app.ControlSocketPath()),
+	}
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.StringVar(&sections, "sections", "all", "comma-separated sections: "+strings.Join(models.ReportSections, ", ")+", or all")
+	flags.IntVar(&opts.TopN, "top", 10, "processes in each top list")
+	flags.DurationVar(&opts.Window, "window", 24*time.Hour, "how far back the load and alerts sections go")
+	flags.StringVar(&watch, "watch", "", "comma-separated process names to watch besides those in the config")
+	flags.StringVar(&format, "format", "", "markdown, html or pdf (default from the output file's extension, else markdown)")
+	flags.StringVar(&output, "output", "", "file to write the report to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if opts.Sections, err = services.ParseReportSections(sections); err != nil {
+		return err
+	}
+	if opts.TopN < 1 {
+		return fmt.Errorf("-top must be at least 1")
+	}
+	if opts.Window <= 0 {
+		return fmt.Errorf("-window must be positive")
+	}
+	opts.Watch = slices.Clone(config.Watch)
+	for _, name := range strings.Split(watch, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Watch = append(opts.Watch, name)
+		}
+	}
+	if format == "" {
+		format = services.ReportMarkdown
+		if f, ok := reportFormats[strings.ToLower(filepath.Ext(output))]; ok {
+			format = f
+		}
+	}
+	if !slices.Contains([]string{services.ReportMarkdown, services.ReportHTML, services.ReportPDF}, format) {
+		return fmt.Errorf("unknown report format %q (expected markdown, html or pdf)", format)
+	}
+
+	storage := application.GetStorage()
+	report, err := services.BuildReport(services.NewProcessService(storage), services.NewSystemService(), storage, opts)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		return services.WriteReport(os.Stdout, report, format)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := services.WriteReport(file, report, format); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("Report written to %s\n", output)
+	return nil
+}